@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"tpwfc/internal/config"
+	"tpwfc/internal/crawler"
+	"tpwfc/internal/crawler/parsers"
+)
+
+// maxListedEvents caps how many missing/extra event IDs are printed per mirror.
+const maxListedEvents = 5
+
+// runAudit fetches every configured location of each source and reports drift between them.
+// It returns false if any source has a stale, diverged or unreachable mirror.
+func runAudit(timeout time.Duration, sources []config.SourceConfig, scraper *crawler.Scraper, parser *parsers.Parser) bool {
+	ctx, cancel := newRunContext(timeout)
+	defer cancel()
+
+	fmt.Printf("🔎 Auditing mirrors of %d sources...\n", len(sources))
+
+	allInSync := true
+
+	for i, src := range sources {
+		if ctx.Err() != nil {
+			fmt.Printf("\n⚠️  Audit interrupted: %v\n", ctx.Err())
+
+			return false
+		}
+
+		fmt.Printf("\n----------------------------------------------------------------\n")
+		fmt.Printf("📦 Source %d/%d: %s (%s/%s)\n", i+1, len(sources), src.Name, src.FireID, src.Language)
+
+		audit := crawler.AuditSource(ctx, scraper, parser, src)
+		printAudit(audit)
+
+		if !audit.InSync() {
+			allInSync = false
+		}
+	}
+
+	if allInSync {
+		fmt.Println("\n✅ All mirrors in sync")
+	} else {
+		fmt.Println("\n⚠️  Some mirrors are stale or diverged")
+	}
+
+	return allInSync
+}
+
+func printAudit(audit *crawler.SourceAudit) {
+	if len(audit.Mirrors) < 2 {
+		fmt.Println("   ℹ️  Only one location configured, nothing to compare")
+	}
+
+	for _, m := range audit.Mirrors {
+		icon := "✅"
+
+		switch m.Status {
+		case crawler.MirrorReference:
+			icon = "⭐"
+		case crawler.MirrorStale:
+			icon = "🕰️ "
+		case crawler.MirrorDiverged:
+			icon = "⚠️ "
+		case crawler.MirrorUnreachable:
+			icon = "❌"
+		}
+
+		fmt.Printf("   %s [%s] %s: %s\n", icon, m.Kind, m.Status, m.Location)
+
+		if m.Err != nil {
+			fmt.Printf("      error: %v\n", m.Err)
+
+			continue
+		}
+
+		lastModify := "unsigned"
+		if !m.LastModify.IsZero() {
+			lastModify = m.LastModify.Format("2006-01-02T15:04:05Z07:00")
+		}
+
+		fmt.Printf("      last modify: %s, events: %d, hash: %s\n", lastModify, len(m.EventIDs), shortHash(m.ContentHash))
+
+		if len(m.MissingEvents) > 0 {
+			fmt.Printf("      missing %d events: %s\n", len(m.MissingEvents), listIDs(m.MissingEvents))
+		}
+
+		if len(m.ExtraEvents) > 0 {
+			fmt.Printf("      extra %d events: %s\n", len(m.ExtraEvents), listIDs(m.ExtraEvents))
+		}
+	}
+}
+
+func shortHash(hash string) string {
+	if len(hash) > 12 {
+		return hash[:12]
+	}
+
+	return hash
+}
+
+func listIDs(ids []string) string {
+	if len(ids) > maxListedEvents {
+		return strings.Join(ids[:maxListedEvents], ", ") + ", ..."
+	}
+
+	return strings.Join(ids, ", ")
+}
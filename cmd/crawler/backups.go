@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+
+	"tpwfc/internal/config"
+	"tpwfc/internal/crawler"
+)
+
+// runBackups lists the backups of listPath and/or restores restorePath from the chosen backup.
+// Restoring keeps as many backups as the config (if given) would. It returns false on failure.
+func runBackups(configFile, listPath, restorePath, choice string) bool {
+	if listPath != "" {
+		backups, err := crawler.ListBackups(listPath)
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+
+			return false
+		}
+
+		fmt.Printf("💾 Backups of %s: %d\n", listPath, len(backups))
+
+		for _, b := range backups {
+			fmt.Printf("  %s  %8d bytes  %s\n", b.CreatedAt.Format("2006-01-02 15:04:05Z"), b.Size, b.Path)
+		}
+	}
+
+	if restorePath == "" {
+		return true
+	}
+
+	keep := config.DefaultBackupRetention
+
+	if configFile != "" {
+		cfg, err := config.LoadConfig(configFile)
+		if err != nil {
+			fmt.Printf("❌ Failed to load config: %v\n", err)
+
+			return false
+		}
+
+		if retention := cfg.GetBackupRetention(); retention > 0 {
+			keep = retention
+		}
+	}
+
+	restored, err := crawler.RestoreBackup(restorePath, choice, keep)
+	if err != nil {
+		fmt.Printf("❌ Restore failed: %v\n", err)
+
+		return false
+	}
+
+	fmt.Printf("♻️  Restored %s from backup %s (%s)\n", restorePath, restored.Path, restored.CreatedAt.Format("2006-01-02 15:04:05Z"))
+
+	return true
+}
@@ -0,0 +1,692 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"tpwfc/internal/config"
+	"tpwfc/internal/crawler"
+	"tpwfc/internal/crawler/parsers"
+	"tpwfc/internal/validator"
+)
+
+// Source crawl outcomes.
+const (
+	statusSaved   = crawler.ReportStatusSaved
+	statusSkipped = crawler.ReportStatusSkipped
+	statusFailed  = crawler.ReportStatusFailed
+)
+
+// Source crawl errors.
+var (
+	errFetchFailed      = errors.New("all fetch attempts failed")
+	errStrictValidation = errors.New("validation failed in strict mode")
+	errCrawlCancelled   = errors.New("crawl cancelled")
+)
+
+// crawlEnv holds the components shared by every source crawl.
+// Scraper, parser, client and validator are safe for concurrent use.
+type crawlEnv struct {
+	cfg            *config.Config
+	scraper        *crawler.Scraper
+	parser         *parsers.Parser
+	client         *crawler.Client
+	validator      *validator.MarkdownValidator
+	snapshots      *crawler.SnapshotStore
+	outputOverride string
+	verifyMode     string
+	showValidation bool
+}
+
+// sourceResult captures the outcome of crawling a single source.
+type sourceResult struct {
+	err          error
+	log          *bytes.Buffer
+	verification *crawler.Verification
+	validation   *crawler.ValidationSummary
+	valResult    *validator.ValidationResult
+	diagnostics  parsers.Diagnostics
+	source       config.SourceConfig
+	status       string
+	outputPath   string
+	fetchedFrom  string
+	fetchedKind  string
+	snapshotHash string
+	fileType     string
+	rejectsPath  string
+	commit       string
+	attempts     []crawler.AttempResult
+	index        int
+	events       int
+	rejected     int
+	bytes        int
+	cached       bool
+}
+
+// sourceLog writes progress output for one source.
+type sourceLog struct {
+	w io.Writer
+}
+
+func (l sourceLog) printf(format string, args ...any) {
+	_, _ = fmt.Fprintf(l.w, format, args...)
+}
+
+func (l sourceLog) println(args ...any) {
+	_, _ = fmt.Fprintln(l.w, args...)
+}
+
+// crawlAll processes sources with a bounded worker pool and returns results in source order.
+// With a single worker, progress is streamed to stdout as it happens; otherwise each
+// source's output is buffered and printed in order once all sources have finished.
+// Sources not yet started when ctx is done are reported as skipped.
+func (env *crawlEnv) crawlAll(ctx context.Context, sources []config.SourceConfig, workers int) []sourceResult {
+	results := make([]sourceResult, len(sources))
+
+	if workers <= 1 {
+		for i, src := range sources {
+			results[i] = env.crawlSource(ctx, i, len(sources), src, os.Stdout)
+		}
+
+		return results
+	}
+
+	var (
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, workers)
+	)
+
+	for i, src := range sources {
+		wg.Add(1)
+
+		go func(index int, source config.SourceConfig) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			buf := &bytes.Buffer{}
+			result := env.crawlSource(ctx, index, len(sources), source, buf)
+			result.log = buf
+			results[index] = result
+		}(i, src)
+	}
+
+	wg.Wait()
+
+	for _, result := range results {
+		if result.log != nil {
+			_, _ = io.Copy(os.Stdout, result.log)
+		}
+	}
+
+	return results
+}
+
+// crawlSource fetches, validates, parses and saves a single source.
+func (env *crawlEnv) crawlSource(ctx context.Context, index, total int, sourceConfig config.SourceConfig, w io.Writer) (result sourceResult) {
+	out := sourceLog{w: w}
+	cfg := env.cfg
+	result = sourceResult{index: index, source: sourceConfig, status: statusFailed}
+
+	if err := ctx.Err(); err != nil {
+		result.status = statusSkipped
+		result.err = fmt.Errorf("%w: %w", errCrawlCancelled, err)
+
+		return result
+	}
+
+	out.printf("\n----------------------------------------------------------------\n")
+	out.printf("📦 Source %d/%d: %s (%s/%s)\n", index+1, total, sourceConfig.Name, sourceConfig.FireID, sourceConfig.Language)
+
+	var markdown, fireID, language, fetchedFrom string
+
+	var err error
+
+	if sourceConfig.Type == config.SourceTypeGit {
+		fireID, language = sourceConfig.FireID, sourceConfig.Language
+		markdown, fetchedFrom, err = env.readGitSource(ctx, &result, sourceConfig, out)
+	} else {
+		markdown, fireID, language, fetchedFrom, err = env.fetchSource(ctx, &result, sourceConfig, out)
+	}
+
+	if err != nil {
+		out.printf("⚠️  Skipping source %s due to fetch failure\n", sourceConfig.Name)
+
+		result.err = err
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			result.status = statusSkipped
+			result.err = fmt.Errorf("%w: %w", errCrawlCancelled, ctxErr)
+		}
+
+		return result
+	}
+
+	result.fetchedFrom = fetchedFrom
+	result.bytes = len(markdown)
+
+	// Archive the raw body before anything can reject it
+	if env.snapshots != nil {
+		result.snapshotHash = env.storeSnapshot(markdown, fetchedFrom, fireID, language, out)
+	}
+
+	// Check the signed metadata before trusting the content
+	if env.verifyMode != config.VerifyOff {
+		if err = env.verifySource(&result, markdown, fireID, language, out); err != nil {
+			result.status = statusSkipped
+			result.err = err
+
+			return result
+		}
+	}
+
+	handler, err := env.parser.FileTypeHandler(markdown)
+	if err != nil {
+		out.printf("❌ %v\n", err)
+
+		result.err = err
+
+		return result
+	}
+
+	// Name the output after the detected type, so an untagged detailed timeline cannot
+	// overwrite the FIRE_TIMELINE output
+	result.fileType = handler.FileType
+
+	// Only timelines have a timeline table to validate and reject rows from
+	if handler.FileType != parsers.FileTypeTimeline {
+		env.saveDocument(&result, handler, markdown, fireID, language, out)
+
+		return result
+	}
+
+	var valResult *validator.ValidationResult
+
+	// Validate markdown format if requested or if required by config
+	if env.showValidation || (cfg.Crawler.Validation.ValidateTableFormat && cfg.Features.StrictValidation) {
+		out.println("\n🔍 Validating markdown format...")
+
+		valResult = env.validator.ValidateMarkdown(markdown)
+		result.validation = &crawler.ValidationSummary{
+			TotalRows:   valResult.Stats.TotalRows,
+			ValidRows:   valResult.Stats.ValidRows,
+			InvalidRows: valResult.Stats.InvalidRows,
+			Errors:      len(valResult.Errors),
+			Warnings:    len(valResult.Warnings),
+			Valid:       valResult.IsValid,
+		}
+
+		if cfg.Crawler.Logging.DetailedValidation {
+			valResult.FprintWarnings(w)
+
+			if !valResult.IsValid {
+				valResult.FprintErrors(w)
+			}
+		}
+
+		out.printf("%s\n", valResult)
+
+		if !valResult.IsValid && cfg.Features.StrictValidation {
+			out.printf("❌ Validation failed in strict mode, skipping...\n")
+
+			result.status = statusSkipped
+			result.err = fmt.Errorf("%w: %s", errStrictValidation, valResult)
+
+			if cfg.Advanced.SaveFailedRows {
+				env.saveSkippedRejects(&result, markdown, valResult, fireID, language, out)
+			}
+
+			return result
+		}
+	}
+
+	// Parse events
+	out.println("\n📊 Parsing timeline events...")
+
+	// Rows failing validation are recorded as rejects and left out of the events
+	if cfg.Advanced.SaveFailedRows && valResult == nil && cfg.Crawler.Validation.ValidateTableFormat {
+		valResult = env.validator.ValidateMarkdown(markdown)
+	}
+
+	result.valResult = valResult
+
+	events, rejects, err := env.parser.ParseMarkdownTableWithRejects(markdown, invalidRows(valResult))
+	if err != nil {
+		out.printf("❌ Parse failed: %v\n", err)
+
+		result.err = err
+
+		return result
+	}
+
+	// Parse full document for additional metadata
+	doc, diags, docErr := env.parser.ParseDocumentWithDiagnostics(markdown)
+	if docErr != nil {
+		out.printf("⚠️  Could not parse document metadata: %v\n", docErr)
+	}
+
+	out.printf("✅ Successfully extracted %d events\n", len(events))
+	diags.Fprint(w)
+
+	result.diagnostics = diags
+
+	result.events = len(events)
+
+	// If document metadata contains an IncidentID, use it to override the config ID
+	// This allows dynamic directory structure based on content
+	if doc != nil && doc.BasicInfo.IncidentID != "" {
+		out.printf("ℹ️  Found Incident ID in document: %s (overriding config: %s)\n",
+			doc.BasicInfo.IncidentID, fireID)
+
+		fireID = doc.BasicInfo.IncidentID
+	}
+
+	// Determine output path
+	out.printf("\n📝 Saving to %s...\n", strings.ToUpper(cfg.Crawler.Output.Format))
+
+	outputPath := env.outputPath(fireID, language, result.fileType)
+	result.outputPath = outputPath
+
+	if err = env.prepareOutput(outputPath, out); err != nil {
+		result.err = err
+
+		return result
+	}
+
+	// Save with document metadata if available
+	if err = env.client.SaveTimeline(events, doc, outputPath, cfg.Crawler.Output.Format); err != nil {
+		out.printf("❌ Save failed: %v\n", err)
+
+		result.err = err
+
+		return result
+	}
+
+	out.printf("✅ Saved to: %s\n", outputPath)
+
+	if cfg.Advanced.SaveFailedRows {
+		env.saveRejects(&result, rejects, outputPath, out)
+	}
+
+	result.status = statusSaved
+
+	return result
+}
+
+// fetchSource fetches a source from its URL, its backup URLs and its local file, with retries.
+// It returns the body with the fire ID and language of the location that served it.
+func (env *crawlEnv) fetchSource(ctx context.Context, result *sourceResult, sourceConfig config.SourceConfig, out sourceLog) (markdown, fireID, language, fetchedFrom string, err error) {
+	// Create a temporary config with just this source to use existing URLManager logic
+	sourceCfg := *env.cfg
+	sourceCfg.Crawler.Sources = []config.SourceConfig{sourceConfig}
+
+	urlManager := crawler.NewURLManager(&sourceCfg)
+	defer func() { result.attempts = urlManager.GetAllAttempts() }()
+
+	// Resolved on the first remote attempt so local-only sources never need the token
+	var reqOpts *crawler.RequestOptions
+
+	// Fetch from source (with retries)
+	for {
+		source, sourceName, fID, lang, attemptNum, nextErr := urlManager.NextURLContext(ctx)
+		if nextErr != nil {
+			out.printf("❌ Source exhausted: %v\n", nextErr)
+
+			return "", "", "", "", fmt.Errorf("%w: %s", errFetchFailed, sourceConfig.Name)
+		}
+
+		// Check if this is a local file source
+		if urlManager.IsCurrentSourceLocal() {
+			out.printf("⏳ Reading local file: %s\n", source)
+
+			content, fileSize, duration, readErr := env.scraper.ReadLocalFileWithMetrics(source)
+			urlManager.RecordAttempt(source, readErr == nil, readErr, 0, duration)
+
+			if readErr == nil {
+				result.fetchedKind = crawler.MirrorLocal
+
+				out.printf("✅ Successfully read %d bytes (%.2fms)\n", fileSize, float64(duration.Microseconds())/1000)
+
+				return content, fID, lang, source, nil
+			}
+
+			out.printf("❌ Failed to read local file: %v\n", readErr)
+
+			continue
+		}
+
+		// Remote URL source
+		if reqOpts == nil {
+			opts, optsErr := crawler.RequestOptionsFor(&sourceConfig)
+			if optsErr != nil {
+				out.printf("❌ Invalid request settings: %v\n", optsErr)
+
+				return "", "", "", "", optsErr
+			}
+
+			reqOpts = opts
+		}
+
+		out.printf("⏳ Fetching (Attempt %d): %s\n   Remote: %s\n", attemptNum, sourceName, source)
+
+		content, statusCode, duration, fetchErr := env.scraper.ScrapeWithOptionsContext(ctx, source, reqOpts)
+		urlManager.RecordAttempt(source, fetchErr == nil, fetchErr, statusCode, duration)
+
+		if fetchErr == nil {
+			result.fetchedKind = crawler.MirrorBackup
+			if source == sourceConfig.URL {
+				result.fetchedKind = crawler.MirrorPrimary
+			}
+
+			if statusCode == http.StatusNotModified {
+				result.cached = true
+				out.printf("✅ Not modified, using cached copy of %s (%.2fs)\n", sourceName, duration.Seconds())
+			} else {
+				out.printf("✅ Successfully fetched [Remote] from %s (%.2fs)\n", sourceName, duration.Seconds())
+			}
+
+			return content, fID, lang, source, nil
+		}
+
+		out.printf("❌ Failed: %v (%.2fs)\n", fetchErr, duration.Seconds())
+
+		// Check if we should retry
+		if attemptNum < env.cfg.Crawler.Retry.MaxAttempts {
+			delay := urlManager.GetRetryDelay(attemptNum)
+			out.printf("⏳ Retrying in %.1f seconds...\n", delay.Seconds())
+			// Note: NextURL will handle the retry increment
+		}
+	}
+}
+
+// readGitSource reads a git source at the commit its ref resolves to and records that commit.
+func (env *crawlEnv) readGitSource(ctx context.Context, result *sourceResult, sourceConfig config.SourceConfig, out sourceLog) (markdown, fetchedFrom string, err error) {
+	ref := sourceConfig.Ref
+	if ref == "" {
+		ref = "HEAD"
+	}
+
+	out.printf("⏳ Reading %s at %s from git repository %s\n", sourceConfig.Path, ref, sourceConfig.Repo)
+
+	start := time.Now()
+
+	doc, err := crawler.ReadGitSource(ctx, &sourceConfig)
+	if err != nil {
+		out.printf("❌ Git read failed: %v\n", err)
+
+		return "", "", err
+	}
+
+	result.fetchedKind = crawler.MirrorGit
+	result.commit = doc.Commit
+
+	out.printf("✅ Read %d bytes at commit %s (%.2fms)\n", len(doc.Content), doc.Commit, float64(time.Since(start).Microseconds())/1000)
+
+	return doc.Content, doc.Location(), nil
+}
+
+// saveDocument parses a document other than a FIRE_TIMELINE with its registered handler and saves it.
+func (env *crawlEnv) saveDocument(result *sourceResult, handler *parsers.FileTypeHandler, markdown, fireID, language string, out sourceLog) {
+	out.printf("\n📊 Parsing %s...\n", handler.Name)
+
+	doc, diags, err := handler.Parse(env.parser, markdown)
+	if err != nil {
+		out.printf("❌ Parse failed: %v\n", err)
+
+		result.err = err
+
+		return
+	}
+
+	result.diagnostics = diags
+	result.events = doc.Len()
+
+	out.printf("✅ Successfully extracted %s\n", doc.Summary())
+	diags.Fprint(out.w)
+
+	if incidentID := doc.IncidentID(); incidentID != "" {
+		out.printf("ℹ️  Found Incident ID in document: %s (overriding config: %s)\n", incidentID, fireID)
+
+		fireID = incidentID
+	}
+
+	out.printf("\n📝 Saving to %s...\n", strings.ToUpper(env.cfg.Crawler.Output.Format))
+
+	outputPath := env.outputPath(fireID, language, result.fileType)
+	result.outputPath = outputPath
+
+	if err = env.prepareOutput(outputPath, out); err != nil {
+		result.err = err
+
+		return
+	}
+
+	if err = env.client.SaveParsed(doc, outputPath, env.cfg.Crawler.Output.Format); err != nil {
+		out.printf("❌ Save failed: %v\n", err)
+
+		result.err = err
+
+		return
+	}
+
+	out.printf("✅ Saved to: %s\n", outputPath)
+
+	result.status = statusSaved
+}
+
+// outputPath returns the configured output path for a document, or the -output override.
+func (env *crawlEnv) outputPath(fireID, language, fileType string) string {
+	if env.outputOverride != "" {
+		return env.outputOverride
+	}
+
+	return env.cfg.GetOutputPath(fireID, language, fileType)
+}
+
+// prepareOutput creates the output directory; the client backs up and replaces the file itself.
+func (env *crawlEnv) prepareOutput(outputPath string, out sourceLog) error {
+	// Ensure output directory exists
+	outputDir := filepath.Dir(outputPath)
+	if outputDir != "." && outputDir != "" {
+		if mkdirErr := os.MkdirAll(outputDir, 0755); mkdirErr != nil {
+			out.printf("❌ Could not create output directory: %v\n", mkdirErr)
+
+			return mkdirErr
+		}
+	}
+
+	return nil
+}
+
+// invalidRows maps the line of every row with validation errors to its joined messages.
+func invalidRows(valResult *validator.ValidationResult) map[int]string {
+	if valResult == nil {
+		return nil
+	}
+
+	invalid := make(map[int]string)
+
+	for _, e := range valResult.Errors {
+		if e.Line == 0 {
+			continue
+		}
+
+		if prev, ok := invalid[e.Line]; ok {
+			invalid[e.Line] = prev + "; " + e.Message
+		} else {
+			invalid[e.Line] = e.Message
+		}
+	}
+
+	return invalid
+}
+
+// saveRejects writes the rejected rows next to the output file.
+// A failed write is reported but does not fail the source.
+func (env *crawlEnv) saveRejects(result *sourceResult, rejects []parsers.RejectedRow, outputPath string, out sourceLog) {
+	path := crawler.RejectsPath(outputPath)
+	result.rejected = len(rejects)
+
+	if err := crawler.WriteRejects(path, rejects); err != nil {
+		out.printf("⚠️  Could not save rejected rows: %v\n", err)
+
+		return
+	}
+
+	if len(rejects) > 0 {
+		result.rejectsPath = path
+		out.printf("🚫 %d rejected rows saved to: %s\n", len(rejects), path)
+	}
+}
+
+// saveSkippedRejects writes the rejected rows of a source that strict validation skipped to
+// where its rejects would have been saved, so the rows to fix are listed even though no output was written.
+func (env *crawlEnv) saveSkippedRejects(result *sourceResult, markdown string, valResult *validator.ValidationResult, fireID, language string, out sourceLog) {
+	_, rejects, err := env.parser.ParseMarkdownTableWithRejects(markdown, invalidRows(valResult))
+	if err != nil {
+		out.printf("⚠️  Could not collect rejected rows: %v\n", err)
+
+		return
+	}
+
+	if doc, docErr := env.parser.ParseDocument(markdown); docErr == nil && doc.BasicInfo.IncidentID != "" {
+		fireID = doc.BasicInfo.IncidentID
+	}
+
+	env.saveRejects(result, rejects, env.outputPath(fireID, language, result.fileType), out)
+}
+
+// storeSnapshot archives the fetched body, keyed by the document's incident ID when it has one,
+// and returns its hash. A failed archive write is reported but does not fail the source.
+func (env *crawlEnv) storeSnapshot(markdown, source, fireID, language string, out sourceLog) string {
+	if doc, err := env.parser.ParseDocument(markdown); err == nil && doc.BasicInfo.IncidentID != "" {
+		fireID = doc.BasicInfo.IncidentID
+	}
+
+	record, err := env.snapshots.Put(markdown, source, fireID, language)
+	if err != nil {
+		out.printf("⚠️  Could not store snapshot: %v\n", err)
+
+		return ""
+	}
+
+	out.printf("🗃️  Snapshot stored: %s\n", record.Hash[:12])
+
+	return record.Hash
+}
+
+// verifySource checks markdown against its metadata HASH and applies the verification mode.
+// It returns an error when the source must not be parsed any further.
+func (env *crawlEnv) verifySource(result *sourceResult, markdown, fireID, language string, out sourceLog) error {
+	verification := crawler.VerifyDocument(markdown, env.cfg.Crawler.Verification.AllowUnvalidated)
+	result.verification = verification
+
+	if verification.OK() {
+		out.printf("🔏 Metadata hash verified: %s\n", verification.Hash)
+
+		return nil
+	}
+
+	out.printf("⚠️  Metadata verification failed (%s): %s\n", verification.Status, verification.Reason)
+
+	path, err := crawler.ApplyVerifyMode(verification, markdown, fireID, language, env.verifyMode, env.cfg.GetQuarantineDir())
+	if path != "" {
+		out.printf("🚧 Quarantined document to: %s\n", path)
+	}
+
+	if err != nil {
+		out.printf("❌ Refusing unverified document: %v\n", err)
+	}
+
+	return err
+}
+
+// printResults prints a per-source summary in source order.
+func printResults(results []sourceResult) {
+	fmt.Println("\n📋 Crawl Results:")
+
+	saved, rejected := 0, 0
+
+	for _, r := range results {
+		rejected += r.rejected
+
+		switch r.status {
+		case statusSaved:
+			saved++
+
+			cacheNote := ""
+			if r.cached {
+				cacheNote = " (cached)"
+			}
+
+			if r.verification != nil {
+				cacheNote += " [" + r.verification.Status + "]"
+			}
+
+			if r.rejected > 0 {
+				cacheNote += fmt.Sprintf(" (%d rejected → %s)", r.rejected, r.rejectsPath)
+			}
+
+			fmt.Printf("  %d. ✅ %s (%s/%s): %d events → %s%s\n",
+				r.index+1, r.source.Name, r.source.FireID, r.source.Language, r.events, r.outputPath, cacheNote)
+		default:
+			fmt.Printf("  %d. ❌ %s (%s/%s): %s: %v\n",
+				r.index+1, r.source.Name, r.source.FireID, r.source.Language, r.status, r.err)
+		}
+	}
+
+	fmt.Printf("  Total: %d sources, %d saved, %d not saved, %d rows rejected\n", len(results), saved, len(results)-saved, rejected)
+}
+
+// buildReport converts the ordered results into a crawl report.
+func buildReport(results []sourceResult, startedAt time.Time, configPath string, workers int) *crawler.CrawlReport {
+	report := &crawler.CrawlReport{
+		StartedAt:  startedAt.UTC(),
+		ConfigPath: configPath,
+		Workers:    workers,
+		Sources:    make([]crawler.SourceReport, 0, len(results)),
+	}
+
+	for _, r := range results {
+		src := crawler.SourceReport{
+			Validation:    r.validation,
+			Verification:  r.verification,
+			Name:          r.source.Name,
+			FireID:        r.source.FireID,
+			Language:      r.source.Language,
+			Status:        r.status,
+			FetchedFrom:   r.fetchedFrom,
+			FetchedKind:   r.fetchedKind,
+			OutputPath:    r.outputPath,
+			SnapshotHash:  r.snapshotHash,
+			FileType:      r.fileType,
+			RejectsPath:   r.rejectsPath,
+			Commit:        r.commit,
+			Attempts:      r.attempts,
+			Diagnostics:   r.diagnostics,
+			BytesFetched:  r.bytes,
+			EventsParsed:  r.events,
+			RowsRejected:  r.rejected,
+			FromCache:     r.cached,
+			UsedLocalFile: r.fetchedKind == crawler.MirrorLocal,
+		}
+
+		if r.err != nil {
+			src.Error = r.err.Error()
+		}
+
+		report.Sources = append(report.Sources, src)
+	}
+
+	report.Finish(statusSaved, statusSkipped)
+
+	return report
+}
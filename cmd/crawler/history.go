@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"tpwfc/internal/crawler"
+	"tpwfc/internal/crawler/parsers"
+)
+
+// runHistory reconstructs the per-event history of path in repo as of ref and prints a summary.
+// With outputPath the full history is also written there as JSON. It returns false on failure.
+func runHistory(ctx context.Context, repo, ref, path, outputPath string) bool {
+	fmt.Printf("📜 Reconstructing history of %s in %s (%s)...\n", path, repo, ref)
+
+	history, err := crawler.BuildTimelineHistory(ctx, parsers.NewParser(), repo, ref, path)
+	if err != nil {
+		fmt.Printf("❌ History failed: %v\n", err)
+
+		return false
+	}
+
+	removed, changed := 0, 0
+
+	for _, event := range history.Events {
+		if event.RemovedIn != "" {
+			removed++
+		}
+
+		if len(event.Changes) > 1 {
+			changed++
+		}
+	}
+
+	fmt.Printf("✅ %d revisions, %d events (%d changed after introduction, %d removed)\n",
+		len(history.Revisions), len(history.Events), changed, removed)
+
+	for _, rev := range history.Revisions {
+		if rev.Error != "" {
+			fmt.Printf("⚠️  %s %s: %s\n", shortCommit(rev.Commit), rev.Subject, rev.Error)
+		}
+	}
+
+	for _, event := range history.Events {
+		for _, change := range event.Changes[1:] {
+			fmt.Printf("  %s %s %-11s %s %s %s\n", change.Time.Format("2006-01-02"), shortCommit(change.Commit),
+				change.Kind, event.Date, event.Time, event.Category)
+		}
+	}
+
+	if outputPath == "" {
+		return true
+	}
+
+	data, err := json.MarshalIndent(history, "", "  ")
+	if err != nil {
+		fmt.Printf("❌ Failed to encode history: %v\n", err)
+
+		return false
+	}
+
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
+		fmt.Printf("❌ Failed to create output directory: %v\n", err)
+
+		return false
+	}
+
+	if err := crawler.WriteFileAtomic(outputPath, data); err != nil {
+		fmt.Printf("❌ Failed to write history: %v\n", err)
+
+		return false
+	}
+
+	fmt.Printf("💾 History saved to: %s\n", outputPath)
+
+	return true
+}
+
+// shortCommit abbreviates a commit SHA for display.
+func shortCommit(commit string) string {
+	return commit[:min(len(commit), 8)]
+}
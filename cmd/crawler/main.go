@@ -2,12 +2,16 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"log"
 	"os"
-	"os/exec"
+	"os/signal"
 	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
 
 	"tpwfc/internal/config"
 	"tpwfc/internal/crawler"
@@ -23,6 +27,21 @@ func main() {
 	output := flag.String("output", "", "Output JSON file path (overrides config)")
 	format := flag.String("format", "", "Output format (overrides config)")
 	showValidation := flag.Bool("validate", false, "Validate markdown format before crawling")
+	concurrency := flag.Int("concurrency", 0, "Number of sources to crawl in parallel (overrides config)")
+	verifyMode := flag.String("verify", "", "Metadata hash verification: off, warn, refuse or quarantine (overrides config)")
+	reportDir := flag.String("report-dir", "", "Directory for the JSON crawl report (overrides config)")
+	audit := flag.Bool("audit", false, "Compare primary, backup and local copies of each source instead of crawling")
+	timeout := flag.Duration("timeout", 0, "Abort the whole crawl after this duration (e.g. 5m, 0 = no limit)")
+	allowedHosts := flag.String("allowed-hosts", "", "Comma-separated hosts remote fetches may reach, *.domain for subdomains (overrides config)")
+	listBackups := flag.String("backups", "", "List the timestamped backups of an output file")
+	restore := flag.String("restore", "", "Restore an output file from its newest backup (or the one chosen with -backup)")
+	backup := flag.String("backup", "", "Backup to restore: file name, path or timestamp prefix (default newest)")
+	historyPath := flag.String("history", "", "Reconstruct the per-event history of this file (path inside -repo) from git")
+	repo := flag.String("repo", "../data", "Git repository for -history")
+	ref := flag.String("ref", "HEAD", "Branch, tag or commit whose history -history walks")
+	watch := flag.Bool("watch", false, "Re-parse, validate and save local file sources whenever they change, until interrupted")
+	watchInterval := flag.Duration("watch-interval", crawler.DefaultWatchInterval, "How often -watch polls the source files")
+	watchDebounce := flag.Duration("watch-debounce", crawler.DefaultWatchDebounce, "How long a changed file must stay unchanged before -watch re-runs")
 	showUsage := flag.Bool("help", false, "Show usage information")
 
 	flag.Parse()
@@ -32,7 +51,38 @@ func main() {
 		os.Exit(0)
 	}
 
+	// Backup maintenance does not crawl anything
+	if *listBackups != "" || *restore != "" {
+		if !runBackups(*configFile, *listBackups, *restore, *backup) {
+			os.Exit(1)
+		}
+
+		return
+	}
+
+	// History mode reads the repository, not the configured sources
+	if *historyPath != "" {
+		ctx, cancel := newRunContext(*timeout)
+		ok := runHistory(ctx, *repo, *ref, *historyPath, *output)
+
+		cancel()
+
+		if !ok {
+			os.Exit(1)
+		}
+
+		return
+	}
+
 	// If local file is provided, use local file mode
+	if *localFile != "" && *watch {
+		if !runWatchFile(*localFile, *output, *configFile, *watchInterval, *watchDebounce, *timeout) {
+			os.Exit(1)
+		}
+
+		return
+	}
+
 	if *localFile != "" {
 		runLocalFileMode(*localFile, *output, *showValidation)
 
@@ -43,6 +93,8 @@ func main() {
 
 	var err error
 
+	configPath := *configFile
+
 	// Load configuration
 	if *configFile != "" {
 		fmt.Printf("⚙️  Loading configuration from: %s\n", *configFile)
@@ -65,6 +117,8 @@ func main() {
 		if _, statErr := os.Stat(defaultConfig); statErr == nil {
 			fmt.Printf("⚙️  Loading default configuration: %s\n", defaultConfig)
 
+			configPath = defaultConfig
+
 			cfg, err = config.LoadConfig(defaultConfig)
 			if err != nil {
 				log.Fatalf("❌ Failed to load default config: %v\n", err)
@@ -76,6 +130,11 @@ func main() {
 		}
 	}
 
+	// Replace discover sources with the documents found in the data repository
+	if err = cfg.ExpandSources(parsers.NewParser().DetectFileType); err != nil {
+		log.Fatalf("❌ Failed to discover sources: %v\n", err)
+	}
+
 	printCrawlerHeader(cfg)
 
 	// Create URL manager with fallback support
@@ -84,196 +143,156 @@ func main() {
 	parser := parsers.NewParser()
 	client := crawler.NewClientWithDeps(scraper, parser, urlManager)
 
-	// Create validator
-	markdownValidator, err := validator.NewMarkdownValidator(cfg)
-	if err != nil {
-		log.Fatalf("❌ Failed to create validator: %v\n", err)
-	}
-
-	// Process each enabled source
-	enabledSources := cfg.GetEnabledSources()
-	fmt.Printf("🚀 Processing %d enabled sources...\n", len(enabledSources))
-
-	for i, sourceConfig := range enabledSources {
-		fmt.Printf("\n----------------------------------------------------------------\n")
-		fmt.Printf("📦 Source %d/%d: %s (%s/%s)\n", i+1, len(enabledSources), sourceConfig.Name, sourceConfig.FireID, sourceConfig.Language)
-
-		// Create a temporary config with just this source to use existing URLManager logic
-		sourceCfg := *cfg
-		sourceCfg.Crawler.Sources = []config.SourceConfig{sourceConfig}
-
-		urlManager := crawler.NewURLManager(&sourceCfg)
-
-		// Fetch from source (with retries)
-		var markdown string
-
-		var fireID, language string
-
-		var fetchSuccess bool
-
-		for {
-			source, sourceName, fID, lang, attemptNum, err := urlManager.NextURL()
-			if err != nil {
-				fmt.Printf("❌ Source exhausted: %v\n", err)
-
-				break
+	// Keep remote fetches away from internal services
+	if *allowedHosts != "" {
+		cfg.Crawler.Network.AllowedHosts = config.SplitHostList(*allowedHosts)
+		for _, host := range cfg.Crawler.Network.AllowedHosts {
+			if !config.IsValidHostPattern(host) {
+				log.Fatalf("❌ Invalid -allowed-hosts entry: %s\n", host)
 			}
+		}
+	}
 
-			// Check if this is a local file source
-			if urlManager.IsCurrentSourceLocal() {
-				// Ensure local data is up to date
-				gitPull(source)
-
-				fmt.Printf("⏳ Reading local file: %s\n", source)
-
-				content, fileSize, duration, readErr := scraper.ReadLocalFileWithMetrics(source)
-				urlManager.RecordAttempt(source, readErr == nil, readErr, 0, duration)
-
-				if readErr == nil {
-					markdown = content
-					fireID = fID
-					language = lang
-					fetchSuccess = true
-
-					fmt.Printf("✅ Successfully read %d bytes (%.2fms)\n", fileSize, float64(duration.Microseconds())/1000)
+	scraper.SetNetworkPolicy(crawler.NewNetworkPolicy(cfg))
 
-					break
-				}
+	if len(cfg.Crawler.Network.AllowedHosts) > 0 {
+		fmt.Printf("🛡️  Allowed hosts: %s\n\n", strings.Join(cfg.Crawler.Network.AllowedHosts, ", "))
+	}
 
-				fmt.Printf("❌ Failed to read local file: %v\n", readErr)
+	// Keep timestamped copies of the previous output of every source
+	client.SetBackupRetention(cfg.GetBackupRetention())
 
-				continue
-			}
+	// Enable conditional fetching against the on-disk response cache
+	if cfg.Features.EnableCaching {
+		cache, cacheErr := crawler.NewResponseCache(cfg.GetCacheDir())
+		if cacheErr != nil {
+			log.Fatalf("❌ Failed to create response cache: %v\n", cacheErr)
+		}
 
-			// Remote URL source
-			fmt.Printf("⏳ Fetching (Attempt %d): %s\n   Remote: %s\n", attemptNum, sourceName, source)
+		scraper.SetCache(cache)
+		fmt.Printf("🗄️  Response cache enabled: %s\n\n", cache.Dir())
+	}
 
-			content, statusCode, duration, fetchErr := scraper.ScrapeWithMetrics(source)
-			urlManager.RecordAttempt(source, fetchErr == nil, fetchErr, statusCode, duration)
+	// Archive every fetched body for later re-parsing
+	var snapshots *crawler.SnapshotStore
 
-			if fetchErr == nil {
-				markdown = content
-				fireID = fID
-				language = lang
-				fetchSuccess = true
+	if cfg.Features.EnableSnapshots {
+		snapshots, err = crawler.NewSnapshotStore(cfg.GetSnapshotDir())
+		if err != nil {
+			log.Fatalf("❌ Failed to create snapshot store: %v\n", err)
+		}
 
-				fmt.Printf("✅ Successfully fetched [Remote] from %s (%.2fs)\n", sourceName, duration.Seconds())
+		fmt.Printf("🗃️  Snapshot archive enabled: %s\n\n", snapshots.Dir())
+	}
 
-				break
-			}
+	// Create validator
+	markdownValidator, err := validator.NewMarkdownValidator(cfg)
+	if err != nil {
+		log.Fatalf("❌ Failed to create validator: %v\n", err)
+	}
 
-			fmt.Printf("❌ Failed: %v (%.2fs)\n", fetchErr, duration.Seconds())
+	// Process each enabled source
+	enabledSources := cfg.GetEnabledSources()
 
-			// Check if we should retry
-			if attemptNum < cfg.Crawler.Retry.MaxAttempts {
-				delay := urlManager.GetRetryDelay(attemptNum)
-				fmt.Printf("⏳ Retrying in %.1f seconds...\n", delay.Seconds())
-				// Note: NextURL will handle the retry increment
-			}
-		}
+	workers := cfg.GetConcurrency()
+	if *concurrency > 0 {
+		workers = *concurrency
+	}
 
-		if !fetchSuccess {
-			fmt.Printf("⚠️  Skipping source %s due to fetch failure\n", sourceConfig.Name)
+	fmt.Printf("🚀 Processing %d enabled sources (%d at a time)...\n", len(enabledSources), workers)
 
-			continue
+	verify := cfg.GetVerifyMode()
+	if *verifyMode != "" {
+		if !config.IsValidVerifyMode(*verifyMode) {
+			log.Fatalf("❌ Invalid -verify mode: %s\n", *verifyMode)
 		}
 
-		// Validate markdown format if requested or if required by config
-		if *showValidation || (cfg.Crawler.Validation.ValidateTableFormat && cfg.Features.StrictValidation) {
-			fmt.Println("\n🔍 Validating markdown format...")
-
-			valResult := markdownValidator.ValidateMarkdown(markdown)
-
-			if cfg.Crawler.Logging.DetailedValidation {
-				valResult.PrintWarnings()
+		verify = *verifyMode
+	}
 
-				if !valResult.IsValid {
-					valResult.PrintErrors()
-				}
-			}
+	if verify != config.VerifyOff {
+		fmt.Printf("🔏 Metadata verification: %s\n", verify)
+	}
 
-			fmt.Printf("%s\n", valResult)
+	env := &crawlEnv{
+		cfg:            cfg,
+		verifyMode:     verify,
+		snapshots:      snapshots,
+		scraper:        scraper,
+		parser:         parser,
+		client:         client,
+		validator:      markdownValidator,
+		showValidation: *showValidation,
+	}
 
-			if !valResult.IsValid && cfg.Features.StrictValidation {
-				fmt.Printf("❌ Validation failed in strict mode, skipping...\n")
+	// Only override output path if processing single source or specified via CLI
+	if *output != "" && len(enabledSources) == 1 {
+		env.outputOverride = *output
+	}
 
-				continue
-			}
+	if *audit {
+		if !runAudit(*timeout, enabledSources, scraper, parser) {
+			os.Exit(1)
 		}
 
-		// Parse events
-		fmt.Println("\n📊 Parsing timeline events...")
+		return
+	}
 
-		events, err := parser.ParseMarkdownTable(markdown)
-		if err != nil {
-			fmt.Printf("❌ Parse failed: %v\n", err)
+	if *watch {
+		ctx, cancel := newRunContext(*timeout)
+		ok := env.runWatch(ctx, enabledSources, *watchInterval, *watchDebounce)
 
-			continue
-		}
+		cancel()
 
-		// Parse full document for additional metadata
-		doc, docErr := parser.ParseDocument(markdown)
-		if docErr != nil {
-			fmt.Printf("⚠️  Could not parse document metadata: %v\n", docErr)
+		if !ok {
+			os.Exit(1)
 		}
 
-		fmt.Printf("✅ Successfully extracted %d events\n", len(events))
+		return
+	}
 
-		// If document metadata contains an IncidentID, use it to override the config ID
-		// This allows dynamic directory structure based on content
-		if doc != nil && doc.BasicInfo.IncidentID != "" {
-			fmt.Printf("ℹ️  Found Incident ID in document: %s (overriding config: %s)\n",
-				doc.BasicInfo.IncidentID, fireID)
+	ctx, cancel := newRunContext(*timeout)
+	defer cancel()
 
-			fireID = doc.BasicInfo.IncidentID
-		}
+	startedAt := time.Now()
+	results := env.crawlAll(ctx, enabledSources, workers)
+	printResults(results)
 
-		// Determine output path
-		fmt.Println("\n📝 Saving to JSON...")
+	// Persist the run for the seeder and CI
+	if *reportDir != "" {
+		cfg.Crawler.Output.ReportDir = *reportDir
+	}
 
-		outputPath := cfg.GetOutputPath(fireID, language)
-		if *output != "" && len(enabledSources) == 1 {
-			// Only override output path if processing single source or specified via CLI
-			outputPath = *output
-		}
+	report := buildReport(results, startedAt, configPath, workers)
+	if reportPath, reportErr := report.Write(cfg.GetReportDir()); reportErr != nil {
+		fmt.Printf("⚠️  Could not write crawl report: %v\n", reportErr)
+	} else {
+		fmt.Printf("📄 Crawl report: %s\n", reportPath)
+	}
 
-		// Create backup if file exists
-		if cfg.Crawler.Output.CreateBackup {
-			if _, statErr := os.Stat(outputPath); statErr == nil {
-				backupPath := outputPath + ".bak"
-				if renameErr := os.Rename(outputPath, backupPath); renameErr != nil {
-					fmt.Printf("⚠️  Could not create backup: %v\n", renameErr)
-				} else {
-					fmt.Printf("💾 Backed up existing file to: %s\n", backupPath)
-				}
-			}
-		}
+	if ctx.Err() != nil {
+		fmt.Printf("\n⚠️  Crawling interrupted: %v\n", ctx.Err())
 
-		// Ensure output directory exists
-		outputDir := filepath.Dir(outputPath)
-		if outputDir != "." && outputDir != "" {
-			if mkdirErr := os.MkdirAll(outputDir, 0755); mkdirErr != nil {
-				log.Fatalf("❌ Could not create output directory: %v\n", mkdirErr)
-			}
-		}
+		return
+	}
 
-		// Save with document metadata if available
-		if doc != nil {
-			err = client.SaveTimelineJSONWithDocument(events, doc, outputPath)
-		} else {
-			err = client.SaveTimelineJSON(events, outputPath)
-		}
+	fmt.Println("\n✨ Crawling complete!")
+}
 
-		if err != nil {
-			fmt.Printf("❌ Save failed: %v\n", err)
+// newRunContext returns a context cancelled on Ctrl-C, SIGTERM or after timeout (0 = no limit),
+// so in-flight fetches and backoff sleeps stop promptly.
+func newRunContext(timeout time.Duration) (context.Context, context.CancelFunc) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	if timeout <= 0 {
+		return ctx, stop
+	}
 
-			continue
-		}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
 
-		fmt.Printf("✅ Saved to: %s\n", outputPath)
+	return ctx, func() {
+		cancel()
+		stop()
 	}
-
-	fmt.Println("\n✨ Crawling complete!")
 }
 
 // createConfigFromCLI creates a config from CLI arguments.
@@ -290,13 +309,7 @@ func createConfigFromCLI(url, output, format string) *config.Config {
 					Enabled:  true,
 				},
 			},
-			Retry: config.RetryPolicy{
-				MaxAttempts:       3,
-				InitialDelayMs:    500,
-				MaxDelayMs:        30000,
-				BackoffMultiplier: 2.0,
-				TimeoutSec:        30,
-			},
+			Retry: config.DefaultRetryPolicy(),
 			Output: config.OutputConfig{
 				BasePath:     "./data/fire",
 				Path:         output,
@@ -394,6 +407,18 @@ func runLocalFileMode(filePath, outputPath string, validate bool) {
 		fmt.Printf("%s\n", valResult)
 	}
 
+	handler, err := parser.FileTypeHandler(markdown)
+	if err != nil {
+		log.Fatalf("❌ %v\n", err)
+	}
+
+	// Only timelines have events to sample; other types are saved as parsed
+	if handler.FileType != parsers.FileTypeTimeline {
+		saveLocalDocument(client, parser, handler, markdown, localOutputPath(filePath, outputPath), cfg)
+
+		return
+	}
+
 	// Parse events
 	fmt.Println("\n📊 Parsing timeline events...")
 
@@ -403,36 +428,21 @@ func runLocalFileMode(filePath, outputPath string, validate bool) {
 	}
 
 	// Parse full document for additional metadata
-	doc, docErr := parser.ParseDocument(markdown)
+	doc, diags, docErr := parser.ParseDocumentWithDiagnostics(markdown)
 	if docErr != nil {
 		fmt.Printf("⚠️  Could not parse document metadata: %v\n", docErr)
 	}
 
 	fmt.Printf("✅ Successfully extracted %d events\n", len(events))
+	diags.Fprint(os.Stdout)
 
 	// Determine output path
-	if outputPath == "" {
-		// Default output path based on input file
-		dir := filepath.Dir(filePath)
-		base := filepath.Base(filePath)
-		ext := filepath.Ext(base)
-		name := base[:len(base)-len(ext)]
-		outputPath = filepath.Join(dir, name+".json")
-	}
+	outputPath = localOutputPath(filePath, outputPath)
 
 	fmt.Println("\n📝 Saving to JSON...")
 
-	// Create backup if file exists
-	if cfg.Crawler.Output.CreateBackup {
-		if _, statErr := os.Stat(outputPath); statErr == nil {
-			backupPath := outputPath + ".bak"
-			if renameErr := os.Rename(outputPath, backupPath); renameErr != nil {
-				fmt.Printf("⚠️  Could not create backup: %v\n", renameErr)
-			} else {
-				fmt.Printf("💾 Backed up existing file to: %s\n", backupPath)
-			}
-		}
-	}
+	// Keep timestamped copies of the previous output
+	client.SetBackupRetention(cfg.GetBackupRetention())
 
 	// Ensure output directory exists
 	outputDir := filepath.Dir(outputPath)
@@ -443,13 +453,7 @@ func runLocalFileMode(filePath, outputPath string, validate bool) {
 	}
 
 	// Save with document metadata if available
-	if doc != nil {
-		err = client.SaveTimelineJSONWithDocument(events, doc, outputPath)
-	} else {
-		err = client.SaveTimelineJSON(events, outputPath)
-	}
-
-	if err != nil {
+	if err = client.SaveTimeline(events, doc, outputPath, cfg.Crawler.Output.Format); err != nil {
 		log.Fatalf("❌ Save failed: %v\n", err)
 	}
 
@@ -484,6 +488,50 @@ func runLocalFileMode(filePath, outputPath string, validate bool) {
 	fmt.Println("\n✨ Local file crawling complete!")
 }
 
+// saveLocalDocument parses a local document other than a FIRE_TIMELINE with its registered handler and saves it.
+func saveLocalDocument(client *crawler.Client, parser *parsers.Parser, handler *parsers.FileTypeHandler, markdown, outputPath string, cfg *config.Config) {
+	fmt.Printf("\n📊 Parsing %s...\n", handler.Name)
+
+	doc, diags, err := handler.Parse(parser, markdown)
+	if err != nil {
+		log.Fatalf("❌ Parse failed: %v\n", err)
+	}
+
+	fmt.Printf("✅ Successfully extracted %s\n", doc.Summary())
+	diags.Fprint(os.Stdout)
+
+	fmt.Println("\n📝 Saving to JSON...")
+
+	client.SetBackupRetention(cfg.GetBackupRetention())
+
+	if outputDir := filepath.Dir(outputPath); outputDir != "." && outputDir != "" {
+		if mkdirErr := os.MkdirAll(outputDir, 0755); mkdirErr != nil {
+			log.Fatalf("❌ Could not create output directory: %v\n", mkdirErr)
+		}
+	}
+
+	if err = client.SaveParsed(doc, outputPath, cfg.Crawler.Output.Format); err != nil {
+		log.Fatalf("❌ Save failed: %v\n", err)
+	}
+
+	fmt.Printf("✅ Saved to: %s\n", outputPath)
+	fmt.Println("\n✨ Local file crawling complete!")
+}
+
+// localOutputPath returns outputPath, or by default the input file's path with a .json extension.
+func localOutputPath(filePath, outputPath string) string {
+	if outputPath != "" {
+		return outputPath
+	}
+
+	dir := filepath.Dir(filePath)
+	base := filepath.Base(filePath)
+	ext := filepath.Ext(base)
+	name := base[:len(base)-len(ext)]
+
+	return filepath.Join(dir, name+".json")
+}
+
 func printUsage() {
 	fmt.Println("Usage: ./bin/crawler [OPTIONS]")
 	fmt.Println()
@@ -492,6 +540,8 @@ func printUsage() {
 	fmt.Println("  2. Default config: ./bin/crawler (reads configs/crawler.yaml if exists)")
 	fmt.Println("  3. CLI arguments:  ./bin/crawler -url <URL> -output <PATH>")
 	fmt.Println("  4. Local file:     ./bin/crawler -file <PATH> [-output <PATH>]")
+	fmt.Println("  5. Git history:    ./bin/crawler -history <PATH> [-repo <DIR>] [-ref <REF>] [-output <PATH>]")
+	fmt.Println("  6. Watch:          ./bin/crawler -watch [-config <PATH> | -file <PATH>]")
 	fmt.Println()
 	fmt.Println("Options:")
 	flag.PrintDefaults()
@@ -501,41 +551,13 @@ func printUsage() {
 	fmt.Println("  ./bin/crawler -url https://raw.githubusercontent.com/... -output output.json")
 	fmt.Println("  ./bin/crawler -file data/source/zh-HK/fire/WANG_FUK_COURT_FIRE_2025/timeline.md -output data/fire/WANG_FUK_COURT_FIRE_2025/zh-hk/timeline.json")
 	fmt.Println("  ./bin/crawler -config configs/crawler.yaml -validate")
-}
-
-// gitPull executes git pull in the directory of the given file path.
-func gitPull(filePath string) {
-	// Resolve absolute path to be safe, or use relative if that's how we run
-	dir := filepath.Dir(filePath)
-
-	// Check if directory exists
-	if _, err := os.Stat(dir); os.IsNotExist(err) {
-		// If directory doesn't exist, we can't pull.
-		// It might be created later or path is wrong.
-		return
-	}
-
-	fmt.Printf("🔄 Checking for git updates in %s...\n", dir)
-
-	// Create command
-	cmd := exec.Command("git", "pull")
-	cmd.Dir = dir
-
-	// Run command
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		// Log warning but don't fail the whole process - local file might still be readable
-		fmt.Printf("⚠️  Git pull warning: %v\n", err)
-	} else {
-		// Only print output if verbose or interesting; git pull usually prints "Already up to date."
-		// We'll print a summary.
-		outputStr := string(output)
-		if outputStr != "" {
-			// Trim newline
-			if outputStr[len(outputStr)-1] == '\n' {
-				outputStr = outputStr[:len(outputStr)-1]
-			}
-			fmt.Printf("📄 Git output: %s\n", outputStr)
-		}
-	}
+	fmt.Println("  ./bin/crawler -config configs/crawler.yaml -concurrency 4")
+	fmt.Println("  ./bin/crawler -config configs/crawler.yaml -timeout 5m")
+	fmt.Println("  ./bin/crawler -config configs/crawler.yaml -verify refuse")
+	fmt.Println("  ./bin/crawler -config configs/crawler.yaml -audit")
+	fmt.Println("  ./bin/crawler -config configs/crawler.yaml -watch")
+	fmt.Println("  ./bin/crawler -file timeline.md -config configs/crawler.yaml -watch")
+	fmt.Println("  ./bin/crawler -backups data/fire/WANG_FUK_COURT_FIRE_2025/zh-hk/fire_timeline.json")
+	fmt.Println("  ./bin/crawler -restore data/fire/WANG_FUK_COURT_FIRE_2025/zh-hk/fire_timeline.json -backup 20251127")
+	fmt.Println("  ./bin/crawler -history source/zh-HK/fire/WANG_FUK_COURT_FIRE_2025/timeline.md -output data/history.json")
 }
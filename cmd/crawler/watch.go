@@ -0,0 +1,179 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"tpwfc/internal/config"
+	"tpwfc/internal/crawler"
+	"tpwfc/internal/crawler/parsers"
+	"tpwfc/internal/validator"
+)
+
+// localSources returns the sources backed by a local file, reading only that file:
+// a watch run must show the editor's working copy, not the published version.
+func localSources(sources []config.SourceConfig) []config.SourceConfig {
+	var local []config.SourceConfig
+
+	for _, src := range sources {
+		if src.Type == config.SourceTypeGit || !src.IsLocalFile() {
+			continue
+		}
+
+		src.URL = ""
+		src.BackupURLs = nil
+		local = append(local, src)
+	}
+
+	return local
+}
+
+// runWatch crawls the local-file sources, then crawls each again whenever its file changes,
+// redrawing the terminal with how its validation changed since the previous run. Outputs are
+// overwritten without backups and no snapshots are stored, so an editing session does not
+// rotate real backups out or fill the archive. It returns false when there is nothing to
+// watch, and true once ctx is done.
+func (env *crawlEnv) runWatch(ctx context.Context, sources []config.SourceConfig, interval, debounce time.Duration) bool {
+	sources = localSources(sources)
+	if len(sources) == 0 {
+		fmt.Println("❌ No enabled sources with a local file to watch")
+
+		return false
+	}
+
+	// Every run validates, so there is always a previous result to compare against
+	env.showValidation = true
+	env.client.SetBackupRetention(0)
+	env.snapshots = nil
+
+	byPath := make(map[string][]config.SourceConfig)
+	paths := make([]string, 0, len(sources))
+
+	for _, src := range sources {
+		if _, seen := byPath[src.File]; !seen {
+			paths = append(paths, src.File)
+		}
+
+		byPath[src.File] = append(byPath[src.File], src)
+	}
+
+	// Baseline before the first run, so edits made while it runs are picked up
+	watcher := crawler.NewFileWatcher(paths, interval, debounce)
+	previous := make(map[string]*validator.ValidationResult)
+
+	env.watchRun(ctx, sources, previous)
+
+	err := watcher.Watch(ctx, func(changed []string) {
+		var rerun []config.SourceConfig
+		for _, path := range changed {
+			rerun = append(rerun, byPath[path]...)
+		}
+
+		clearScreen()
+		fmt.Printf("🔄 [%s] Changed: %v\n", time.Now().Format("15:04:05"), changed)
+		env.watchRun(ctx, rerun, previous)
+	})
+	if err != nil && !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded) {
+		fmt.Printf("❌ Watch failed: %v\n", err)
+
+		return false
+	}
+
+	fmt.Println("\n👋 Stopped watching")
+
+	return true
+}
+
+// watchRun crawls sources one at a time and prints, for each, the validation changes
+// since its previous run, remembering this run's results for the next comparison.
+func (env *crawlEnv) watchRun(ctx context.Context, sources []config.SourceConfig, previous map[string]*validator.ValidationResult) {
+	results := env.crawlAll(ctx, sources, 1)
+	if ctx.Err() != nil {
+		return
+	}
+
+	printResults(results)
+
+	for _, r := range results {
+		if r.valResult == nil {
+			continue
+		}
+
+		key := r.source.FireID + ":" + r.source.Language + ":" + r.source.File
+
+		prev, seen := previous[key]
+		previous[key] = r.valResult
+
+		if !seen {
+			continue
+		}
+
+		fmt.Printf("\n%s (%s/%s):\n", r.source.Name, r.source.FireID, r.source.Language)
+		r.valResult.Diff(prev).FprintDiff(os.Stdout)
+	}
+
+	fmt.Printf("\n👀 Watching for changes (Ctrl-C to stop)...\n")
+}
+
+// clearScreen clears the terminal so each watch run replaces the previous one in place
+// instead of scrolling. It does nothing when stdout is not a terminal.
+func clearScreen() {
+	if info, err := os.Stdout.Stat(); err == nil && info.Mode()&os.ModeCharDevice != 0 {
+		fmt.Print("\033[H\033[2J")
+	}
+}
+
+// runWatchFile watches the single markdown file given with -file and saves it as JSON to
+// outputPath (next to the file by default). The validation rules come from configPath when
+// it is set; the command-line defaults only check the document parses.
+func runWatchFile(filePath, outputPath, configPath string, interval, debounce, timeout time.Duration) bool {
+	fmt.Println("🕷️  TPWFC Timeline Crawler - Watch Mode")
+	fmt.Printf("📂 Source file: %s\n", filePath)
+
+	cfg := createConfigFromCLI("", outputPath, "json")
+	cfg.Crawler.Sources[0].Name = filepath.Base(filePath)
+	cfg.Crawler.Sources[0].File = filePath
+
+	if configPath != "" {
+		loaded, err := config.LoadConfig(configPath)
+		if err != nil {
+			fmt.Printf("❌ Failed to load config: %v\n", err)
+
+			return false
+		}
+
+		cfg.Crawler.Validation = loaded.Crawler.Validation
+		cfg.Crawler.Logging = loaded.Crawler.Logging
+		fmt.Printf("⚙️  Validation rules from: %s\n", configPath)
+	}
+
+	markdownValidator, err := validator.NewMarkdownValidator(cfg)
+	if err != nil {
+		fmt.Printf("❌ Failed to create validator: %v\n", err)
+
+		return false
+	}
+
+	scraper := crawler.NewScraperWithConfig(&cfg.Crawler.Retry, cfg.Advanced.BufferSizeKb)
+	parser := parsers.NewParser()
+	client := crawler.NewClientWithDeps(scraper, parser, nil)
+
+	env := &crawlEnv{
+		cfg:            cfg,
+		verifyMode:     config.VerifyOff,
+		scraper:        scraper,
+		parser:         parser,
+		client:         client,
+		validator:      markdownValidator,
+		outputOverride: localOutputPath(filePath, outputPath),
+	}
+
+	ctx, cancel := newRunContext(timeout)
+	defer cancel()
+
+	return env.runWatch(ctx, cfg.Crawler.Sources, interval, debounce)
+}
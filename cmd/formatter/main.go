@@ -50,6 +50,11 @@ func main() {
 		if err != nil {
 			log.Printf("⚠️  Failed to load config: %v (proceeding with defaults)\n", err)
 		} else {
+			// Discovered sources must be listed so remote-backed files are skipped below
+			if expandErr := cfg.ExpandSources(parsers.NewParser().DetectFileType); expandErr != nil {
+				log.Printf("⚠️  Failed to discover sources: %v\n", expandErr)
+			}
+
 			if !cfg.Features.EnableMarkdownFormatter {
 				fmt.Println("⚠️  Note: 'enable_markdown_formatter' is set to false in config.")
 				fmt.Println("   (Running anyway because you explicitly invoked the formatter command)")
@@ -179,8 +184,7 @@ func processFile(path string, write bool, cfg *config.Config) (bool, error) {
 
 		// Note: We need to import "tpwfc/internal/crawler"
 
-		parser := parsers.NewParser()
-		fileType := parser.ParseFileType(formatted)
+		handler, handlerErr := parsers.NewParser().FileTypeHandler(formatted)
 
 		shouldValidate := true
 
@@ -192,8 +196,8 @@ func processFile(path string, write bool, cfg *config.Config) (bool, error) {
 			validated = true
 		}
 
-		if fileType == "FIRE_INVESTIGATION" || fileType == "FIRE_RESPONSES" {
-			// Skip validation for these types as they don't have the standard timeline table yet
+		if handlerErr == nil && !handler.ValidateTable {
+			// Skip validation for types that don't have the standard timeline table
 			shouldValidate = false
 			// We can consider them "valid" in terms of "signed as valid" if we don't want to block them,
 			// or "false" if we want to indicate they aren't fully validated.
@@ -33,64 +33,34 @@ func main() {
 
 	// Parse based on file type
 	parser := parsers.NewParser()
-	fileType := parser.ParseFileType(string(content))
-	fmt.Printf("🔍 Detected File Type: %s\n", fileType)
-
-	var output interface{}
-
-	switch fileType {
-	case "DETAILED_TIMELINE":
-		doc, parseErr := parser.ParseDetailedTimeline(string(content))
-		if parseErr != nil {
-			log.Fatalf("Error parsing detailed timeline: %v\n", parseErr)
-		}
-		fmt.Printf("📊 Parsed: %d phases, %d long-term tracking events, %d category metrics, %d notes\n",
-			len(doc.Phases), len(doc.LongTermTracking), len(doc.CategoryMetrics), len(doc.Notes))
-
-		// Map to map[string]interface{} for consistency with previous output structure
-		output = map[string]interface{}{
-			"phases":           doc.Phases,
-			"longTermTracking": doc.LongTermTracking,
-			"categoryMetrics":  doc.CategoryMetrics,
-			"notes":            doc.Notes,
-		}
-
-	case "FIRE_TIMELINE":
-		doc, parseErr := parser.ParseDocument(string(content))
-		if parseErr != nil {
-			log.Fatalf("Error parsing timeline: %v\n", parseErr)
-		}
-		fmt.Printf("📊 Parsed standard timeline: %d events\n", len(doc.Events))
-		output = doc
-
-	case "FIRE_INVESTIGATION", "FIRE_RESPONSES":
-		fmt.Printf("⚠️  Parsing for %s not yet implemented. Passing raw content in wrapper.\n", fileType)
-		// Return a generic wrapper for now
-		output = map[string]interface{}{
-			"fileType": fileType,
-			"raw":      string(content),
-		}
-
-	default:
-		// Fallback detection (legacy)
-		if parser.ParseFileType(string(content)) == "" {
-			fmt.Println("⚠️  No FILE_TYPE found. Attempting heuristic detection...")
-			// TODO: Add heuristic or default to DetailedTimeline logic as it was default before?
-			// The original code assumed DetailedTimeline because it called parser.ParseDetailedTimeline directly.
-			// Let's fallback to that for backward compatibility.
-			doc, parseErr := parser.ParseDetailedTimeline(string(content))
-			if parseErr != nil {
-				log.Fatalf("Error parsing (fallback): %v\n", parseErr)
-			}
-			fmt.Printf("📊 Parsed (fallback): %d phases\n", len(doc.Phases))
-			output = map[string]interface{}{
-				"phases":           doc.Phases,
-				"longTermTracking": doc.LongTermTracking,
-				"notes":            doc.Notes,
-			}
-		} else {
-			log.Fatalf("Unknown file type: %s\n", fileType)
-		}
+
+	handler, err := parser.FileTypeHandler(string(content))
+	if err != nil {
+		log.Fatalf("Error: %v\n", err)
+	}
+
+	fmt.Printf("🔍 Detected File Type: %s\n", handler.FileType)
+
+	if parser.ParseFileType(string(content)) == "" {
+		fmt.Printf("⚠️  No FILE_TYPE found, parsing as %s from its sections\n", handler.Name)
+	}
+
+	doc, diags, err := handler.Parse(parser, string(content))
+	if err != nil {
+		log.Fatalf("Error parsing %s: %v\n", handler.Name, err)
+	}
+
+	fmt.Printf("📊 Parsed %s: %s\n", handler.Name, doc.Summary())
+	diags.Fprint(os.Stdout)
+
+	// The output is still written, so a draft can be inspected before it is complete
+	if validateErr := doc.Validate(); validateErr != nil {
+		fmt.Printf("⚠️  Validation: %v\n", validateErr)
+	}
+
+	output, err := doc.Normalize()
+	if err != nil {
+		log.Fatalf("Error normalizing %s: %v\n", handler.Name, err)
 	}
 
 	// Ensure directory exists
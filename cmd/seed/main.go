@@ -3,15 +3,22 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"os/exec"
+	"os/signal"
 	"path/filepath"
 	"strings"
+	"syscall"
 	"time"
+
+	"tpwfc/internal/crawler"
+	"tpwfc/internal/crawler/parsers"
+	"tpwfc/pkg/utils"
 )
 
 // ANSI color codes for terminal output.
@@ -51,8 +58,12 @@ func main() {
 	// Parse configuration from flags and environment
 	cfg := parseConfig()
 
+	// Abort health checks and child processes on Ctrl-C or SIGTERM
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	// Wait for web service
-	if !waitForWeb(cfg) {
+	if !waitForWeb(ctx, cfg) {
 		logError("Aborting seeding - web service not available")
 		os.Exit(1)
 	}
@@ -70,17 +81,38 @@ func main() {
 
 	// Run formatter on source files
 	logInfo("Formatting source markdown files...")
-	runFormatter(cfg)
+	runFormatter(ctx, cfg)
+
+	// Run crawler, with a report directory of its own so only this run's report is read
+	reportDir, err := newCrawlReportDir(cfg)
+	if err != nil {
+		logError(fmt.Sprintf("Failed to create crawl report directory: %v", err))
+		os.Exit(1)
+	}
 
-	// Run crawler
 	logInfo("Running crawler...")
-	if err := runCrawler(cfg); err != nil {
-		logError(fmt.Sprintf("Crawler failed: %v", err))
+	crawlErr := runCrawler(ctx, cfg, reportDir)
+
+	// The crawler has exited, so its report is complete (or absent)
+	report := reportCrawl(reportDir)
+
+	if crawlErr != nil {
+		logError(fmt.Sprintf("Crawler failed: %v", crawlErr))
 		os.Exit(1)
 	}
 
-	// Upload timelines for each language
-	uploadTimelines(cfg)
+	if report == nil {
+		logError("Crawler exited without writing a crawl report, cannot tell which timelines to upload")
+		os.Exit(1)
+	}
+
+	// Upload every timeline the crawler saved
+	uploadTimelines(ctx, cfg, report)
+
+	if ctx.Err() != nil {
+		logError(fmt.Sprintf("Seeding interrupted: %v", ctx.Err()))
+		os.Exit(1)
+	}
 
 	logInfo("===========================================")
 	logInfo("Seeding complete!")
@@ -117,14 +149,34 @@ func parseConfig() Config {
 	}
 }
 
-func waitForWeb(cfg Config) bool {
+// subprocessWaitDelay bounds how long a child process may take to exit after it is sent SIGTERM
+// on cancellation; after that it is killed.
+var subprocessWaitDelay = 30 * time.Second
+
+// commandContext is exec.CommandContext, except that cancelling ctx sends SIGTERM so the child can
+// finish its current write and exit cleanly, and kills it only after subprocessWaitDelay.
+func commandContext(ctx context.Context, name string, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Cancel = func() error { return cmd.Process.Signal(syscall.SIGTERM) }
+	cmd.WaitDelay = subprocessWaitDelay
+
+	return cmd
+}
+
+func waitForWeb(ctx context.Context, cfg Config) bool {
 	startTime := time.Now()
 	logInfo(fmt.Sprintf("Waiting for web service at %s...", cfg.WebURL))
 
 	client := &http.Client{Timeout: 5 * time.Second}
 
 	for {
-		resp, err := client.Get(cfg.WebURL)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, cfg.WebURL, http.NoBody)
+		if err != nil {
+			logError(fmt.Sprintf("Invalid web service URL: %v", err))
+			return false
+		}
+
+		resp, err := client.Do(req)
 		if err == nil {
 			statusCode := resp.StatusCode
 			// Close body immediately after reading status
@@ -135,10 +187,12 @@ func waitForWeb(cfg Config) bool {
 				logInfo(fmt.Sprintf("Web service is ready! (HTTP %d)", statusCode))
 				// Wait for database schema initialization (Payload push: true)
 				logInfo("Waiting for database schema initialization...")
-				time.Sleep(15 * time.Second)
+				if utils.SleepContext(ctx, 15*time.Second) != nil {
+					return false
+				}
 
 				// Verify GraphQL is actually ready by testing introspection
-				if waitForGraphQL(cfg, client) {
+				if waitForGraphQL(ctx, cfg, client) {
 					return true
 				}
 				logWarn("GraphQL not ready after initial wait, continuing to retry...")
@@ -152,17 +206,21 @@ func waitForWeb(cfg Config) bool {
 		}
 
 		fmt.Print(".")
-		time.Sleep(2 * time.Second)
+
+		if utils.SleepContext(ctx, 2*time.Second) != nil {
+			logError("Interrupted while waiting for web service")
+			return false
+		}
 	}
 }
 
 // waitForGraphQL verifies the GraphQL endpoint is responding with valid schema.
-func waitForGraphQL(cfg Config, client *http.Client) bool {
+func waitForGraphQL(ctx context.Context, cfg Config, client *http.Client) bool {
 	// Simple introspection query to verify schema is loaded
 	query := `{"query": "{ __typename }"}`
 
 	for i := 0; i < 5; i++ {
-		req, err := http.NewRequest(http.MethodPost, cfg.GraphQLEndpoint, strings.NewReader(query))
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, cfg.GraphQLEndpoint, strings.NewReader(query))
 		if err != nil {
 			continue
 		}
@@ -170,7 +228,9 @@ func waitForGraphQL(cfg Config, client *http.Client) bool {
 
 		resp, err := client.Do(req)
 		if err != nil {
-			time.Sleep(2 * time.Second)
+			if utils.SleepContext(ctx, 2*time.Second) != nil {
+				return false
+			}
 			continue
 		}
 
@@ -186,58 +246,110 @@ func waitForGraphQL(cfg Config, client *http.Client) bool {
 		}
 
 		logWarn(fmt.Sprintf("GraphQL not ready (attempt %d/5), waiting...", i+1))
-		time.Sleep(3 * time.Second)
+		if utils.SleepContext(ctx, 3*time.Second) != nil {
+			return false
+		}
 	}
 
 	return false
 }
 
-func runFormatter(cfg Config) {
+func runFormatter(ctx context.Context, cfg Config) {
 	formatterPath := filepath.Join(cfg.BinDir, "formatter")
 	sourcePath := filepath.Join(cfg.DataDir, "source")
 
-	cmd := exec.Command(formatterPath, "-path", sourcePath, "-write")
+	cmd := commandContext(ctx, formatterPath, "-path", sourcePath, "-write")
 	// Ignore errors - matches original script behavior
 	_ = cmd.Run()
 }
 
-func runCrawler(cfg Config) error {
+func runCrawler(ctx context.Context, cfg Config, reportDir string) error {
 	crawlerPath := filepath.Join(cfg.BinDir, "crawler")
 
-	cmd := exec.Command(crawlerPath, "-config", cfg.ConfigPath)
+	cmd := commandContext(ctx, crawlerPath, "-config", cfg.ConfigPath, "-report-dir", reportDir)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 
 	return cmd.Run()
 }
 
-func uploadTimelines(cfg Config) {
-	languages := []struct {
-		code    string
-		dirName string
-	}{
-		{code: "zh-hk", dirName: "zh-hk"},
-		{code: "en-us", dirName: "en-us"},
-		{code: "zh-cn", dirName: "zh-cn"},
+// newCrawlReportDir creates a fresh directory under {data-dir}/reports for the crawler's run
+// report, so a report left by an earlier or concurrent run is never mistaken for this one.
+func newCrawlReportDir(cfg Config) (string, error) {
+	parent := filepath.Join(cfg.DataDir, "reports")
+	if err := os.MkdirAll(parent, 0755); err != nil {
+		return "", err
 	}
 
-	for _, lang := range languages {
-		jsonPath := filepath.Join(cfg.DataDir, "fire", "WANG_FUK_COURT_FIRE_2025", lang.dirName, "timeline.json")
+	return os.MkdirTemp(parent, "seed-"+time.Now().UTC().Format("20060102T150405Z")+"-")
+}
+
+// reportCrawl summarizes the report the crawler wrote to reportDir and warns about sources
+// that were not saved. It must only be called once the crawler has exited, and returns nil
+// when the report cannot be read.
+func reportCrawl(reportDir string) *crawler.CrawlReport {
+	reportPath := filepath.Join(reportDir, crawler.ReportLatestFile)
 
-		if _, err := os.Stat(jsonPath); os.IsNotExist(err) {
-			logWarn(fmt.Sprintf("Timeline not found for %s, skipping: %s", lang.code, jsonPath))
+	report, err := crawler.LoadCrawlReport(reportPath)
+	if err != nil {
+		logWarn(fmt.Sprintf("Crawl report unavailable: %v", err))
+		return nil
+	}
+
+	t := report.Totals
+	logInfo(fmt.Sprintf("Crawl report: %d sources, %d saved, %d skipped, %d failed, %d events, %d rows rejected, %d/%d attempts failed (%s)",
+		t.Sources, t.Saved, t.Skipped, t.Failed, t.Events, t.RowsRejected, t.FailedAttempts, t.Attempts, reportPath))
+
+	for _, src := range report.Sources {
+		if src.Error != "" {
+			logWarn(fmt.Sprintf("  %s (%s/%s) %s: %s", src.Name, src.FireID, src.Language, src.Status, src.Error))
+		} else if src.UsedLocalFile {
+			logInfo(fmt.Sprintf("  %s (%s/%s) served from local file %s", src.Name, src.FireID, src.Language, src.FetchedFrom))
+		}
+
+		if src.RowsRejected > 0 {
+			logWarn(fmt.Sprintf("  %s (%s/%s) rejected %d rows: %s", src.Name, src.FireID, src.Language, src.RowsRejected, src.RejectsPath))
+		}
+	}
+
+	return report
+}
+
+// uploadTimelines uploads every timeline the crawl report lists as saved, wherever output.structure
+// put it, so new incidents and languages discovered by the crawler are seeded without code changes.
+// Detailed timelines need a CMS incident ID and are left to the detailed uploader.
+func uploadTimelines(ctx context.Context, cfg Config, report *crawler.CrawlReport) {
+	uploaded := make(map[string]bool)
+
+	for _, src := range report.Sources {
+		if ctx.Err() != nil {
+			return
+		}
+
+		if src.Status != crawler.ReportStatusSaved || src.OutputPath == "" || uploaded[src.OutputPath] {
 			continue
 		}
 
-		logInfo(fmt.Sprintf("Uploading %s timeline...", lang.code))
+		if src.FileType != "" && src.FileType != parsers.FileTypeTimeline {
+			logInfo(fmt.Sprintf("Skipping %s output for %s (%s/%s): %s", src.FileType, src.Name, src.FireID, src.Language, src.OutputPath))
+			continue
+		}
+
+		uploaded[src.OutputPath] = true
 
-		if err := runUploader(cfg, jsonPath, lang.code); err != nil {
-			logError(fmt.Sprintf("Failed to upload %s timeline: %v", lang.code, err))
+		logInfo(fmt.Sprintf("Uploading %s %s timeline...", src.FireID, src.Language))
+
+		if err := runUploader(ctx, cfg, src.OutputPath, src.Language); err != nil {
+			logError(fmt.Sprintf("Failed to upload %s %s timeline: %v", src.FireID, src.Language, err))
 		}
 	}
+
+	if len(uploaded) == 0 {
+		logWarn("No saved timelines in the crawl report")
+	}
 }
 
-func runUploader(cfg Config, inputPath, language string) error {
+func runUploader(ctx context.Context, cfg Config, inputPath, language string) error {
 	uploaderPath := filepath.Join(cfg.BinDir, "uploader")
 
 	args := []string{
@@ -249,7 +361,7 @@ func runUploader(cfg Config, inputPath, language string) error {
 		"--language", language,
 	}
 
-	cmd := exec.Command(uploaderPath, args...)
+	cmd := commandContext(ctx, uploaderPath, args...)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 
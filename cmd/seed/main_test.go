@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCommandContext_TerminatesOnCancel(t *testing.T) {
+	marker := filepath.Join(t.TempDir(), "terminated")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	// The child traps SIGTERM and records that it got the chance to clean up.
+	cmd := commandContext(ctx, "sh", "-c", `trap 'echo done > "$0"; exit 0' TERM; sleep 5 & wait`, marker)
+
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	time.Sleep(200 * time.Millisecond)
+	cancel()
+
+	start := time.Now()
+	_ = cmd.Wait()
+
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Errorf("child took %v to exit after SIGTERM", elapsed)
+	}
+
+	if _, err := os.Stat(marker); err != nil {
+		t.Errorf("child did not handle SIGTERM: %v", err)
+	}
+}
+
+func TestCommandContext_KillsAfterWaitDelay(t *testing.T) {
+	defer func(d time.Duration) { subprocessWaitDelay = d }(subprocessWaitDelay)
+
+	subprocessWaitDelay = 300 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	// The child ignores SIGTERM, so only the kill after WaitDelay stops it.
+	cmd := commandContext(ctx, "sh", "-c", `trap '' TERM; while :; do sleep 0.1; done`)
+
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	time.Sleep(200 * time.Millisecond)
+	cancel()
+
+	start := time.Now()
+	if err := cmd.Wait(); err == nil {
+		t.Error("Wait() error = nil, want the child to be killed")
+	}
+
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Errorf("child took %v to be killed", elapsed)
+	}
+}
@@ -9,7 +9,6 @@ import (
 
 	"tpwfc/internal/config"
 	"tpwfc/internal/crawler/parsers"
-	"tpwfc/internal/normalizer"
 	"tpwfc/internal/validator"
 	"tpwfc/pkg/metadata"
 )
@@ -61,61 +60,33 @@ func main() {
 
 	// 2. Parse and Validate Structure
 	parser := parsers.NewParser()
-	fileType := parser.ParseFileType(content)
-	fmt.Printf("🔍 Detected File Type: %s\n", fileType)
 
-	valid := false
+	handler, err := parser.FileTypeHandler(content)
+	if err != nil {
+		log.Fatalf("❌ Unsupported file type for signing: %v\n", err)
+	}
 
-	switch fileType {
-	case "DETAILED_TIMELINE":
-		doc, parseErr := parser.ParseDetailedTimeline(content)
-		if parseErr != nil {
-			log.Fatalf("❌ Parse Error (Detailed Timeline): %v\n", parseErr)
-		}
+	fmt.Printf("🔍 Detected File Type: %s\n", handler.FileType)
 
-		// Optional: Add specific validation logic for DetailedTimeline here if needed
-		// For now, if it parses successfully, we consider it structurally valid enough to sign
-		// But let's check basic fields
-		if len(doc.Phases) == 0 && len(doc.LongTermTracking) == 0 {
-			fmt.Println("⚠️  Warning: Detailed timeline contains no phases or tracking events.")
-		} else {
-			valid = true
-		}
+	if parser.ParseFileType(content) == "" {
+		fmt.Printf("⚠️  No FILE_TYPE tag found, parsing as %s from its sections\n", handler.Name)
+	}
 
-	case "FIRE_TIMELINE":
-		doc, parseErr := parser.ParseDocument(content)
-		if parseErr != nil {
-			log.Fatalf("❌ Parse Error (Timeline): %v\n", parseErr)
-		}
+	doc, diags, parseErr := handler.Parse(parser, content)
+	if parseErr != nil {
+		log.Fatalf("❌ Parse Error (%s): %v\n", handler.Name, parseErr)
+	}
 
-		v := normalizer.NewValidator()
-		if err := v.Validate(doc); err != nil {
-			log.Fatalf("❌ Validation Error: %v\n", err)
-		}
+	diags.Fprint(os.Stdout)
+	requireNoParseErrors(diags)
+
+	valid := false
+
+	if validateErr := doc.Validate(); validateErr != nil {
+		fmt.Printf("❌ Validation Error: %v\n", validateErr)
+	} else {
 		valid = true
 		fmt.Println("✅ Validation Passed")
-
-	case "FIRE_INVESTIGATION", "FIRE_RESPONSES":
-		// TODO: Add validators for these types
-		fmt.Printf("⚠️  Validation for %s not yet implemented. Signing as unvalidated.\n", fileType)
-		valid = true // Allow signing even if specific validation is missing, but maybe set VALIDATION: FALSE?
-		// The user request implies "validate the structure AND do the signature".
-		// If we can't completely validate, we should perhaps still sign but maybe note it?
-		// Using TRUE for now if it parses, consistent with "run-sign" intent.
-
-	default:
-		// Attempt fallback parsing (legacy behavior similar to normalizer)
-		if parser.ParseFileType(content) == "" {
-			fmt.Println("⚠️  No FILE_TYPE tag found. Attempting heuristic parsing (DetailedTimeline)...")
-			_, parseErr := parser.ParseDetailedTimeline(content)
-			if parseErr != nil {
-				log.Fatalf("❌ Fallback Parse Error: %v\n", parseErr)
-			}
-			valid = true
-			fmt.Println("✅ Fallback Validation Passed")
-		} else {
-			log.Fatalf("❌ Unknown or unsupported file type for signing: %s\n", fileType)
-		}
 	}
 
 	if valid {
@@ -131,3 +102,11 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// requireNoParseErrors refuses to sign a document the parser had to drop content from,
+// since a signed document is trusted to be complete.
+func requireNoParseErrors(diags parsers.Diagnostics) {
+	if n := diags.Errors(); n > 0 {
+		log.Fatalf("❌ %d parse errors: fix the rows above before signing\n", n)
+	}
+}
@@ -0,0 +1,167 @@
+// Package main provides the snapshot command-line tool for browsing and re-parsing archived crawls.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"tpwfc/internal/config"
+	"tpwfc/internal/crawler"
+	"tpwfc/internal/crawler/parsers"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		printUsage()
+		os.Exit(1)
+	}
+
+	switch os.Args[1] {
+	case "list":
+		runList(os.Args[2:])
+	case "parse":
+		runParse(os.Args[2:])
+	case "-help", "--help", "help":
+		printUsage()
+	default:
+		fmt.Printf("❌ Unknown command: %s\n\n", os.Args[1])
+		printUsage()
+		os.Exit(1)
+	}
+}
+
+// storeFlags registers the flags that locate the snapshot archive.
+func storeFlags(fs *flag.FlagSet) (*string, *string) {
+	configFile := fs.String("config", "", "Crawler config to read advanced.snapshot_dir from")
+	dir := fs.String("dir", "", "Snapshot directory (overrides config)")
+
+	return configFile, dir
+}
+
+// openStore opens the archive from -dir, the config, or the default location.
+func openStore(configFile, dir string) *crawler.SnapshotStore {
+	storeDir := config.DefaultSnapshotDir
+
+	if configFile != "" {
+		cfg, err := config.LoadConfig(configFile)
+		if err != nil {
+			log.Fatalf("❌ Failed to load config: %v\n", err)
+		}
+
+		storeDir = cfg.GetSnapshotDir()
+	}
+
+	if dir != "" {
+		storeDir = dir
+	}
+
+	store, err := crawler.NewSnapshotStore(storeDir)
+	if err != nil {
+		log.Fatalf("❌ Failed to open snapshot store: %v\n", err)
+	}
+
+	return store
+}
+
+// runList prints the fetch history recorded in the index.
+func runList(args []string) {
+	fs := flag.NewFlagSet("list", flag.ExitOnError)
+	configFile, dir := storeFlags(fs)
+	fireID := fs.String("fire", "", "Only show snapshots for this fire ID")
+	language := fs.String("language", "", "Only show snapshots for this language")
+	source := fs.String("source", "", "Only show snapshots fetched from this URL or file")
+	_ = fs.Parse(args)
+
+	store := openStore(*configFile, *dir)
+
+	records, err := store.List(crawler.SnapshotFilter{FireID: *fireID, Language: *language, Source: *source})
+	if err != nil {
+		log.Fatalf("❌ Failed to list snapshots: %v\n", err)
+	}
+
+	fmt.Printf("🗃️  Snapshots in %s: %d\n\n", store.Dir(), len(records))
+
+	for _, r := range records {
+		fmt.Printf("  %s  %s  %s/%s  %8d bytes  %s\n",
+			r.Hash[:12], r.FetchedAt.Format("2006-01-02 15:04:05Z"), r.FireID, r.Language, r.Size, r.Source)
+	}
+}
+
+// runParse re-runs the parser on an archived body and writes the output the crawler would have produced.
+func runParse(args []string) {
+	fs := flag.NewFlagSet("parse", flag.ExitOnError)
+	configFile, dir := storeFlags(fs)
+	hash := fs.String("hash", "", "Snapshot hash or unique prefix (required)")
+	output := fs.String("output", "", "Output path (default: snapshot-<hash>.<format>)")
+	format := fs.String("format", config.FormatJSON, "Output format: json or jsonl")
+	_ = fs.Parse(args)
+
+	if *format != config.FormatJSON && *format != config.FormatJSONL {
+		log.Fatalf("❌ Invalid -format: %s\n", *format)
+	}
+
+	if *hash == "" {
+		fmt.Println("Usage: snapshot parse -hash <HASH> [-output <PATH>]")
+		fs.PrintDefaults()
+		os.Exit(1)
+	}
+
+	store := openStore(*configFile, *dir)
+
+	markdown, fullHash, err := store.Get(*hash)
+	if err != nil {
+		log.Fatalf("❌ %v\n", err)
+	}
+
+	fmt.Printf("📂 Snapshot %s (%d bytes)\n", fullHash[:12], len(markdown))
+
+	outputPath := *output
+	if outputPath == "" {
+		outputPath = "snapshot-" + fullHash[:12] + "." + *format
+	}
+
+	if outputDir := filepath.Dir(outputPath); outputDir != "." {
+		if mkdirErr := os.MkdirAll(outputDir, 0755); mkdirErr != nil {
+			log.Fatalf("❌ Could not create output directory: %v\n", mkdirErr)
+		}
+	}
+
+	parser := parsers.NewParser()
+	client := crawler.NewClientWithDeps(nil, parser, nil)
+
+	handler, err := parser.FileTypeHandler(markdown)
+	if err != nil {
+		log.Fatalf("❌ %v\n", err)
+	}
+
+	fmt.Printf("🔍 Detected File Type: %s\n", handler.FileType)
+
+	doc, diags, err := handler.Parse(parser, markdown)
+	if err != nil {
+		log.Fatalf("❌ Parse failed: %v\n", err)
+	}
+
+	fmt.Printf("📊 Parsed %s: %s\n", handler.Name, doc.Summary())
+	diags.Fprint(os.Stdout)
+
+	if err = client.SaveParsed(doc, outputPath, *format); err != nil {
+		log.Fatalf("❌ Save failed: %v\n", err)
+	}
+
+	fmt.Printf("✅ Saved to: %s\n", outputPath)
+}
+
+func printUsage() {
+	fmt.Println("Usage: ./bin/snapshot <command> [OPTIONS]")
+	fmt.Println()
+	fmt.Println("Commands:")
+	fmt.Println("  list    List archived fetches (filter with -fire, -language, -source)")
+	fmt.Println("  parse   Re-parse an archived body with its FILE_TYPE handler and save it as JSON")
+	fmt.Println()
+	fmt.Println("Examples:")
+	fmt.Println("  ./bin/snapshot list -config configs/crawler.yaml -fire WANG_FUK_COURT_FIRE_2025 -language zh-hk")
+	fmt.Println("  ./bin/snapshot parse -hash 3fa9c1 -output /tmp/timeline-2025-11-30.json")
+}
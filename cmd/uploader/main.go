@@ -2,18 +2,24 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"flag"
 	"fmt"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
 
+	"tpwfc/internal/config"
 	"tpwfc/internal/logger"
+	"tpwfc/internal/models"
 	"tpwfc/internal/payload"
 )
 
 func main() {
 	// Command line flags
-	inputFile := flag.String("input", "", "Path to timeline JSON file (required)")
+	inputFile := flag.String("input", "", "Path to timeline JSON or JSONL file (required)")
 	// Determine endpoint with fallback
 	baseURL := os.Getenv("NEXT_PUBLIC_BASE_URL")
 	if baseURL == "" {
@@ -25,12 +31,12 @@ func main() {
 	password := flag.String("password", os.Getenv("ADMIN_PASSWORD"), "Admin password for authentication")
 	signingSecret := flag.String("signing-secret", os.Getenv("SEED_SIGNING_SECRET"), "HMAC signing secret for request signatures")
 
-	// Detailed mode flags
-	incidentIDInt := flag.Int("incident-id", 0, "Fire incident ID (integer) for detailed upload")
+	// Detailed, investigation and responses mode flags
+	incidentIDInt := flag.Int("incident-id", 0, "Fire incident ID (integer) for detailed, investigation and responses uploads")
 
 	// Common flags
 	language := flag.String("language", "zh-hk", "Language code")
-	mode := flag.String("mode", "standard", "Upload mode: 'standard' or 'detailed'")
+	mode := flag.String("mode", "standard", "Upload mode: 'standard', 'detailed', 'investigation' or 'responses'")
 
 	// Statistics flags (passed from external scripts)
 	pagesCreated := flag.Int("pages-created", -1, "Number of pages created (for stats only)")
@@ -63,6 +69,10 @@ func main() {
 	uploader.SetSigningSecret(*signingSecret)
 	log.Info("HMAC request signing enabled")
 
+	// Back off on throttled responses, honouring Retry-After
+	retryPolicy := config.DefaultRetryPolicy()
+	uploader.SetRetryPolicy(&retryPolicy)
+
 	// Authenticate
 	if *email != "" && *password != "" {
 		log.Info("Attempting to authenticate...")
@@ -74,14 +84,30 @@ func main() {
 		}
 	}
 
-	if *mode == "detailed" {
-		handleDetailedUpload(uploader, log, *inputFile, *incidentIDInt, *language, *pagesCreated, *pagesUpdated)
-	} else {
-		handleStandardUpload(uploader, log, *inputFile, *language)
+	// Stop starting new uploads on Ctrl-C or SIGTERM; in-flight requests are cancelled
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	switch *mode {
+	case "detailed":
+		handleDetailedUpload(ctx, uploader, log, *inputFile, *incidentIDInt, *language, *pagesCreated, *pagesUpdated)
+	case "investigation":
+		handleInvestigationUpload(ctx, uploader, log, *inputFile, *incidentIDInt, *language)
+	case "responses":
+		handleResponsesUpload(ctx, uploader, log, *inputFile, *incidentIDInt, *language)
+	default:
+		handleStandardUpload(ctx, uploader, log, *inputFile, *language)
 	}
 }
 
-func handleStandardUpload(uploader *payload.Uploader, log *logger.Logger, inputFile, language string) {
+func handleStandardUpload(ctx context.Context, uploader *payload.Uploader, log *logger.Logger, inputFile, language string) {
+	// JSONL input is streamed: events are uploaded as they are read
+	if isJSONL(inputFile) {
+		handleStandardJSONLUpload(ctx, uploader, log, inputFile, language)
+
+		return
+	}
+
 	// Load timeline data
 	data, err := payload.LoadTimelineJSON(inputFile)
 	if err != nil {
@@ -91,6 +117,43 @@ func handleStandardUpload(uploader *payload.Uploader, log *logger.Logger, inputF
 
 	log.Info(fmt.Sprintf("Loaded timeline data: events=%d", len(data.Events)))
 
+	checkIncident(log, data)
+
+	result, err := uploader.UploadContext(ctx, data, language)
+	reportStandardUpload(log, result, err)
+}
+
+func handleStandardJSONLUpload(ctx context.Context, uploader *payload.Uploader, log *logger.Logger, inputFile, language string) {
+	f, err := os.Open(inputFile)
+	if err != nil {
+		log.Error(fmt.Sprintf("Failed to open timeline JSONL: %v", err))
+		os.Exit(1)
+	}
+
+	header, events, err := payload.ReadTimelineJSONL(f)
+	if err != nil {
+		_ = f.Close()
+		log.Error(fmt.Sprintf("Failed to load timeline JSONL: %v", err))
+		os.Exit(1)
+	}
+
+	log.Info(fmt.Sprintf("Streaming timeline data: events=%d", header.Summary.TotalEvents))
+
+	checkIncident(log, header)
+
+	result, err := uploader.UploadStreamContext(ctx, header, events, language)
+	_ = f.Close()
+
+	reportStandardUpload(log, result, err)
+}
+
+// isJSONL reports whether the input file uses the line-delimited crawler output format.
+func isJSONL(path string) bool {
+	return filepath.Ext(path) == "."+config.FormatJSONL
+}
+
+// checkIncident exits unless the timeline carries the incident fields the CMS requires.
+func checkIncident(log *logger.Logger, data *models.Timeline) {
 	// Validate required fields from JSON
 	if data.BasicInfo.IncidentID == "" {
 		log.Error("Error: basicInfo.incidentId is required in timeline JSON")
@@ -105,8 +168,9 @@ func handleStandardUpload(uploader *payload.Uploader, log *logger.Logger, inputF
 	if data.BasicInfo.Map.Name != "" {
 		log.Info(fmt.Sprintf("Map info: name=%s, url=%s", data.BasicInfo.Map.Name, data.BasicInfo.Map.URL))
 	}
+}
 
-	result, err := uploader.Upload(data, language)
+func reportStandardUpload(log *logger.Logger, result *payload.UploadResult, err error) {
 	if err != nil {
 		log.Error(fmt.Sprintf("Upload failed: %v", err))
 		os.Exit(1)
@@ -125,23 +189,15 @@ func handleStandardUpload(uploader *payload.Uploader, log *logger.Logger, inputF
 		result.EventsCreated+result.EventsUpdated, result.IncidentID)
 }
 
-func handleDetailedUpload(uploader *payload.Uploader, log *logger.Logger, inputFile string, incidentID int, language string, pagesCreated int, pagesUpdated int) {
+func handleDetailedUpload(ctx context.Context, uploader *payload.Uploader, log *logger.Logger, inputFile string, incidentID int, language string, pagesCreated int, pagesUpdated int) {
 	if incidentID == 0 {
 		log.Error("Error: --incident-id (integer) is required for detailed mode")
 		os.Exit(1)
 	}
 
-	// Read JSON file
-	jsonData, err := os.ReadFile(inputFile)
+	data, err := loadDetailedTimeline(inputFile)
 	if err != nil {
-		log.Error(fmt.Sprintf("Error reading file: %v", err))
-		os.Exit(1)
-	}
-
-	// Parse JSON into DetailedTimelineData structure
-	var data payload.DetailedTimelineData
-	if unmarshalErr := json.Unmarshal(jsonData, &data); unmarshalErr != nil {
-		log.Error(fmt.Sprintf("Error parsing JSON: %v", unmarshalErr))
+		log.Error(err.Error())
 		os.Exit(1)
 	}
 
@@ -151,7 +207,7 @@ func handleDetailedUpload(uploader *payload.Uploader, log *logger.Logger, inputF
 	// Upload detailed timeline data
 	log.Info("Uploading detailed timeline data...")
 
-	result, err := uploader.UploadDetailedTimeline(&data, incidentID, language)
+	result, err := uploader.UploadDetailedTimelineContext(ctx, data, incidentID, language)
 	if err != nil {
 		log.Error(fmt.Sprintf("Upload failed: %v", err))
 		os.Exit(1)
@@ -173,3 +229,115 @@ func handleDetailedUpload(uploader *payload.Uploader, log *logger.Logger, inputF
 		}
 	}
 }
+
+// loadDetailedTimeline reads a detailed timeline from a JSON or JSONL file.
+func loadDetailedTimeline(inputFile string) (*payload.DetailedTimelineData, error) {
+	if isJSONL(inputFile) {
+		f, err := os.Open(inputFile)
+		if err != nil {
+			return nil, fmt.Errorf("error reading file: %w", err)
+		}
+		defer f.Close()
+
+		data, err := payload.ReadDetailedTimelineJSONL(f)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing JSONL: %w", err)
+		}
+
+		return data, nil
+	}
+
+	// Read JSON file
+	jsonData, err := os.ReadFile(inputFile)
+	if err != nil {
+		return nil, fmt.Errorf("error reading file: %w", err)
+	}
+
+	// Parse JSON into DetailedTimelineData structure
+	var data payload.DetailedTimelineData
+	if err := json.Unmarshal(jsonData, &data); err != nil {
+		return nil, fmt.Errorf("error parsing JSON: %w", err)
+	}
+
+	return &data, nil
+}
+
+func handleInvestigationUpload(ctx context.Context, uploader *payload.Uploader, log *logger.Logger, inputFile string, incidentID int, language string) {
+	if incidentID == 0 {
+		log.Error("Error: --incident-id (integer) is required for investigation mode")
+		os.Exit(1)
+	}
+
+	var doc models.InvestigationDocument
+	if err := loadJSON(inputFile, &doc); err != nil {
+		log.Error(err.Error())
+		os.Exit(1)
+	}
+
+	fmt.Printf("📊 Loaded: investigation %q, %d findings\n", doc.Info.Title, len(doc.Findings))
+
+	result, err := uploader.UploadInvestigationContext(ctx, &doc, incidentID, language)
+	if err != nil {
+		log.Error(fmt.Sprintf("Upload failed: %v", err))
+		os.Exit(1)
+	}
+
+	fmt.Printf("\n✓ Upload complete!\n")
+	fmt.Printf("   Investigation %d (created: %t)\n", result.InvestigationID, result.InvestigationCreated)
+	fmt.Printf("   Findings created: %d, updated: %d\n", result.FindingsCreated, result.FindingsUpdated)
+	reportErrors(result.Errors)
+}
+
+func handleResponsesUpload(ctx context.Context, uploader *payload.Uploader, log *logger.Logger, inputFile string, incidentID int, language string) {
+	if incidentID == 0 {
+		log.Error("Error: --incident-id (integer) is required for responses mode")
+		os.Exit(1)
+	}
+
+	var doc models.ResponsesDocument
+	if err := loadJSON(inputFile, &doc); err != nil {
+		log.Error(err.Error())
+		os.Exit(1)
+	}
+
+	fmt.Printf("📊 Loaded: %d responses\n", len(doc.Responses))
+
+	result, err := uploader.UploadResponsesContext(ctx, &doc, incidentID, language)
+	if err != nil {
+		log.Error(fmt.Sprintf("Upload failed: %v", err))
+		os.Exit(1)
+	}
+
+	fmt.Printf("\n✓ Upload complete!\n")
+	fmt.Printf("   Responses created: %d, updated: %d\n", result.ResponsesCreated, result.ResponsesUpdated)
+	reportErrors(result.Errors)
+}
+
+// loadJSON reads the normalizer output at inputFile into v.
+func loadJSON(inputFile string, v any) error {
+	jsonData, err := os.ReadFile(inputFile)
+	if err != nil {
+		return fmt.Errorf("error reading file: %w", err)
+	}
+
+	if err := json.Unmarshal(jsonData, v); err != nil {
+		return fmt.Errorf("error parsing JSON: %w", err)
+	}
+
+	return nil
+}
+
+// reportErrors prints the errors of a partly failed upload and exits non-zero.
+func reportErrors(errs []error) {
+	if len(errs) == 0 {
+		return
+	}
+
+	fmt.Printf("   Errors: %d\n", len(errs))
+
+	for _, err := range errs {
+		fmt.Printf("     - %v\n", err)
+	}
+
+	os.Exit(1)
+}
@@ -2,23 +2,54 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
 	"time"
 
+	"tpwfc/internal/config"
 	"tpwfc/internal/crawler"
 	"tpwfc/internal/crawler/parsers"
 	"tpwfc/internal/logger"
-	"tpwfc/internal/models"
-	"tpwfc/internal/normalizer"
 	"tpwfc/internal/payload"
 )
 
+// Pipeline errors.
+var (
+	errNoIncidentID    = errors.New("no incident ID found in document")
+	errNoCMSIncidentID = errors.New("-incident-id is required")
+)
+
+// pipeline holds the settings of one crawl, normalize and upload pass.
+type pipeline struct {
+	log              *logger.Logger
+	scraper          *crawler.Scraper
+	parser           *parsers.Parser
+	retryPolicy      *config.RetryPolicy
+	crawlerURL       string
+	localFile        string
+	payloadURL       string
+	apiKey           string
+	email            string
+	password         string
+	language         string
+	verifyMode       string
+	quarantineDir    string
+	incidentID       int
+	allowUnvalidated bool
+	dryRun           bool
+}
+
 func main() {
 	// 1. Define Command-Line Flags
 	// ---------------------------
 	crawlerURL := flag.String("crawler-url", "", "Target Markdown URL to crawl")
+	localFile := flag.String("file", "", "Local Markdown file to process instead of -crawler-url")
 	payloadURL := flag.String("payload-url", os.Getenv("NEXT_PUBLIC_BASE_URL")+"/api/graphql", "Payload CMS GraphQL endpoint")
 	apiKey := flag.String("api-key", os.Getenv("SEED_SIGNING_SECRET"), "API key for authentication (required)")
 	email := flag.String("email", os.Getenv("ADMIN_EMAIL"), "Admin email for authentication")
@@ -26,6 +57,22 @@ func main() {
 
 	// Metadata overrides
 	language := flag.String("language", "zh-hk", "Language code (zh-hk, zh-cn, en)")
+	incidentID := flag.Int("incident-id", 0, "Fire incident ID (integer) for detailed timeline, investigation and responses documents")
+	verifyMode := flag.String("verify", config.VerifyOff, "Metadata hash verification: off, warn, refuse or quarantine")
+	allowUnvalidated := flag.Bool("allow-unvalidated", false, "With -verify, accept documents signed with VALIDATION: FALSE")
+	quarantineDir := flag.String("quarantine-dir", config.DefaultQuarantineDir, "Directory for documents rejected in quarantine mode")
+	timeout := flag.Duration("timeout", 0, "Abort the pipeline after this duration (e.g. 2m, 0 = no limit)")
+
+	// Re-run on local edits
+	watch := flag.Bool("watch", false, "With -file, re-run the pipeline whenever the file changes, until interrupted")
+	watchInterval := flag.Duration("watch-interval", crawler.DefaultWatchInterval, "How often -watch polls the file")
+	watchDebounce := flag.Duration("watch-debounce", crawler.DefaultWatchDebounce, "How long the file must stay unchanged before -watch re-runs")
+	watchUpload := flag.Bool("watch-upload", false, "With -watch, upload every change to the CMS (default: parse and validate only)")
+
+	// Network restrictions for the crawl
+	allowedHosts := flag.String("allowed-hosts", os.Getenv("CRAWLER_ALLOWED_HOSTS"), "Comma-separated hosts the crawler may fetch from, *.domain for subdomains (empty = any public host)")
+	maxRedirects := flag.Int("max-redirects", config.DefaultMaxRedirects, "Maximum redirects followed by the crawl request")
+	allowPrivate := flag.Bool("allow-private-networks", false, "Allow fetching from private, loopback and link-local addresses")
 
 	flag.Parse()
 
@@ -33,30 +80,141 @@ func main() {
 	log := logger.NewLogger("info")
 
 	// Validate Inputs
-	if *crawlerURL == "" {
-		log.Error("Please provide a crawler URL with -crawler-url flag")
+	if (*crawlerURL == "") == (*localFile == "") {
+		log.Error("Please provide either a crawler URL with -crawler-url or a local file with -file")
 		flag.PrintDefaults()
 		os.Exit(1)
 	}
 
+	if *watch && *localFile == "" {
+		log.Error("-watch requires -file")
+		os.Exit(1)
+	}
+
+	if *watchUpload && !*watch {
+		log.Error("-watch-upload requires -watch")
+		os.Exit(1)
+	}
+
+	if !config.IsValidVerifyMode(*verifyMode) {
+		log.Error(fmt.Sprintf("Invalid -verify mode: %s", *verifyMode))
+		os.Exit(1)
+	}
+
+	policy := &crawler.NetworkPolicy{
+		AllowedHosts:         config.SplitHostList(*allowedHosts),
+		MaxRedirects:         *maxRedirects,
+		AllowPrivateNetworks: *allowPrivate,
+	}
+
+	for _, host := range policy.AllowedHosts {
+		if !config.IsValidHostPattern(host) {
+			log.Error(fmt.Sprintf("Invalid -allowed-hosts entry: %s", host))
+			os.Exit(1)
+		}
+	}
+
+	source := *crawlerURL
+	if *localFile != "" {
+		source = *localFile
+	}
+
 	log.Info("🚀 Starting TPWFC Worker Pipeline")
-	log.Info(fmt.Sprintf("📍 Source: %s", *crawlerURL))
+	log.Info(fmt.Sprintf("📍 Source: %s", source))
 	log.Info(fmt.Sprintf("🎯 Target: %s", *payloadURL))
 
+	// Cancel in-flight requests on Ctrl-C, SIGTERM or timeout
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+
+	// Share one retry policy so the fetch and the upload back off the same way
+	retryPolicy := config.DefaultRetryPolicy()
+	scraper := crawler.NewScraperWithConfig(&retryPolicy, 1024)
+	scraper.SetNetworkPolicy(policy)
+
+	p := &pipeline{
+		log:              log,
+		scraper:          scraper,
+		parser:           parsers.NewParser(),
+		retryPolicy:      &retryPolicy,
+		crawlerURL:       *crawlerURL,
+		localFile:        *localFile,
+		payloadURL:       *payloadURL,
+		apiKey:           *apiKey,
+		email:            *email,
+		password:         *password,
+		language:         *language,
+		verifyMode:       *verifyMode,
+		quarantineDir:    *quarantineDir,
+		incidentID:       *incidentID,
+		allowUnvalidated: *allowUnvalidated,
+		dryRun:           *watch && !*watchUpload,
+	}
+
+	if *watch {
+		p.watch(ctx, *watchInterval, *watchDebounce)
+
+		return
+	}
+
+	if err := p.run(ctx); err != nil {
+		os.Exit(1)
+	}
+}
+
+// watch runs the pipeline, then again each time the local file changes, until ctx is done.
+// A failed run is logged and waits for the next edit. Unless -watch-upload is given, the
+// runs stop before the upload, so saving a half-edited file never reaches the CMS.
+func (p *pipeline) watch(ctx context.Context, interval, debounce time.Duration) {
+	watcher := crawler.NewFileWatcher([]string{p.localFile}, interval, debounce)
+
+	_ = p.run(ctx)
+
+	p.log.Info("👀 Watching for changes (Ctrl-C to stop)...")
+
+	_ = watcher.Watch(ctx, func(_ []string) {
+		p.log.Info(fmt.Sprintf("🔄 Changed: %s", p.localFile))
+
+		_ = p.run(ctx)
+
+		p.log.Info("👀 Watching for changes (Ctrl-C to stop)...")
+	})
+
+	p.log.Info("👋 Stopped watching")
+}
+
+// run performs one crawl, normalize and upload pass. Failures are logged before being returned.
+func (p *pipeline) run(ctx context.Context) error {
+	log := p.log
+
 	// 2. Ingestion (Crawler)
 	// ----------------------
 	log.Info("Phase 1: Ingestion (Crawling)...")
 
 	startTime := time.Now()
 
-	scraper := crawler.NewScraper()
-	parser := parsers.NewParser()
-
 	// Fetch raw content
-	markdown, err := scraper.Scrape(*crawlerURL)
+	var markdown string
+
+	var err error
+
+	if p.localFile != "" {
+		markdown, err = p.scraper.ReadLocalFile(p.localFile)
+	} else {
+		markdown, err = p.scraper.ScrapeContext(ctx, p.crawlerURL)
+	}
+
 	if err != nil {
 		log.Error(fmt.Sprintf("❌ Crawl failed: %v", err))
-		os.Exit(1)
+
+		return err
 	}
 
 	log.Info(fmt.Sprintf("✅ Fetched %d bytes in %v", len(markdown), time.Since(startTime)))
@@ -67,53 +225,78 @@ func main() {
 
 	processStart := time.Now()
 
-	doc, err := parser.ParseDocument(markdown)
+	handler, err := p.parser.FileTypeHandler(markdown)
+	if err != nil {
+		log.Error(fmt.Sprintf("❌ %v", err))
+
+		return err
+	}
+
+	log.Info(fmt.Sprintf("🔍 File Type: %s", handler.FileType))
+
+	doc, diags, err := handler.Parse(p.parser, markdown)
 	if err != nil {
 		log.Error(fmt.Sprintf("❌ Parsing failed: %v", err))
-		os.Exit(1)
+
+		return err
+	}
+
+	if n := diags.Errors(); n > 0 {
+		log.Warn(fmt.Sprintf("⚠️  %d rows could not be parsed", n))
 	}
 
-	// Check for Incident ID in document
-	if doc.BasicInfo.IncidentID == "" {
+	// Timelines create their incident; the other types are attached to an existing one
+	incidentID := doc.IncidentID()
+	if handler.NeedsIncident {
+		if p.incidentID == 0 {
+			log.Error(fmt.Sprintf("❌ -incident-id is required for %s documents", handler.Name))
+
+			return fmt.Errorf("%w: %s", errNoCMSIncidentID, handler.Name)
+		}
+
+		if incidentID == "" {
+			incidentID = strconv.Itoa(p.incidentID)
+		}
+	} else if incidentID == "" {
 		log.Error("❌ No Incident ID found in document (basicInfo.incidentId required)")
-		os.Exit(1)
+
+		return errNoIncidentID
 	}
-	log.Info(fmt.Sprintf("ℹ️  Incident ID: %s", doc.BasicInfo.IncidentID))
+	log.Info(fmt.Sprintf("ℹ️  Incident ID: %s", incidentID))
 
-	// Check for Incident Name in document
-	if doc.BasicInfo.IncidentName == "" {
-		log.Warn("⚠️  No Incident Name found in document, using incidentId as fallback")
+	// Only documents that went through the signer may reach the CMS
+	if p.verifyMode != config.VerifyOff {
+		if err = verifyDocument(log, markdown, incidentID, p.language, p.verifyMode, p.quarantineDir, p.allowUnvalidated); err != nil {
+			return err
+		}
 	}
-	log.Info(fmt.Sprintf("ℹ️  Incident Name: %s", doc.BasicInfo.IncidentName))
 
-	// Normalization using Processor
-	processor := normalizer.NewProcessor()
+	if err = doc.Validate(); err != nil {
+		log.Error(fmt.Sprintf("❌ Validation failed: %v", err))
 
-	normalizedData, err := processor.Process(doc)
-	if err != nil {
-		log.Error(fmt.Sprintf("❌ Normalization failed: %v", err))
-		os.Exit(1)
+		return err
 	}
 
-	timeline, ok := normalizedData.(*models.Timeline)
-	if !ok {
-		log.Error("❌ Normalization returned unexpected type")
-		os.Exit(1)
-	}
+	log.Info(fmt.Sprintf("✅ Parsed %s: %s in %v", handler.Name, doc.Summary(), time.Since(processStart)))
 
-	log.Info(fmt.Sprintf("✅ Parsed %d events, stats, and metadata in %v", len(timeline.Events), time.Since(processStart)))
+	if p.dryRun {
+		log.Info("🧪 Dry run: skipping upload (use -watch-upload to upload every change)")
+
+		return nil
+	}
 
 	// 4. Synchronization (Uploader)
 	// -----------------------------
 	log.Info("Phase 3: Synchronization (Uploading)...")
 
-	uploader := payload.NewUploader(*payloadURL, *apiKey, log)
+	uploader := payload.NewUploader(p.payloadURL, p.apiKey, log)
+	uploader.SetRetryPolicy(p.retryPolicy)
 
 	// Authenticate
-	if *email != "" && *password != "" {
+	if p.email != "" && p.password != "" {
 		log.Info("🔐 Authenticating...")
 
-		if authErr := uploader.Authenticate(*email, *password); authErr != nil {
+		if authErr := uploader.Authenticate(p.email, p.password); authErr != nil {
 			log.Warn(fmt.Sprintf("⚠️  Authentication failed: %v (Attempting upload anyway...)", authErr))
 		} else {
 			log.Info("✅ Authenticated successfully")
@@ -121,10 +304,11 @@ func main() {
 	}
 
 	// Upload
-	result, err := uploader.Upload(timeline, *language)
+	result, err := doc.Upload(ctx, uploader, p.incidentID, p.language)
 	if err != nil {
 		log.Error(fmt.Sprintf("❌ Upload failed: %v", err))
-		os.Exit(1)
+
+		return err
 	}
 
 	// 5. Final Report
@@ -133,9 +317,13 @@ func main() {
 	fmt.Println("\n------------------------------------------------")
 	fmt.Printf("📊 Summary Report\n")
 	fmt.Println("------------------------------------------------")
-	fmt.Printf("Incident ID: %d (%s)\n", result.IncidentID, doc.BasicInfo.IncidentID)
-	fmt.Printf("Events Created: %d\n", result.EventsCreated)
-	fmt.Printf("Events Updated: %d\n", result.EventsUpdated)
+	fmt.Printf("Incident ID: %d (%s)\n", result.IncidentID, incidentID)
+
+	for _, count := range result.Counts {
+		fmt.Printf("%s Created: %d\n", count.Name, count.Created)
+		fmt.Printf("%s Updated: %d\n", count.Name, count.Updated)
+	}
+
 	fmt.Printf("Total Duration: %v\n", time.Since(startTime))
 
 	if len(result.Errors) > 0 {
@@ -147,4 +335,29 @@ func main() {
 	}
 
 	fmt.Println("------------------------------------------------")
+
+	return nil
+}
+
+// verifyDocument checks the metadata HASH and returns an error unless the document passes or mode is warn.
+func verifyDocument(log *logger.Logger, markdown, incidentID, language, mode, quarantineDir string, allowUnvalidated bool) error {
+	verification := crawler.VerifyDocument(markdown, allowUnvalidated)
+	if verification.OK() {
+		log.Info(fmt.Sprintf("🔏 Metadata hash verified: %s", verification.Hash))
+
+		return nil
+	}
+
+	log.Warn(fmt.Sprintf("⚠️  Metadata verification failed (%s): %s", verification.Status, verification.Reason))
+
+	path, err := crawler.ApplyVerifyMode(verification, markdown, incidentID, language, mode, quarantineDir)
+	if path != "" {
+		log.Error(fmt.Sprintf("🚧 Quarantined unverified document to: %s", path))
+	}
+
+	if err != nil {
+		log.Error(fmt.Sprintf("❌ Refusing to upload unverified document: %v", err))
+	}
+
+	return err
 }
@@ -2,7 +2,10 @@ module tpwfc
 
 go 1.25.5
 
-require gopkg.in/yaml.v3 v3.0.1
+require (
+	github.com/andybalholm/brotli v1.2.0
+	gopkg.in/yaml.v3 v3.0.1
+)
 
 require github.com/clipperhouse/stringish v0.1.1 // indirect
 
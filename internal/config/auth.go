@@ -0,0 +1,87 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// Source authentication errors.
+var (
+	ErrTokenSourceConflict = errors.New("token_env and token_file are mutually exclusive")
+	ErrTokenUnavailable    = errors.New("source token is not available")
+	ErrInvalidProxy        = errors.New("proxy must be an http, https or socks5 URL")
+)
+
+// HasToken reports whether the source authenticates with a bearer token.
+func (s *SourceConfig) HasToken() bool {
+	return s.TokenEnv != "" || s.TokenFile != ""
+}
+
+// Token resolves the bearer token from token_env or token_file.
+// It returns an empty token when the source does not authenticate.
+func (s *SourceConfig) Token() (string, error) {
+	switch {
+	case s.TokenEnv != "":
+		token := strings.TrimSpace(os.Getenv(s.TokenEnv))
+		if token == "" {
+			return "", fmt.Errorf("%w: environment variable %s is empty", ErrTokenUnavailable, s.TokenEnv)
+		}
+
+		return token, nil
+	case s.TokenFile != "":
+		content, err := os.ReadFile(s.TokenFile)
+		if err != nil {
+			return "", fmt.Errorf("%w: %w", ErrTokenUnavailable, err)
+		}
+
+		token := strings.TrimSpace(string(content))
+		if token == "" {
+			return "", fmt.Errorf("%w: %s is empty", ErrTokenUnavailable, s.TokenFile)
+		}
+
+		return token, nil
+	}
+
+	return "", nil
+}
+
+// ProxyURL parses the source's proxy setting, returning nil when none is set.
+func (s *SourceConfig) ProxyURL() (*url.URL, error) {
+	if s.Proxy == "" {
+		return nil, nil
+	}
+
+	proxy, err := url.Parse(s.Proxy)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidProxy, err)
+	}
+
+	switch proxy.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrInvalidProxy, s.Proxy)
+	}
+
+	if proxy.Host == "" {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidProxy, s.Proxy)
+	}
+
+	return proxy, nil
+}
+
+// validateRequestSettings checks the token and proxy settings of one source.
+// The token itself is resolved at crawl time so a missing secret only fails its source.
+func validateRequestSettings(src *SourceConfig) error {
+	if src.TokenEnv != "" && src.TokenFile != "" {
+		return ErrTokenSourceConflict
+	}
+
+	if _, err := src.ProxyURL(); err != nil {
+		return err
+	}
+
+	return nil
+}
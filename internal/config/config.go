@@ -4,8 +4,11 @@ package config
 import (
 	"errors"
 	"fmt"
+	"math/rand/v2"
+	"net"
 	"os"
 	"regexp"
+	"strings"
 	"time"
 
 	"gopkg.in/yaml.v3"
@@ -17,17 +20,29 @@ var (
 	ErrSourceMissingURLOrFile   = errors.New("either URL or file path is required")
 	ErrSourceMissingFireID      = errors.New("fire_id is required")
 	ErrSourceMissingLanguage    = errors.New("language is required")
+	ErrSourceMissingRoot        = errors.New("root is required for discover sources")
+	ErrInvalidSourceType        = errors.New("source type must be empty, 'discover' or 'git'")
+	ErrSourceMissingRepo        = errors.New("repo is required for git sources")
+	ErrSourceMissingPath        = errors.New("path is required for git sources")
+	ErrInvalidGitRef            = errors.New("ref must not start with '-'")
 	ErrNoEnabledSources         = errors.New("at least one source must be enabled")
 	ErrInvalidMaxAttempts       = errors.New("retry.max_attempts must be at least 1")
 	ErrInvalidInitialDelay      = errors.New("retry.initial_delay_ms must be non-negative")
 	ErrInvalidBackoffMultiplier = errors.New("retry.backoff_multiplier must be >= 1.0")
 	ErrInvalidTimeout           = errors.New("retry.timeout_sec must be at least 1")
+	ErrInvalidJitter            = errors.New("retry.jitter must be one of: none, full, equal")
 	ErrMissingOutputPath        = errors.New("output.base_path or output.path is required")
 	ErrInvalidOutputFormat      = errors.New("output.format must be 'json' or 'jsonl'")
+	ErrInvalidOutputStructure   = errors.New("output.structure must name a registered output layout")
+	ErrInvalidBackupRetention   = errors.New("output.backup_retention must be non-negative")
 	ErrInvalidMinEvents         = errors.New("validation.min_events must be non-negative")
 	ErrInvalidMaxEvents         = errors.New("validation.max_events must be at least 1")
 	ErrMinExceedsMax            = errors.New("validation.min_events cannot exceed validation.max_events")
 	ErrInvalidLogLevel          = errors.New("logging.level must be one of: debug, info, warn, error")
+	ErrInvalidConcurrency       = errors.New("advanced.max_concurrent_sources must be non-negative")
+	ErrInvalidMaxRedirects      = errors.New("network.max_redirects must be non-negative")
+	ErrInvalidAllowedHost       = errors.New("network.allowed_hosts entries must be host names or *.domain wildcards")
+	ErrInvalidVerifyMode        = errors.New("verification.mode must be one of: off, warn, refuse, quarantine")
 )
 
 // Config represents the complete crawler configuration.
@@ -39,23 +54,41 @@ type Config struct {
 
 // CrawlerConfig contains crawler-specific settings.
 type CrawlerConfig struct {
-	Output     OutputConfig     `yaml:"output"`
-	Sources    []SourceConfig   `yaml:"sources"`
-	Logging    LoggingConfig    `yaml:"logging"`
-	Validation ValidationConfig `yaml:"validation"`
-	Retry      RetryPolicy      `yaml:"retry"`
+	Output       OutputConfig       `yaml:"output"`
+	Sources      []SourceConfig     `yaml:"sources"`
+	Logging      LoggingConfig      `yaml:"logging"`
+	Validation   ValidationConfig   `yaml:"validation"`
+	Retry        RetryPolicy        `yaml:"retry"`
+	Verification VerificationConfig `yaml:"verification"`
+	Network      NetworkConfig      `yaml:"network"`
 }
 
 // SourceConfig represents a timeline source.
+// A source with type "discover" is a template that ExpandSources replaces with one
+// source per document found under Root. A source with type "git" reads Path from the
+// repository at Repo as of the commit Ref resolves to (HEAD when empty).
 type SourceConfig struct {
-	FireID     string   `yaml:"fire_id"`
-	FireName   string   `yaml:"fire_name"`
-	Language   string   `yaml:"language"`
-	URL        string   `yaml:"url"`
-	File       string   `yaml:"file"`
-	Name       string   `yaml:"name"`
-	BackupURLs []string `yaml:"backup_urls"`
-	Enabled    bool     `yaml:"enabled"`
+	Type        string            `yaml:"type,omitempty"`
+	FireID      string            `yaml:"fire_id"`
+	FireName    string            `yaml:"fire_name"`
+	Language    string            `yaml:"language"`
+	URL         string            `yaml:"url"`
+	File        string            `yaml:"file"`
+	Name        string            `yaml:"name"`
+	FileType    string            `yaml:"file_type,omitempty"`
+	Root        string            `yaml:"root,omitempty"`
+	URLTemplate string            `yaml:"url_template,omitempty"`
+	Repo        string            `yaml:"repo,omitempty"`
+	Ref         string            `yaml:"ref,omitempty"`
+	Path        string            `yaml:"path,omitempty"`
+	Headers     map[string]string `yaml:"headers,omitempty"`
+	TokenEnv    string            `yaml:"token_env,omitempty"`
+	TokenFile   string            `yaml:"token_file,omitempty"`
+	TokenHosts  []string          `yaml:"token_hosts,omitempty"`
+	Proxy       string            `yaml:"proxy,omitempty"`
+	BackupURLs  []string          `yaml:"backup_urls"`
+	Fetch       bool              `yaml:"fetch,omitempty"`
+	Enabled     bool              `yaml:"enabled"`
 }
 
 // IsLocalFile returns true if this source uses a local file.
@@ -79,18 +112,46 @@ type RetryPolicy struct {
 	MaxDelayMs        int     `yaml:"max_delay_ms"`
 	BackoffMultiplier float64 `yaml:"backoff_multiplier"`
 	TimeoutSec        int     `yaml:"timeout_sec"`
+	Jitter            string  `yaml:"jitter"`
+}
+
+// Jitter strategies for retry backoff.
+const (
+	JitterNone  = "none"
+	JitterFull  = "full"
+	JitterEqual = "equal"
+)
+
+// DefaultRetryPolicy returns the retry policy used when no configuration is loaded.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:       3,
+		InitialDelayMs:    500,
+		MaxDelayMs:        30000,
+		BackoffMultiplier: 2.0,
+		TimeoutSec:        30,
+		Jitter:            JitterFull,
+	}
 }
 
 // OutputConfig defines output behavior.
 type OutputConfig struct {
-	BasePath     string `yaml:"base_path"`
-	Format       string `yaml:"format"`
-	Structure    string `yaml:"structure"`
-	Path         string `yaml:"path"`
-	PrettyPrint  bool   `yaml:"pretty_print"`
-	CreateBackup bool   `yaml:"create_backup"`
+	BasePath        string `yaml:"base_path"`
+	Format          string `yaml:"format"`
+	Structure       string `yaml:"structure"`
+	Path            string `yaml:"path"`
+	ReportDir       string `yaml:"report_dir"`
+	PrettyPrint     bool   `yaml:"pretty_print"`
+	CreateBackup    bool   `yaml:"create_backup"`
+	BackupRetention int    `yaml:"backup_retention"`
 }
 
+// Output formats.
+const (
+	FormatJSON  = "json"
+	FormatJSONL = "jsonl"
+)
+
 // ValidationConfig defines markdown validation rules.
 type ValidationConfig struct {
 	Patterns             PatternsConfig `yaml:"patterns"`
@@ -102,6 +163,34 @@ type ValidationConfig struct {
 	ValidateCasualties   bool           `yaml:"validate_casualties"`
 }
 
+// VerificationConfig defines how fetched documents are checked against their signed metadata.
+type VerificationConfig struct {
+	Mode          string `yaml:"mode"`
+	QuarantineDir string `yaml:"quarantine_dir"`
+	// AllowUnvalidated accepts documents signed with VALIDATION: FALSE, which are rejected by default.
+	AllowUnvalidated bool `yaml:"allow_unvalidated"`
+}
+
+// Verification modes.
+const (
+	VerifyOff        = "off"
+	VerifyWarn       = "warn"
+	VerifyRefuse     = "refuse"
+	VerifyQuarantine = "quarantine"
+)
+
+// DefaultQuarantineDir is used in quarantine mode when verification.quarantine_dir is unset.
+const DefaultQuarantineDir = "./data/quarantine"
+
+// NetworkConfig restricts where remote fetches may connect.
+// An empty allowlist permits any host; private, loopback and link-local addresses
+// stay blocked unless AllowPrivateNetworks is set.
+type NetworkConfig struct {
+	AllowedHosts         []string `yaml:"allowed_hosts"`
+	MaxRedirects         int      `yaml:"max_redirects"`
+	AllowPrivateNetworks bool     `yaml:"allow_private_networks"`
+}
+
 // PatternsConfig defines regex patterns for validation.
 type PatternsConfig struct {
 	Date        string `yaml:"date"`
@@ -123,15 +212,19 @@ type FeaturesConfig struct {
 	EnableNormalizationPreview bool `yaml:"enable_normalization_preview"`
 	StrictValidation           bool `yaml:"strict_validation"`
 	EnableMarkdownFormatter    bool `yaml:"enable_markdown_formatter"`
+	EnableSnapshots            bool `yaml:"enable_snapshots"`
 }
 
 // AdvancedConfig contains advanced settings.
 type AdvancedConfig struct {
-	MaxMemoryMb                int  `yaml:"max_memory_mb"`
-	ConcurrentURLAttempts      bool `yaml:"concurrent_url_attempts"`
-	ContinueOnValidationErrors bool `yaml:"continue_on_validation_errors"`
-	SaveFailedRows             bool `yaml:"save_failed_rows"`
-	BufferSizeKb               int  `yaml:"buffer_size_kb"`
+	MaxMemoryMb                int    `yaml:"max_memory_mb"`
+	ConcurrentURLAttempts      bool   `yaml:"concurrent_url_attempts"`
+	ContinueOnValidationErrors bool   `yaml:"continue_on_validation_errors"`
+	SaveFailedRows             bool   `yaml:"save_failed_rows"`
+	BufferSizeKb               int    `yaml:"buffer_size_kb"`
+	MaxConcurrentSources       int    `yaml:"max_concurrent_sources"`
+	CacheDir                   string `yaml:"cache_dir"`
+	SnapshotDir                string `yaml:"snapshot_dir"`
 }
 
 // LoadConfig loads configuration from YAML file.
@@ -178,9 +271,40 @@ func (c *Config) Validate() error {
 	enabledCount := 0
 
 	for i, src := range c.Crawler.Sources {
-		// Either URL or File must be provided
-		if src.URL == "" && src.File == "" {
-			return fmt.Errorf("%w: source[%d]", ErrSourceMissingURLOrFile, i)
+		if err := validateRequestSettings(&src); err != nil {
+			return fmt.Errorf("%w: source[%d]", err, i)
+		}
+
+		if strings.HasPrefix(src.Ref, "-") {
+			return fmt.Errorf("%w: source[%d]: %s", ErrInvalidGitRef, i, src.Ref)
+		}
+
+		switch src.Type {
+		case "":
+			// Either URL or File must be provided
+			if src.URL == "" && src.File == "" {
+				return fmt.Errorf("%w: source[%d]", ErrSourceMissingURLOrFile, i)
+			}
+		case SourceTypeGit:
+			if src.Repo == "" {
+				return fmt.Errorf("%w: source[%d]", ErrSourceMissingRepo, i)
+			}
+
+			if src.Path == "" {
+				return fmt.Errorf("%w: source[%d]", ErrSourceMissingPath, i)
+			}
+		case SourceTypeDiscover:
+			if src.Root == "" {
+				return fmt.Errorf("%w: source[%d]", ErrSourceMissingRoot, i)
+			}
+
+			if src.Enabled {
+				enabledCount++
+			}
+
+			continue
+		default:
+			return fmt.Errorf("%w: source[%d]: %s", ErrInvalidSourceType, i, src.Type)
 		}
 
 		if src.FireID == "" {
@@ -217,15 +341,34 @@ func (c *Config) Validate() error {
 		return ErrInvalidTimeout
 	}
 
+	switch c.Crawler.Retry.Jitter {
+	case "", JitterNone, JitterFull, JitterEqual:
+	default:
+		return ErrInvalidJitter
+	}
+
+	if !IsValidVerifyMode(c.Crawler.Verification.Mode) {
+		return ErrInvalidVerifyMode
+	}
+
 	// Validate output config
 	if c.Crawler.Output.BasePath == "" && c.Crawler.Output.Path == "" {
 		return ErrMissingOutputPath
 	}
 
-	if c.Crawler.Output.Format != "json" && c.Crawler.Output.Format != "jsonl" {
+	if c.Crawler.Output.Format != FormatJSON && c.Crawler.Output.Format != FormatJSONL {
 		return ErrInvalidOutputFormat
 	}
 
+	if c.Crawler.Output.BackupRetention < 0 {
+		return ErrInvalidBackupRetention
+	}
+
+	if _, ok := outputLayouts[c.Crawler.Output.Structure]; !ok && c.Crawler.Output.Structure != "" {
+		return fmt.Errorf("%w: %s (known: %s)", ErrInvalidOutputStructure,
+			c.Crawler.Output.Structure, strings.Join(OutputLayouts(), ", "))
+	}
+
 	// Validate validation config
 	if c.Crawler.Validation.MinEvents < 0 {
 		return ErrInvalidMinEvents
@@ -267,9 +410,49 @@ func (c *Config) Validate() error {
 		return ErrInvalidLogLevel
 	}
 
+	// Validate advanced config
+	if c.Advanced.MaxConcurrentSources < 0 {
+		return ErrInvalidConcurrency
+	}
+
+	// Validate network config
+	if c.Crawler.Network.MaxRedirects < 0 {
+		return ErrInvalidMaxRedirects
+	}
+
+	for _, host := range c.Crawler.Network.AllowedHosts {
+		if !IsValidHostPattern(host) {
+			return fmt.Errorf("%w: %q", ErrInvalidAllowedHost, host)
+		}
+	}
+
 	return nil
 }
 
+// SplitHostList parses a comma-separated host allowlist, dropping empty entries.
+func SplitHostList(list string) []string {
+	var hosts []string
+
+	for host := range strings.SplitSeq(list, ",") {
+		if host = strings.TrimSpace(host); host != "" {
+			hosts = append(hosts, host)
+		}
+	}
+
+	return hosts
+}
+
+// IsValidHostPattern reports whether pattern is a bare host name, an IP address or
+// a "*.domain" wildcard, without scheme, port or path.
+func IsValidHostPattern(pattern string) bool {
+	host := strings.TrimPrefix(pattern, "*.")
+	if host == "" || strings.ContainsAny(host, "*/:@ ") {
+		return net.ParseIP(host) != nil
+	}
+
+	return true
+}
+
 // GetEnabledSources returns only enabled sources.
 func (c *Config) GetEnabledSources() []SourceConfig {
 	var enabled []SourceConfig
@@ -302,20 +485,162 @@ func (rp *RetryPolicy) GetRetryDelay(attempt int) time.Duration {
 	return time.Duration(int(delayMs)) * time.Millisecond
 }
 
+// BackoffDelay returns the wait before retrying after the given failed attempt.
+// The exponential delay is randomized according to the jitter strategy (full when unset,
+// as in DefaultRetryPolicy and configs/crawler.yaml); a server
+// Retry-After hint (0 if absent) raises the delay to at least that value.
+// The result never exceeds max_delay_ms when it is set.
+func (rp *RetryPolicy) BackoffDelay(attempt int, retryAfter time.Duration) time.Duration {
+	delay := rp.GetRetryDelay(attempt)
+
+	switch rp.Jitter {
+	case "", JitterFull:
+		delay = randDuration(delay)
+	case JitterEqual:
+		delay = delay/2 + randDuration(delay-delay/2)
+	}
+
+	delay = max(delay, retryAfter)
+
+	if maxDelay := time.Duration(rp.MaxDelayMs) * time.Millisecond; rp.MaxDelayMs > 0 && delay > maxDelay {
+		delay = maxDelay
+	}
+
+	return delay
+}
+
+// randDuration returns a random duration in [0, d].
+func randDuration(d time.Duration) time.Duration {
+	if d <= 0 {
+		return 0
+	}
+
+	return rand.N(d + 1)
+}
+
 // GetTimeout returns the timeout duration.
 func (rp *RetryPolicy) GetTimeout() time.Duration {
 	return time.Duration(rp.TimeoutSec) * time.Second
 }
 
-// GetOutputPath follows structure: {base_path}/{fire_id}/{language}/timeline.{format}.
-func (c *Config) GetOutputPath(fireID, language string) string {
+// DefaultMaxConcurrentSources is the worker pool size when concurrent crawling is enabled without a size.
+const DefaultMaxConcurrentSources = 4
+
+// GetConcurrency returns how many sources may be crawled at once (1 when concurrency is disabled).
+func (c *Config) GetConcurrency() int {
+	if !c.Advanced.ConcurrentURLAttempts {
+		return 1
+	}
+
+	if c.Advanced.MaxConcurrentSources > 0 {
+		return c.Advanced.MaxConcurrentSources
+	}
+
+	return DefaultMaxConcurrentSources
+}
+
+// DefaultCacheDir is used when features.enable_caching is on but advanced.cache_dir is unset.
+const DefaultCacheDir = "./data/cache"
+
+// GetCacheDir returns the response cache directory.
+func (c *Config) GetCacheDir() string {
+	if c.Advanced.CacheDir != "" {
+		return c.Advanced.CacheDir
+	}
+
+	return DefaultCacheDir
+}
+
+// IsValidVerifyMode reports whether mode is a known verification mode; empty means off.
+func IsValidVerifyMode(mode string) bool {
+	switch mode {
+	case "", VerifyOff, VerifyWarn, VerifyRefuse, VerifyQuarantine:
+		return true
+	}
+
+	return false
+}
+
+// GetVerifyMode returns the verification mode, defaulting to off.
+func (c *Config) GetVerifyMode() string {
+	if c.Crawler.Verification.Mode == "" {
+		return VerifyOff
+	}
+
+	return c.Crawler.Verification.Mode
+}
+
+// GetQuarantineDir returns the directory rejected documents are copied to.
+func (c *Config) GetQuarantineDir() string {
+	if c.Crawler.Verification.QuarantineDir != "" {
+		return c.Crawler.Verification.QuarantineDir
+	}
+
+	return DefaultQuarantineDir
+}
+
+// DefaultSnapshotDir is used when features.enable_snapshots is on but advanced.snapshot_dir is unset.
+const DefaultSnapshotDir = "./data/snapshots"
+
+// GetSnapshotDir returns the raw snapshot archive directory.
+func (c *Config) GetSnapshotDir() string {
+	if c.Advanced.SnapshotDir != "" {
+		return c.Advanced.SnapshotDir
+	}
+
+	return DefaultSnapshotDir
+}
+
+// DefaultReportDir is where crawl reports go when output.report_dir is unset.
+const DefaultReportDir = "./data/reports"
+
+// GetReportDir returns the crawl report directory.
+func (c *Config) GetReportDir() string {
+	if c.Crawler.Output.ReportDir != "" {
+		return c.Crawler.Output.ReportDir
+	}
+
+	return DefaultReportDir
+}
+
+// DefaultBackupRetention is how many backups per output file are kept when output.backup_retention is unset.
+const DefaultBackupRetention = 5
+
+// GetBackupRetention returns how many timestamped backups to keep per output file (0 when backups are off).
+func (c *Config) GetBackupRetention() int {
+	if !c.Crawler.Output.CreateBackup {
+		return 0
+	}
+
+	if c.Crawler.Output.BackupRetention > 0 {
+		return c.Crawler.Output.BackupRetention
+	}
+
+	return DefaultBackupRetention
+}
+
+// DefaultMaxRedirects is how many redirects a fetch follows when network.max_redirects is unset.
+const DefaultMaxRedirects = 5
+
+// GetMaxRedirects returns how many redirects a single fetch may follow.
+func (c *Config) GetMaxRedirects() int {
+	if c.Crawler.Network.MaxRedirects > 0 {
+		return c.Crawler.Network.MaxRedirects
+	}
+
+	return DefaultMaxRedirects
+}
+
+// GetOutputPath returns where a parsed document is written, following output.structure
+// (see OutputLayouts). The file is named after the document's FILE_TYPE, or "timeline" when unknown.
+func (c *Config) GetOutputPath(fireID, language, fileType string) string {
 	if c.Crawler.Output.BasePath != "" {
-		return fmt.Sprintf("%s/%s/%s/timeline.%s",
-			c.Crawler.Output.BasePath,
-			fireID,
-			language,
-			c.Crawler.Output.Format,
-		)
+		layout, ok := outputLayouts[c.Crawler.Output.Structure]
+		if !ok {
+			layout = outputLayouts[StructureFireLanguage]
+		}
+
+		return layout(c.Crawler.Output.BasePath, fireID, language, OutputFileName(fileType)) + "." + c.Crawler.Output.Format
 	}
 	// Fallback to legacy path if specified
 	return c.Crawler.Output.Path
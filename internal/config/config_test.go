@@ -1,8 +1,11 @@
 package config
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
+	"regexp"
+	"slices"
 	"testing"
 	"time"
 )
@@ -189,6 +192,40 @@ func TestConfig_Validate_InvalidRetryMaxAttempts(t *testing.T) {
 	}
 }
 
+func TestConfig_Validate_InvalidJitter(t *testing.T) {
+	cfg := &Config{
+		Crawler: CrawlerConfig{
+			Sources: []SourceConfig{
+				{FireID: "FIRE001", Language: "en", URL: "http://example.com", Enabled: true},
+			},
+			Retry: RetryPolicy{MaxAttempts: 1, InitialDelayMs: 100, BackoffMultiplier: 1.0, TimeoutSec: 10, Jitter: "random"},
+		},
+	}
+
+	if err := cfg.Validate(); !errors.Is(err, ErrInvalidJitter) {
+		t.Fatalf("Expected ErrInvalidJitter, got %v", err)
+	}
+}
+
+func TestConfig_Validate_InvalidVerifyMode(t *testing.T) {
+	cfg := &Config{
+		Crawler: CrawlerConfig{
+			Sources: []SourceConfig{
+				{FireID: "FIRE001", Language: "en", URL: "http://example.com", Enabled: true},
+			},
+			Retry:        RetryPolicy{MaxAttempts: 1, InitialDelayMs: 100, BackoffMultiplier: 1.0, TimeoutSec: 10},
+			Output:       OutputConfig{BasePath: "./data", Format: "json"},
+			Validation:   ValidationConfig{MaxEvents: 10},
+			Logging:      LoggingConfig{Level: "info"},
+			Verification: VerificationConfig{Mode: "strict"},
+		},
+	}
+
+	if err := cfg.Validate(); !errors.Is(err, ErrInvalidVerifyMode) {
+		t.Fatalf("Expected ErrInvalidVerifyMode, got %v", err)
+	}
+}
+
 func TestConfig_Validate_InvalidBackoffMultiplier(t *testing.T) {
 	cfg := &Config{
 		Crawler: CrawlerConfig{
@@ -379,6 +416,43 @@ func TestRetryPolicy_GetRetryDelay(t *testing.T) {
 	}
 }
 
+func TestRetryPolicy_BackoffDelay(t *testing.T) {
+	base := RetryPolicy{
+		InitialDelayMs:    100,
+		MaxDelayMs:        1000,
+		BackoffMultiplier: 2.0,
+	}
+
+	tests := []struct {
+		name       string
+		jitter     string
+		retryAfter time.Duration
+		minDelay   time.Duration
+		maxDelay   time.Duration
+	}{
+		{"no jitter", JitterNone, 0, 400 * time.Millisecond, 400 * time.Millisecond},
+		{"full jitter", JitterFull, 0, 0, 400 * time.Millisecond},
+		{"unset is full jitter", "", 0, 0, 400 * time.Millisecond},
+		{"equal jitter", JitterEqual, 0, 200 * time.Millisecond, 400 * time.Millisecond},
+		{"retry-after raises delay", JitterFull, 700 * time.Millisecond, 700 * time.Millisecond, 700 * time.Millisecond},
+		{"retry-after capped", JitterNone, time.Minute, time.Second, time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rp := base
+			rp.Jitter = tt.jitter
+
+			for range 50 {
+				got := rp.BackoffDelay(3, tt.retryAfter)
+				if got < tt.minDelay || got > tt.maxDelay {
+					t.Fatalf("BackoffDelay(3, %v) = %v, want within [%v, %v]", tt.retryAfter, got, tt.minDelay, tt.maxDelay)
+				}
+			}
+		})
+	}
+}
+
 func TestRetryPolicy_GetTimeout(t *testing.T) {
 	rp := RetryPolicy{TimeoutSec: 30}
 	expected := 30 * time.Second
@@ -407,21 +481,83 @@ func TestConfig_GetEnabledSources(t *testing.T) {
 	}
 }
 
+func TestConfig_GetConcurrency(t *testing.T) {
+	tests := []struct {
+		name     string
+		advanced AdvancedConfig
+		expected int
+	}{
+		{"disabled", AdvancedConfig{MaxConcurrentSources: 8}, 1},
+		{"enabled default", AdvancedConfig{ConcurrentURLAttempts: true}, DefaultMaxConcurrentSources},
+		{"enabled custom", AdvancedConfig{ConcurrentURLAttempts: true, MaxConcurrentSources: 2}, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{Advanced: tt.advanced}
+			if got := cfg.GetConcurrency(); got != tt.expected {
+				t.Errorf("GetConcurrency() = %d, want %d", got, tt.expected)
+			}
+		})
+	}
+}
+
 func TestConfig_GetOutputPath(t *testing.T) {
-	cfg := &Config{
-		Crawler: CrawlerConfig{
-			Output: OutputConfig{
-				BasePath: "./data",
-				Format:   "json",
-			},
-		},
+	tests := []struct {
+		name      string
+		structure string
+		fileType  string
+		expected  string
+	}{
+		{"default structure", "", "FIRE_TIMELINE", "./data/FIRE001/zh-HK/fire_timeline.json"},
+		{"fire_language", StructureFireLanguage, "DETAILED_TIMELINE", "./data/FIRE001/zh-HK/detailed_timeline.json"},
+		{"language_fire", StructureLanguageFire, "FIRE_TIMELINE", "./data/zh-HK/FIRE001/fire_timeline.json"},
+		{"flat", StructureFlat, "DETAILED_TIMELINE", "./data/FIRE001_zh-HK_detailed_timeline.json"},
+		{"no file type", StructureFireLanguage, "", "./data/FIRE001/zh-HK/timeline.json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{
+				Crawler: CrawlerConfig{
+					Output: OutputConfig{
+						BasePath:  "./data",
+						Format:    "json",
+						Structure: tt.structure,
+					},
+				},
+			}
+
+			if path := cfg.GetOutputPath("FIRE001", "zh-HK", tt.fileType); path != tt.expected {
+				t.Errorf("GetOutputPath() = %v, want %v", path, tt.expected)
+			}
+		})
+	}
+}
+
+func TestConfig_Validate_InvalidOutputStructure(t *testing.T) {
+	cfg, err := LoadConfig(createTempConfigFile(t, validConfigYAML))
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+
+	cfg.Crawler.Output.Structure = "by_date"
+
+	if err := cfg.Validate(); !errors.Is(err, ErrInvalidOutputStructure) {
+		t.Errorf("Validate() error = %v, want %v", err, ErrInvalidOutputStructure)
 	}
 
-	path := cfg.GetOutputPath("FIRE001", "zh-HK")
-	expected := "./data/FIRE001/zh-HK/timeline.json"
+	RegisterOutputLayout("by_date", func(basePath, fireID, language, name string) string {
+		return basePath + "/2025/" + fireID + "-" + language + "-" + name
+	})
+	t.Cleanup(func() { delete(outputLayouts, "by_date") })
 
-	if path != expected {
-		t.Errorf("GetOutputPath() = %v, want %v", path, expected)
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() error = %v after registering layout", err)
+	}
+
+	if path := cfg.GetOutputPath("FIRE001", "en", "FIRE_TIMELINE"); path != "./output/2025/FIRE001-en-fire_timeline.json" {
+		t.Errorf("GetOutputPath() = %v", path)
 	}
 }
 
@@ -435,7 +571,7 @@ func TestConfig_GetOutputPath_LegacyFallback(t *testing.T) {
 		},
 	}
 
-	path := cfg.GetOutputPath("FIRE001", "en")
+	path := cfg.GetOutputPath("FIRE001", "en", "FIRE_TIMELINE")
 	if path != "./legacy/output.json" {
 		t.Errorf("Expected legacy path fallback, got %v", path)
 	}
@@ -510,3 +646,300 @@ func TestConfig_SaveConfig(t *testing.T) {
 		t.Error("Loaded config does not match saved config")
 	}
 }
+
+func TestConfig_Validate_DiscoverMissingRoot(t *testing.T) {
+	cfg, err := LoadConfig(createTempConfigFile(t, validConfigYAML))
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+
+	cfg.Crawler.Sources = []SourceConfig{{Type: SourceTypeDiscover, Enabled: true}}
+
+	if err := cfg.Validate(); !errors.Is(err, ErrSourceMissingRoot) {
+		t.Errorf("Validate() error = %v, want %v", err, ErrSourceMissingRoot)
+	}
+
+	cfg.Crawler.Sources = []SourceConfig{{Type: "glob", Root: "data", Enabled: true}}
+
+	if err := cfg.Validate(); !errors.Is(err, ErrInvalidSourceType) {
+		t.Errorf("Validate() error = %v, want %v", err, ErrInvalidSourceType)
+	}
+}
+
+// writeDiscoverFile creates a markdown document under root with the given FILE_TYPE marker.
+func writeDiscoverFile(t *testing.T, root, rel, fileType string) {
+	t.Helper()
+
+	path := filepath.Join(root, filepath.FromSlash(rel))
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+
+	content := "<!-- FILE_TYPE: " + fileType + " -->\n# Timeline\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write %s: %v", path, err)
+	}
+}
+
+// detectFileType mirrors parsers.Parser.ParseFileType without importing the parser.
+func detectFileType(content string) string {
+	if m := regexp.MustCompile(`<!--\s*FILE_TYPE:\s*(\w+)\s*-->`).FindStringSubmatch(content); m != nil {
+		return m[1]
+	}
+
+	return ""
+}
+
+func TestConfig_ExpandSources(t *testing.T) {
+	root := t.TempDir()
+	writeDiscoverFile(t, root, "source/zh-HK/fire/FIRE_A/timeline.md", "TIMELINE")
+	writeDiscoverFile(t, root, "source/en-US/fire/FIRE_A/timeline.md", "TIMELINE")
+	writeDiscoverFile(t, root, "source/zh-HK/fire/FIRE_A/case-study/detailed_timeline.md", "DETAILED_TIMELINE")
+	writeDiscoverFile(t, root, "source/zh-HK/fire/FIRE_A/notes.md", "TIMELINE")
+
+	cfg := &Config{Crawler: CrawlerConfig{Sources: []SourceConfig{
+		{FireID: "manual", Language: "en", URL: "http://example.com/a.md", Enabled: true},
+		{
+			Type:        SourceTypeDiscover,
+			Name:        "Data",
+			Root:        root,
+			URLTemplate: "https://example.com/main/{path}",
+			BackupURLs:  []string{"https://mirror.example.com/{path}", "https://cdn.example.com/{incident}/{locale}.md"},
+			Enabled:     true,
+		},
+	}}}
+
+	if err := cfg.ExpandSources(detectFileType); err != nil {
+		t.Fatalf("ExpandSources() error = %v", err)
+	}
+
+	if len(cfg.Crawler.Sources) != 4 {
+		t.Fatalf("ExpandSources() produced %d sources, want 4", len(cfg.Crawler.Sources))
+	}
+
+	if cfg.Crawler.Sources[0].FireID != "manual" {
+		t.Errorf("Sources[0].FireID = %q, want manual source kept first", cfg.Crawler.Sources[0].FireID)
+	}
+
+	got := cfg.Crawler.Sources[1]
+	if got.FireID != "FIRE_A" || got.Language != "en-us" || got.FileType != "TIMELINE" {
+		t.Errorf("Sources[1] = %+v, want FIRE_A/en-us TIMELINE", got)
+	}
+
+	if got.URL != "https://example.com/main/source/en-US/fire/FIRE_A/timeline.md" {
+		t.Errorf("Sources[1].URL = %q", got.URL)
+	}
+
+	wantBackups := []string{
+		"https://mirror.example.com/source/en-US/fire/FIRE_A/timeline.md",
+		"https://cdn.example.com/FIRE_A/en-US.md",
+	}
+	if !slices.Equal(got.BackupURLs, wantBackups) {
+		t.Errorf("Sources[1].BackupURLs = %q, want %q", got.BackupURLs, wantBackups)
+	}
+
+	if detailed := cfg.Crawler.Sources[2].BackupURLs; len(detailed) != 2 ||
+		detailed[0] != "https://mirror.example.com/source/zh-HK/fire/FIRE_A/case-study/detailed_timeline.md" {
+		t.Errorf("Sources[2].BackupURLs = %q, want expanded per document", detailed)
+	}
+
+	if got.File != filepath.Join(root, "source", "en-US", "fire", "FIRE_A", "timeline.md") {
+		t.Errorf("Sources[1].File = %q", got.File)
+	}
+
+	if !got.Enabled || got.Name != "Data: FIRE_A en-US timeline" {
+		t.Errorf("Sources[1] Enabled=%v Name=%q", got.Enabled, got.Name)
+	}
+
+	if cfg.Crawler.Sources[2].FileType != "DETAILED_TIMELINE" {
+		t.Errorf("Sources[2].FileType = %q, want DETAILED_TIMELINE", cfg.Crawler.Sources[2].FileType)
+	}
+}
+
+func TestDiscoverSources_FileTypeFilter(t *testing.T) {
+	root := t.TempDir()
+	writeDiscoverFile(t, root, "source/zh-HK/fire/FIRE_A/timeline.md", "TIMELINE")
+	writeDiscoverFile(t, root, "source/zh-HK/fire/FIRE_A/case-study/detailed_timeline.md", "DETAILED_TIMELINE")
+
+	sources, err := DiscoverSources(SourceConfig{Type: SourceTypeDiscover, Root: root, FileType: "DETAILED_TIMELINE"}, detectFileType)
+	if err != nil {
+		t.Fatalf("DiscoverSources() error = %v", err)
+	}
+
+	if len(sources) != 1 || sources[0].FileType != "DETAILED_TIMELINE" {
+		t.Errorf("DiscoverSources() = %+v, want only the detailed timeline", sources)
+	}
+
+	if sources[0].URL != "" {
+		t.Errorf("URL = %q, want empty without url_template", sources[0].URL)
+	}
+}
+
+func TestDiscoverSources_GitRef(t *testing.T) {
+	root := t.TempDir()
+	writeDiscoverFile(t, root, "source/zh-HK/fire/FIRE_A/timeline.md", "TIMELINE")
+
+	sources, err := DiscoverSources(SourceConfig{Type: SourceTypeDiscover, Root: root, Ref: "v2025.11", Enabled: true}, detectFileType)
+	if err != nil {
+		t.Fatalf("DiscoverSources() error = %v", err)
+	}
+
+	if len(sources) != 1 {
+		t.Fatalf("DiscoverSources() found %d sources, want 1", len(sources))
+	}
+
+	got := sources[0]
+	if got.Type != SourceTypeGit || got.Repo != root || got.Ref != "v2025.11" || got.File != "" {
+		t.Errorf("DiscoverSources() = %+v, want a git source at v2025.11", got)
+	}
+
+	if got.Path != "source/zh-HK/fire/FIRE_A/timeline.md" {
+		t.Errorf("Path = %q", got.Path)
+	}
+}
+
+func TestConfig_Validate_GitSource(t *testing.T) {
+	cfg, err := LoadConfig(createTempConfigFile(t, validConfigYAML))
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		src  SourceConfig
+		want error
+	}{
+		{"valid", SourceConfig{Type: SourceTypeGit, Repo: "../data", Ref: "main", Path: "timeline.md", FireID: "F", Language: "en"}, nil},
+		{"missing repo", SourceConfig{Type: SourceTypeGit, Path: "timeline.md", FireID: "F", Language: "en"}, ErrSourceMissingRepo},
+		{"missing path", SourceConfig{Type: SourceTypeGit, Repo: "../data", FireID: "F", Language: "en"}, ErrSourceMissingPath},
+		{"option ref", SourceConfig{Type: SourceTypeGit, Repo: "../data", Ref: "--all", Path: "timeline.md", FireID: "F", Language: "en"}, ErrInvalidGitRef},
+		{"missing fire id", SourceConfig{Type: SourceTypeGit, Repo: "../data", Path: "timeline.md", Language: "en"}, ErrSourceMissingFireID},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.src.Enabled = true
+			cfg.Crawler.Sources = []SourceConfig{tt.src}
+
+			if err := cfg.Validate(); !errors.Is(err, tt.want) {
+				t.Errorf("Validate() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfig_ExpandSources_NothingFound(t *testing.T) {
+	cfg := &Config{Crawler: CrawlerConfig{Sources: []SourceConfig{
+		{Type: SourceTypeDiscover, Root: t.TempDir(), Enabled: true},
+	}}}
+
+	if err := cfg.ExpandSources(nil); !errors.Is(err, ErrNoEnabledSources) {
+		t.Errorf("ExpandSources() error = %v, want %v", err, ErrNoEnabledSources)
+	}
+}
+
+func TestConfig_Validate_RequestSettings(t *testing.T) {
+	cfg, err := LoadConfig(createTempConfigFile(t, validConfigYAML))
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+
+	cfg.Crawler.Sources[0].TokenEnv = "DATA_TOKEN"
+	cfg.Crawler.Sources[0].TokenFile = "/run/secrets/data_token"
+
+	if err := cfg.Validate(); !errors.Is(err, ErrTokenSourceConflict) {
+		t.Errorf("Validate() error = %v, want %v", err, ErrTokenSourceConflict)
+	}
+
+	cfg.Crawler.Sources[0].TokenFile = ""
+
+	for _, proxy := range []string{"ftp://proxy:21", "http://", "://bad"} {
+		cfg.Crawler.Sources[0].Proxy = proxy
+
+		if err := cfg.Validate(); !errors.Is(err, ErrInvalidProxy) {
+			t.Errorf("Validate() with proxy %q error = %v, want %v", proxy, err, ErrInvalidProxy)
+		}
+	}
+
+	cfg.Crawler.Sources[0].Proxy = "socks5://127.0.0.1:1080"
+
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+}
+
+func TestSourceConfig_Token(t *testing.T) {
+	t.Setenv("TPWFC_TEST_TOKEN", " env-token\n")
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenFile, []byte("file-token\n"), 0600); err != nil {
+		t.Fatalf("Failed to write token file: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		src     SourceConfig
+		want    string
+		wantErr bool
+	}{
+		{"none", SourceConfig{}, "", false},
+		{"env", SourceConfig{TokenEnv: "TPWFC_TEST_TOKEN"}, "env-token", false},
+		{"env unset", SourceConfig{TokenEnv: "TPWFC_TEST_TOKEN_UNSET"}, "", true},
+		{"file", SourceConfig{TokenFile: tokenFile}, "file-token", false},
+		{"file missing", SourceConfig{TokenFile: tokenFile + ".missing"}, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.src.Token()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Token() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if err != nil && !errors.Is(err, ErrTokenUnavailable) {
+				t.Errorf("Token() error = %v, want %v", err, ErrTokenUnavailable)
+			}
+
+			if got != tt.want {
+				t.Errorf("Token() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfig_Validate_Network(t *testing.T) {
+	cfg, err := LoadConfig(createTempConfigFile(t, validConfigYAML))
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+
+	if got := cfg.GetMaxRedirects(); got != DefaultMaxRedirects {
+		t.Errorf("GetMaxRedirects() = %d, want %d", got, DefaultMaxRedirects)
+	}
+
+	cfg.Crawler.Network.MaxRedirects = -1
+
+	if err := cfg.Validate(); !errors.Is(err, ErrInvalidMaxRedirects) {
+		t.Errorf("Validate() error = %v, want %v", err, ErrInvalidMaxRedirects)
+	}
+
+	cfg.Crawler.Network.MaxRedirects = 0
+
+	for _, host := range []string{"https://raw.githubusercontent.com", "example.com/path", "host:8080", "a.*.com", ""} {
+		cfg.Crawler.Network.AllowedHosts = []string{host}
+
+		if err := cfg.Validate(); !errors.Is(err, ErrInvalidAllowedHost) {
+			t.Errorf("Validate() with host %q error = %v, want %v", host, err, ErrInvalidAllowedHost)
+		}
+	}
+
+	cfg.Crawler.Network.AllowedHosts = SplitHostList(" raw.githubusercontent.com, *.example.org,,10.0.0.1,::1")
+
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+
+	if len(cfg.Crawler.Network.AllowedHosts) != 4 {
+		t.Errorf("SplitHostList() = %v, want 4 hosts", cfg.Crawler.Network.AllowedHosts)
+	}
+}
@@ -0,0 +1,163 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Source types; the empty type reads URL, its backups and File.
+const (
+	// SourceTypeDiscover marks a source that is expanded from the data repository layout.
+	SourceTypeDiscover = "discover"
+	// SourceTypeGit marks a source read from a git repository at an exact commit.
+	SourceTypeGit = "git"
+)
+
+// discoverPatterns are the documents looked for under {root}/source/{locale}/fire/{INCIDENT_ID}/.
+var discoverPatterns = []string{
+	"timeline.md",
+	filepath.Join("case-study", "detailed_timeline.md"),
+}
+
+// FileTypeDetector reports the FILE_TYPE of a markdown document (see parsers.Parser.DetectFileType).
+type FileTypeDetector func(content string) string
+
+// ExpandSources replaces every discover source with the documents found under its root,
+// in the data repository layout source/{locale}/fire/{INCIDENT_ID}/{timeline.md,case-study/detailed_timeline.md}.
+// Discovered sources inherit Enabled and BackupURLs (expanded like url_template) from their template, read the local file,
+// and use the URL template (if any) as their primary URL. A template with ref (or fetch) set
+// yields git sources that read each document from the root repository at that ref instead.
+// A template with file_type set only keeps documents of that type. It fails if no enabled source remains after expansion.
+func (c *Config) ExpandSources(detect FileTypeDetector) error {
+	expanded := make([]SourceConfig, 0, len(c.Crawler.Sources))
+
+	for _, src := range c.Crawler.Sources {
+		if src.Type != SourceTypeDiscover {
+			expanded = append(expanded, src)
+
+			continue
+		}
+
+		found, err := DiscoverSources(src, detect)
+		if err != nil {
+			return err
+		}
+
+		expanded = append(expanded, found...)
+	}
+
+	c.Crawler.Sources = expanded
+
+	if len(c.GetEnabledSources()) == 0 {
+		return fmt.Errorf("%w: nothing discovered", ErrNoEnabledSources)
+	}
+
+	return nil
+}
+
+// DiscoverSources globs the data repository layout under tmpl.Root and builds one source per document.
+func DiscoverSources(tmpl SourceConfig, detect FileTypeDetector) ([]SourceConfig, error) {
+	var sources []SourceConfig
+
+	for _, pattern := range discoverPatterns {
+		matches, err := filepath.Glob(filepath.Join(tmpl.Root, "source", "*", "fire", "*", pattern))
+		if err != nil {
+			return nil, fmt.Errorf("failed to discover sources under %s: %w", tmpl.Root, err)
+		}
+
+		for _, path := range matches {
+			src, ok, err := discoveredSource(tmpl, path, detect)
+			if err != nil {
+				return nil, err
+			}
+
+			if ok {
+				sources = append(sources, src)
+			}
+		}
+	}
+
+	sort.Slice(sources, func(i, j int) bool { return sources[i].File < sources[j].File })
+
+	return sources, nil
+}
+
+// discoveredSource builds the source for one matched document; ok is false when it is filtered out.
+func discoveredSource(tmpl SourceConfig, path string, detect FileTypeDetector) (SourceConfig, bool, error) {
+	rel, err := filepath.Rel(tmpl.Root, path)
+	if err != nil {
+		return SourceConfig{}, false, fmt.Errorf("failed to resolve %s: %w", path, err)
+	}
+
+	// rel is source/{locale}/fire/{INCIDENT_ID}/...
+	parts := strings.Split(filepath.ToSlash(rel), "/")
+	locale, incidentID := parts[1], parts[3]
+
+	fileType := ""
+
+	if detect != nil {
+		content, readErr := os.ReadFile(path)
+		if readErr != nil {
+			return SourceConfig{}, false, fmt.Errorf("failed to read discovered source %s: %w", path, readErr)
+		}
+
+		fileType = detect(string(content))
+	}
+
+	if tmpl.FileType != "" && fileType != tmpl.FileType {
+		return SourceConfig{}, false, nil
+	}
+
+	name := incidentID + " " + locale + " " + strings.TrimSuffix(filepath.Base(path), ".md")
+	if tmpl.Name != "" {
+		name = tmpl.Name + ": " + name
+	}
+
+	src := SourceConfig{
+		FireID:     incidentID,
+		FireName:   tmpl.FireName,
+		Language:   strings.ToLower(locale),
+		File:       path,
+		Name:       name,
+		FileType:   fileType,
+		Headers:    tmpl.Headers,
+		TokenEnv:   tmpl.TokenEnv,
+		TokenFile:  tmpl.TokenFile,
+		TokenHosts: tmpl.TokenHosts,
+		Proxy:      tmpl.Proxy,
+		Enabled:    tmpl.Enabled,
+	}
+
+	// Read the committed document rather than the working tree
+	if tmpl.Ref != "" || tmpl.Fetch {
+		src.Type = SourceTypeGit
+		src.Repo = tmpl.Root
+		src.Ref = tmpl.Ref
+		src.Path = filepath.ToSlash(rel)
+		src.Fetch = tmpl.Fetch
+		src.File = ""
+	}
+
+	// Backup URLs take the same placeholders as url_template
+	replacer := strings.NewReplacer(
+		"{path}", filepath.ToSlash(rel),
+		"{locale}", locale,
+		"{incident}", incidentID,
+	)
+
+	if tmpl.URLTemplate != "" {
+		src.URL = replacer.Replace(tmpl.URLTemplate)
+	}
+
+	if len(tmpl.BackupURLs) > 0 {
+		src.BackupURLs = make([]string, len(tmpl.BackupURLs))
+		for i, backup := range tmpl.BackupURLs {
+			src.BackupURLs[i] = replacer.Replace(backup)
+		}
+	}
+
+	return src, true, nil
+}
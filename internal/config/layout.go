@@ -0,0 +1,61 @@
+package config
+
+import (
+	"sort"
+	"strings"
+)
+
+// Output structures.
+const (
+	StructureFireLanguage = "fire_language"
+	StructureLanguageFire = "language_fire"
+	StructureFlat         = "flat"
+)
+
+// OutputLayout builds an output path without extension from the base path, incident, language
+// and file name.
+type OutputLayout func(basePath, fireID, language, name string) string
+
+// outputLayouts holds the layouts selectable through output.structure.
+var outputLayouts = map[string]OutputLayout{
+	// {base_path}/{fire_id}/{language}/{name}
+	StructureFireLanguage: func(basePath, fireID, language, name string) string {
+		return basePath + "/" + fireID + "/" + language + "/" + name
+	},
+	// {base_path}/{language}/{fire_id}/{name}
+	StructureLanguageFire: func(basePath, fireID, language, name string) string {
+		return basePath + "/" + language + "/" + fireID + "/" + name
+	},
+	// {base_path}/{fire_id}_{language}_{name}
+	StructureFlat: func(basePath, fireID, language, name string) string {
+		return basePath + "/" + fireID + "_" + language + "_" + name
+	},
+}
+
+// RegisterOutputLayout makes a layout selectable as output.structure. It must be called
+// before the configuration is loaded and replaces any layout with the same name.
+func RegisterOutputLayout(name string, layout OutputLayout) {
+	outputLayouts[name] = layout
+}
+
+// OutputLayouts returns the registered layout names in sorted order.
+func OutputLayouts() []string {
+	names := make([]string, 0, len(outputLayouts))
+	for name := range outputLayouts {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// OutputFileName returns the output file name (without extension) for a FILE_TYPE,
+// e.g. FIRE_TIMELINE -> fire_timeline. Documents without a file type use "timeline".
+func OutputFileName(fileType string) string {
+	if fileType == "" {
+		return "timeline"
+	}
+
+	return strings.ToLower(fileType)
+}
@@ -0,0 +1,213 @@
+package crawler
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"tpwfc/internal/config"
+	"tpwfc/internal/crawler/parsers"
+	"tpwfc/pkg/metadata"
+)
+
+// Mirror kinds.
+const (
+	MirrorPrimary = "primary"
+	MirrorBackup  = "backup"
+	MirrorLocal   = "local"
+)
+
+// Mirror audit outcomes.
+const (
+	MirrorReference   = "reference"
+	MirrorInSync      = "in_sync"
+	MirrorStale       = "stale"
+	MirrorDiverged    = "diverged"
+	MirrorUnreachable = "unreachable"
+)
+
+// MirrorResult describes one configured location of a source.
+type MirrorResult struct {
+	LastModify    time.Time `json:"lastModify,omitzero"`
+	Err           error     `json:"-"`
+	Error         string    `json:"error,omitempty"`
+	Location      string    `json:"location"`
+	Kind          string    `json:"kind"`
+	Status        string    `json:"status"`
+	MetadataHash  string    `json:"metadataHash,omitempty"`
+	ContentHash   string    `json:"contentHash,omitempty"`
+	EventIDs      []string  `json:"-"`
+	MissingEvents []string  `json:"missingEvents,omitempty"`
+	ExtraEvents   []string  `json:"extraEvents,omitempty"`
+}
+
+// SourceAudit compares every configured location of a source against the freshest one.
+type SourceAudit struct {
+	Source  config.SourceConfig `json:"-"`
+	Mirrors []MirrorResult      `json:"mirrors"`
+}
+
+// InSync reports whether every reachable mirror matches the reference and none failed.
+func (a *SourceAudit) InSync() bool {
+	for _, m := range a.Mirrors {
+		if m.Status != MirrorReference && m.Status != MirrorInSync {
+			return false
+		}
+	}
+
+	return true
+}
+
+// AuditSource fetches the primary URL, every backup URL and the local file (or git copy) of src,
+// picks the copy with the newest LAST_MODIFY (the primary on ties) as reference,
+// and classifies the others as in sync, stale (older) or diverged.
+func AuditSource(ctx context.Context, scraper *Scraper, parser *parsers.Parser, src config.SourceConfig) *SourceAudit {
+	audit := &SourceAudit{Source: src}
+
+	opts, optsErr := RequestOptionsFor(&src)
+
+	for i, url := range src.GetAllURLs() {
+		if url == "" {
+			continue
+		}
+
+		kind := MirrorBackup
+		if i == 0 {
+			kind = MirrorPrimary
+		}
+
+		if optsErr != nil {
+			audit.Mirrors = append(audit.Mirrors, inspectMirror(parser, url, kind, "", optsErr))
+
+			continue
+		}
+
+		content, _, _, err := scraper.ScrapeWithOptionsContext(ctx, url, opts)
+		audit.Mirrors = append(audit.Mirrors, inspectMirror(parser, url, kind, content, err))
+	}
+
+	if src.File != "" {
+		content, err := scraper.ReadLocalFile(src.File)
+		audit.Mirrors = append(audit.Mirrors, inspectMirror(parser, src.File, MirrorLocal, content, err))
+	}
+
+	if src.Type == config.SourceTypeGit {
+		if doc, err := ReadGitSource(ctx, &src); err != nil {
+			audit.Mirrors = append(audit.Mirrors, inspectMirror(parser, src.Repo+":"+src.Path, MirrorGit, "", err))
+		} else {
+			audit.Mirrors = append(audit.Mirrors, inspectMirror(parser, doc.Location(), MirrorGit, doc.Content, nil))
+		}
+	}
+
+	audit.compare()
+
+	return audit
+}
+
+// inspectMirror extracts the hashes, timestamp and event IDs of one fetched copy.
+func inspectMirror(parser *parsers.Parser, location, kind, content string, err error) MirrorResult {
+	result := MirrorResult{Location: location, Kind: kind}
+	if err != nil {
+		result.Status = MirrorUnreachable
+		result.Err = err
+		result.Error = err.Error()
+
+		return result
+	}
+
+	meta, _ := metadata.Extract(content)
+	if meta != nil {
+		result.MetadataHash = meta.Hash
+		result.LastModify = meta.LastModify
+	}
+
+	result.ContentHash = metadata.CalculateHash(content)
+	result.EventIDs = documentEventIDs(parser, content)
+
+	return result
+}
+
+// compare picks the reference mirror and classifies the rest against it.
+func (a *SourceAudit) compare() {
+	ref := -1
+
+	for i, m := range a.Mirrors {
+		if m.Status == MirrorUnreachable {
+			continue
+		}
+
+		if ref < 0 || m.LastModify.After(a.Mirrors[ref].LastModify) {
+			ref = i
+		}
+	}
+
+	if ref < 0 {
+		return
+	}
+
+	reference := &a.Mirrors[ref]
+	reference.Status = MirrorReference
+
+	for i := range a.Mirrors {
+		m := &a.Mirrors[i]
+		if i == ref || m.Status == MirrorUnreachable {
+			continue
+		}
+
+		m.MissingEvents = difference(reference.EventIDs, m.EventIDs)
+		m.ExtraEvents = difference(m.EventIDs, reference.EventIDs)
+
+		switch {
+		case m.ContentHash == reference.ContentHash:
+			m.Status = MirrorInSync
+		case m.LastModify.Before(reference.LastModify):
+			m.Status = MirrorStale
+		default:
+			m.Status = MirrorDiverged
+		}
+	}
+}
+
+// documentEventIDs returns the sorted event IDs of a timeline or detailed timeline.
+func documentEventIDs(parser *parsers.Parser, content string) []string {
+	var ids []string
+
+	if parser.DetectFileType(content) == parsers.FileTypeDetailedTimeline {
+		doc, err := parser.ParseDetailedTimeline(content)
+		if err != nil {
+			return nil
+		}
+
+		for _, phase := range doc.Phases {
+			for _, event := range phase.Events {
+				ids = append(ids, event.ID)
+			}
+		}
+	} else {
+		events, err := parser.ParseMarkdownTable(content)
+		if err != nil {
+			return nil
+		}
+
+		for _, event := range events {
+			ids = append(ids, event.ID)
+		}
+	}
+
+	slices.Sort(ids)
+
+	return ids
+}
+
+// difference returns the sorted IDs in a that are not in b.
+func difference(a, b []string) []string {
+	var out []string
+
+	for _, id := range a {
+		if _, found := slices.BinarySearch(b, id); !found {
+			out = append(out, id)
+		}
+	}
+
+	return out
+}
@@ -0,0 +1,77 @@
+package crawler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"tpwfc/internal/config"
+	"tpwfc/internal/crawler/parsers"
+)
+
+const auditTable = `<!-- TIMELINE_TABLE_START -->
+
+| DATE       | TIME  | DESCRIPTION | CATEGORY | CASUALTIES | SOURCES |
+| ---------- | ----- | ----------- | -------- | ---------- | ------- |
+| 2025-01-01 | 12:00 | Event 1     | Cat 1    | DEAD:1     | S1      |
+%s
+<!-- TIMELINE_TABLE_END -->
+`
+
+// auditDoc builds a signed-looking document with the given LAST_MODIFY and extra table rows.
+func auditDoc(lastModify, extraRows string) string {
+	return strings.Replace(auditTable, "%s", extraRows, 1) +
+		"\n<!-- METADATA_START\nVALIDATION: TRUE\nLAST_MODIFY: " + lastModify + "\nHASH: x\nMETADATA_END -->\n"
+}
+
+func TestAuditSource(t *testing.T) {
+	newer := auditDoc("2025-12-02T00:00:00Z", "| 2025-01-01 | 13:00 | Event 2     | Cat 2    | INJURED:2  | S2      |")
+	older := auditDoc("2025-12-01T00:00:00Z", "")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/primary.md", "/backup.md":
+			_, _ = w.Write([]byte(newer))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	localFile := filepath.Join(t.TempDir(), "timeline.md")
+	if err := os.WriteFile(localFile, []byte(older), 0644); err != nil {
+		t.Fatalf("failed to write local file: %v", err)
+	}
+
+	src := config.SourceConfig{
+		URL:        server.URL + "/primary.md",
+		BackupURLs: []string{server.URL + "/backup.md", server.URL + "/gone.md"},
+		File:       localFile,
+	}
+
+	scraper := NewScraperWithConfig(&config.RetryPolicy{MaxAttempts: 1, TimeoutSec: 5}, 64)
+	audit := AuditSource(context.Background(), scraper, parsers.NewParser(), src)
+
+	want := []string{MirrorReference, MirrorInSync, MirrorUnreachable, MirrorStale}
+	if len(audit.Mirrors) != len(want) {
+		t.Fatalf("expected %d mirrors, got %d", len(want), len(audit.Mirrors))
+	}
+
+	for i, status := range want {
+		if audit.Mirrors[i].Status != status {
+			t.Errorf("mirror %d (%s) status = %s, want %s", i, audit.Mirrors[i].Location, audit.Mirrors[i].Status, status)
+		}
+	}
+
+	if local := audit.Mirrors[3]; len(local.MissingEvents) != 1 {
+		t.Errorf("expected local mirror to miss 1 event, got %v", local.MissingEvents)
+	}
+
+	if audit.InSync() {
+		t.Error("expected audit to report drift")
+	}
+}
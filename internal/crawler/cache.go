@@ -0,0 +1,83 @@
+package crawler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// ResponseCache stores fetched bodies and their HTTP validators on disk, one file per URL.
+type ResponseCache struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// CacheEntry is the cached response for a single URL.
+type CacheEntry struct {
+	FetchedAt    time.Time `json:"fetchedAt"`
+	URL          string    `json:"url"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"lastModified,omitempty"`
+	Body         string    `json:"body"`
+}
+
+// NewResponseCache creates a cache rooted at dir, creating the directory if needed.
+func NewResponseCache(dir string) (*ResponseCache, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory %s: %w", dir, err)
+	}
+
+	return &ResponseCache{dir: dir}, nil
+}
+
+// Get returns the cached entry for url, if any.
+func (c *ResponseCache) Get(url string) (*CacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data, err := os.ReadFile(c.entryPath(url))
+	if err != nil {
+		return nil, false
+	}
+
+	var entry CacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil || entry.URL != url {
+		return nil, false
+	}
+
+	return &entry, true
+}
+
+// Put stores entry, replacing any previous entry for the same URL.
+func (c *ResponseCache) Put(entry *CacheEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal cache entry: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := WriteFileAtomic(c.entryPath(entry.URL), data); err != nil {
+		return fmt.Errorf("failed to store cache entry: %w", err)
+	}
+
+	return nil
+}
+
+// Dir returns the cache directory.
+func (c *ResponseCache) Dir() string {
+	return c.dir
+}
+
+// entryPath maps a URL to its cache file.
+func (c *ResponseCache) entryPath(url string) string {
+	sum := sha256.Sum256([]byte(url))
+
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+".json")
+}
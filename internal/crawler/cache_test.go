@@ -0,0 +1,72 @@
+package crawler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"tpwfc/internal/config"
+)
+
+const testETag = `"v1"`
+
+func TestScraper_ConditionalFetch(t *testing.T) {
+	requests := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+
+		if r.Header.Get("If-None-Match") == testETag {
+			w.WriteHeader(http.StatusNotModified)
+
+			return
+		}
+
+		w.Header().Set("ETag", testETag)
+		_, _ = w.Write([]byte("# Timeline"))
+	}))
+	defer server.Close()
+
+	cache, err := NewResponseCache(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewResponseCache failed: %v", err)
+	}
+
+	scraper := NewScraperWithConfig(&config.RetryPolicy{MaxAttempts: 1, TimeoutSec: 5}, 64)
+	scraper.SetCache(cache)
+
+	// First fetch downloads the body and stores it
+	body, status, _, err := scraper.ScrapeWithMetrics(server.URL)
+	if err != nil {
+		t.Fatalf("first fetch failed: %v", err)
+	}
+
+	if status != http.StatusOK || body != "# Timeline" {
+		t.Fatalf("first fetch = (%d, %q), want (200, %q)", status, body, "# Timeline")
+	}
+
+	// Second fetch sends If-None-Match and is served from the cache
+	body, status, _, err = scraper.ScrapeWithMetrics(server.URL)
+	if err != nil {
+		t.Fatalf("second fetch failed: %v", err)
+	}
+
+	if status != http.StatusNotModified || body != "# Timeline" {
+		t.Errorf("second fetch = (%d, %q), want (304, %q)", status, body, "# Timeline")
+	}
+
+	if requests != 2 {
+		t.Errorf("expected 2 requests, got %d", requests)
+	}
+}
+
+func TestResponseCache_GetMissing(t *testing.T) {
+	cache, err := NewResponseCache(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewResponseCache failed: %v", err)
+	}
+
+	if _, ok := cache.Get("https://example.com/missing.md"); ok {
+		t.Error("expected cache miss for unknown URL")
+	}
+}
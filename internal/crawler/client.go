@@ -4,8 +4,8 @@ package crawler
 import (
 	"encoding/json"
 	"fmt"
-	"os"
 
+	"tpwfc/internal/config"
 	"tpwfc/internal/crawler/parsers"
 	"tpwfc/internal/models"
 )
@@ -15,6 +15,7 @@ type Client struct {
 	scraper    *Scraper
 	parser     *parsers.Parser
 	urlManager *URLManager
+	backupKeep int
 }
 
 // NewClient creates a new crawler client with default dependencies.
@@ -35,6 +36,27 @@ func NewClientWithDeps(scraper *Scraper, parser *parsers.Parser, urlManager *URL
 	}
 }
 
+// SetBackupRetention makes every save first copy the existing output to a timestamped backup,
+// keeping the newest keep backups; 0 disables backups.
+func (c *Client) SetBackupRetention(keep int) {
+	c.backupKeep = keep
+}
+
+// writeOutput backs up the previous output if enabled, then atomically replaces it with data.
+func (c *Client) writeOutput(outputPath string, data []byte) error {
+	if c.backupKeep > 0 {
+		if _, err := BackupOutput(outputPath, c.backupKeep); err != nil {
+			return fmt.Errorf("failed to back up output: %w", err)
+		}
+	}
+
+	if err := WriteFileAtomic(outputPath, data); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+
+	return nil
+}
+
 // CrawlTimeline fetches and parses a markdown timeline.
 func (c *Client) CrawlTimeline(url string) ([]models.TimelineEvent, error) {
 	// Fetch raw markdown
@@ -103,13 +125,7 @@ func (c *Client) SaveTimelineJSON(events []models.TimelineEvent, outputPath stri
 		return fmt.Errorf("failed to marshal JSON: %w", err)
 	}
 
-	// Write to file
-	err = os.WriteFile(outputPath, jsonData, 0644)
-	if err != nil {
-		return fmt.Errorf("failed to write file: %w", err)
-	}
-
-	return nil
+	return c.writeOutput(outputPath, jsonData)
 }
 
 // SaveTimelineJSONWithDocument saves timeline events with full document data to JSON file.
@@ -136,13 +152,7 @@ func (c *Client) SaveTimelineJSONWithDocument(events []models.TimelineEvent, doc
 		return fmt.Errorf("failed to marshal JSON: %w", err)
 	}
 
-	// Write to file
-	err = os.WriteFile(outputPath, jsonData, 0644)
-	if err != nil {
-		return fmt.Errorf("failed to write file: %w", err)
-	}
-
-	return nil
+	return c.writeOutput(outputPath, jsonData)
 }
 
 // SaveDetailedTimelineJSON saves detailed timeline data (phases, events, long-term tracking) to JSON file.
@@ -160,13 +170,57 @@ func (c *Client) SaveDetailedTimelineJSON(doc *models.DetailedTimelineDocument,
 		return fmt.Errorf("failed to marshal JSON: %w", err)
 	}
 
-	// Write to file
-	err = os.WriteFile(outputPath, jsonData, 0644)
+	return c.writeOutput(outputPath, jsonData)
+}
+
+// SaveDocumentJSON saves a normalized document, such as an investigation, to a JSON file.
+// Types without a line-delimited form are saved as JSON whatever the output format.
+func (c *Client) SaveDocumentJSON(doc any, outputPath string) error {
+	jsonData, err := json.MarshalIndent(doc, "", "  ")
 	if err != nil {
-		return fmt.Errorf("failed to write file: %w", err)
+		return fmt.Errorf("failed to marshal JSON: %w", err)
 	}
 
-	return nil
+	return c.writeOutput(outputPath, jsonData)
+}
+
+// SaveTimeline saves timeline events in the given output format (json or jsonl); doc may be nil.
+func (c *Client) SaveTimeline(events []models.TimelineEvent, doc *models.TimelineDocument, outputPath, format string) error {
+	switch {
+	case format == config.FormatJSONL:
+		return c.SaveTimelineJSONL(events, doc, outputPath)
+	case doc != nil:
+		return c.SaveTimelineJSONWithDocument(events, doc, outputPath)
+	default:
+		return c.SaveTimelineJSON(events, outputPath)
+	}
+}
+
+// SaveParsed saves a document parsed by its FILE_TYPE handler: timelines and detailed timelines
+// in the given output format, other types as their normalized JSON.
+func (c *Client) SaveParsed(doc parsers.Document, outputPath, format string) error {
+	switch model := doc.Model().(type) {
+	case *models.TimelineDocument:
+		return c.SaveTimeline(model.Events, model, outputPath, format)
+	case *models.DetailedTimelineDocument:
+		return c.SaveDetailedTimeline(model, outputPath, format)
+	}
+
+	normalized, err := doc.Normalize()
+	if err != nil {
+		return err
+	}
+
+	return c.SaveDocumentJSON(normalized, outputPath)
+}
+
+// SaveDetailedTimeline saves detailed timeline data in the given output format (json or jsonl).
+func (c *Client) SaveDetailedTimeline(doc *models.DetailedTimelineDocument, outputPath, format string) error {
+	if format == config.FormatJSONL {
+		return c.SaveDetailedTimelineJSONL(doc, outputPath)
+	}
+
+	return c.SaveDetailedTimelineJSON(doc, outputPath)
 }
 
 // Get fetches a URL and returns the response (legacy).
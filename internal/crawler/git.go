@@ -0,0 +1,137 @@
+package crawler
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path"
+	"strings"
+	"sync"
+
+	"tpwfc/internal/config"
+)
+
+// Git source errors.
+var (
+	// ErrGitFailed indicates a git command that exited with an error.
+	ErrGitFailed = errors.New("git command failed")
+	// ErrGitRefNotFound indicates a ref that does not resolve to a commit.
+	ErrGitRefNotFound = errors.New("git ref does not resolve to a commit")
+	// ErrGitPathNotFound indicates a path missing from the resolved commit.
+	ErrGitPathNotFound = errors.New("path not found at commit")
+)
+
+// MirrorGit is the kind of a copy read from a git repository.
+const MirrorGit = "git"
+
+// GitDocument is a file read from a git repository at an exact commit.
+type GitDocument struct {
+	Repo    string
+	Ref     string
+	Commit  string
+	Path    string
+	Content string
+}
+
+// Location returns repo@commit:path, which identifies the exact bytes read.
+func (d *GitDocument) Location() string {
+	return d.Repo + "@" + d.Commit + ":" + d.Path
+}
+
+// gitFetches remembers the outcome of fetching each repository, so sources sharing
+// a repository fetch it once per run and all resolve their ref against the same state.
+var gitFetches = struct {
+	mu   sync.Mutex
+	done map[string]error
+}{done: make(map[string]error)}
+
+// ReadGitSource reads src.Path from src.Repo as of the commit src.Ref (HEAD when empty)
+// resolves to. With src.Fetch the repository is fetched from its default remote first;
+// use a remote-tracking ref such as origin/main to crawl what was fetched.
+func ReadGitSource(ctx context.Context, src *config.SourceConfig) (*GitDocument, error) {
+	if src.Fetch {
+		if err := fetchGitRepo(ctx, src.Repo); err != nil {
+			return nil, err
+		}
+	}
+
+	ref := src.Ref
+	if ref == "" {
+		ref = "HEAD"
+	}
+
+	commit, err := ResolveGitRef(ctx, src.Repo, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	filePath := path.Clean(strings.TrimPrefix(src.Path, "./"))
+
+	content, err := runGit(ctx, src.Repo, "cat-file", "blob", commit+":"+filePath)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s at %s: %w", ErrGitPathNotFound, filePath, commit, err)
+	}
+
+	return &GitDocument{
+		Repo:    src.Repo,
+		Ref:     ref,
+		Commit:  commit,
+		Path:    filePath,
+		Content: string(content),
+	}, nil
+}
+
+// ResolveGitRef returns the full SHA of the commit ref (a branch, tag or SHA) points to in repo.
+func ResolveGitRef(ctx context.Context, repo, ref string) (string, error) {
+	if strings.HasPrefix(ref, "-") {
+		return "", fmt.Errorf("%w: %s", ErrGitRefNotFound, ref)
+	}
+
+	out, err := runGit(ctx, repo, "rev-parse", "--verify", "--quiet", ref+"^{commit}")
+	if err != nil {
+		return "", fmt.Errorf("%w: %s in %s: %w", ErrGitRefNotFound, ref, repo, err)
+	}
+
+	return strings.TrimSpace(string(out)), nil
+}
+
+// fetchGitRepo fetches repo's default remote, once per run.
+func fetchGitRepo(ctx context.Context, repo string) error {
+	gitFetches.mu.Lock()
+	defer gitFetches.mu.Unlock()
+
+	if err, ok := gitFetches.done[repo]; ok {
+		return err
+	}
+
+	_, err := runGit(ctx, repo, "fetch", "--quiet", "--tags")
+	if err != nil {
+		err = fmt.Errorf("failed to fetch %s: %w", repo, err)
+	}
+
+	gitFetches.done[repo] = err
+
+	return err
+}
+
+// runGit runs git in repo and returns its standard output.
+func runGit(ctx context.Context, repo string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", repo}, args...)...)
+
+	var stdout, stderr bytes.Buffer
+
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%w: git %s: %s", ErrGitFailed, args[0], msg)
+		}
+
+		return nil, fmt.Errorf("%w: git %s: %w", ErrGitFailed, args[0], err)
+	}
+
+	return stdout.Bytes(), nil
+}
@@ -0,0 +1,115 @@
+package crawler
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"tpwfc/internal/config"
+)
+
+// gitRepo creates a repository whose timeline.md has one version per commit,
+// tagging the first commit v1, and returns its path.
+func gitRepo(t *testing.T, versions ...string) string {
+	t.Helper()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	repo := t.TempDir()
+
+	git := func(args ...string) {
+		t.Helper()
+
+		cmd := exec.Command("git", append([]string{"-C", repo, "-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+		}
+	}
+
+	git("init", "--quiet", "--initial-branch=main")
+
+	if err := os.MkdirAll(filepath.Join(repo, "fire"), 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+
+	for i, content := range versions {
+		if err := os.WriteFile(filepath.Join(repo, "fire", "timeline.md"), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write timeline: %v", err)
+		}
+
+		git("add", ".")
+		git("commit", "--quiet", "-m", "version")
+
+		if i == 0 {
+			git("tag", "v1")
+		}
+	}
+
+	return repo
+}
+
+func TestReadGitSource(t *testing.T) {
+	repo := gitRepo(t, "# First\n", "# Second\n")
+
+	// The working tree differs from every commit
+	if err := os.WriteFile(filepath.Join(repo, "fire", "timeline.md"), []byte("# Uncommitted\n"), 0644); err != nil {
+		t.Fatalf("Failed to write timeline: %v", err)
+	}
+
+	tests := []struct {
+		ref  string
+		want string
+	}{
+		{"", "# Second\n"},
+		{"main", "# Second\n"},
+		{"v1", "# First\n"},
+		{"main~1", "# First\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run("ref="+tt.ref, func(t *testing.T) {
+			doc, err := ReadGitSource(context.Background(), &config.SourceConfig{Repo: repo, Ref: tt.ref, Path: "./fire/timeline.md"})
+			if err != nil {
+				t.Fatalf("ReadGitSource() error = %v", err)
+			}
+
+			if doc.Content != tt.want {
+				t.Errorf("Content = %q, want %q", doc.Content, tt.want)
+			}
+
+			commit, err := ResolveGitRef(context.Background(), repo, doc.Commit)
+			if err != nil || commit != doc.Commit || len(commit) != 40 {
+				t.Errorf("Commit = %q does not name a commit: %v", doc.Commit, err)
+			}
+
+			if want := repo + "@" + doc.Commit + ":fire/timeline.md"; doc.Location() != want {
+				t.Errorf("Location() = %q, want %q", doc.Location(), want)
+			}
+		})
+	}
+}
+
+func TestReadGitSource_Errors(t *testing.T) {
+	repo := gitRepo(t, "# First\n")
+
+	_, err := ReadGitSource(context.Background(), &config.SourceConfig{Repo: repo, Ref: "v9", Path: "fire/timeline.md"})
+	if !errors.Is(err, ErrGitRefNotFound) {
+		t.Errorf("unknown ref: error = %v, want %v", err, ErrGitRefNotFound)
+	}
+
+	_, err = ReadGitSource(context.Background(), &config.SourceConfig{Repo: repo, Ref: "--output=/tmp/x", Path: "fire/timeline.md"})
+	if !errors.Is(err, ErrGitRefNotFound) {
+		t.Errorf("option-like ref: error = %v, want %v", err, ErrGitRefNotFound)
+	}
+
+	_, err = ReadGitSource(context.Background(), &config.SourceConfig{Repo: repo, Path: "fire/missing.md"})
+	if !errors.Is(err, ErrGitPathNotFound) {
+		t.Errorf("missing path: error = %v, want %v", err, ErrGitPathNotFound)
+	}
+}
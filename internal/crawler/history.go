@@ -0,0 +1,296 @@
+package crawler
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"tpwfc/internal/crawler/parsers"
+	"tpwfc/internal/models"
+)
+
+// Event change kinds.
+const (
+	ChangeAdded       = "added"
+	ChangeDescription = "description"
+	ChangeCasualties  = "casualties"
+	ChangeRemoved     = "removed"
+	ChangeRestored    = "restored"
+)
+
+// GitRevision is one commit that touched a tracked file.
+type GitRevision struct {
+	Time    time.Time `json:"time"`
+	Commit  string    `json:"commit"`
+	Author  string    `json:"author"`
+	Subject string    `json:"subject"`
+	Path    string    `json:"path"`
+	// Error is set when the revision could not be parsed; it is skipped when diffing.
+	Error  string `json:"error,omitempty"`
+	Events int    `json:"events"`
+}
+
+// EventChange is one change to an event in one revision.
+type EventChange struct {
+	Time   time.Time `json:"time"`
+	Kind   string    `json:"kind"`
+	Commit string    `json:"commit"`
+	Before string    `json:"before,omitempty"`
+	After  string    `json:"after,omitempty"`
+}
+
+// EventHistory is the life of one event ID across the history of a timeline file.
+type EventHistory struct {
+	ID           string        `json:"id"`
+	Date         string        `json:"date"`
+	Time         string        `json:"time"`
+	Category     string        `json:"category"`
+	Description  string        `json:"description"`
+	IntroducedIn string        `json:"introducedIn"`
+	RemovedIn    string        `json:"removedIn,omitempty"`
+	Changes      []EventChange `json:"changes"`
+}
+
+// TimelineHistory is the per-event history of a timeline file, oldest revision first.
+type TimelineHistory struct {
+	Repo      string         `json:"repo"`
+	Ref       string         `json:"ref"`
+	Path      string         `json:"path"`
+	Revisions []GitRevision  `json:"revisions"`
+	Events    []EventHistory `json:"events"`
+}
+
+// BuildTimelineHistory walks the commits reachable from ref that touched path (following
+// renames), parses every revision with Parser.ParseDocument, and records for each event ID
+// the commit that introduced it, every change to its description or casualties, and its removal.
+// Revisions that fail to parse are listed with their error and do not count as removals.
+func BuildTimelineHistory(ctx context.Context, parser *parsers.Parser, repo, ref, path string) (*TimelineHistory, error) {
+	if ref == "" {
+		ref = "HEAD"
+	}
+
+	revisions, err := ListGitRevisions(ctx, repo, ref, path)
+	if err != nil {
+		return nil, err
+	}
+
+	history := &TimelineHistory{Repo: repo, Ref: ref, Path: path}
+	tracker := newHistoryTracker()
+
+	for _, rev := range revisions {
+		events, parseErr := revisionEvents(ctx, parser, repo, rev)
+		if parseErr != nil {
+			rev.Error = parseErr.Error()
+			history.Revisions = append(history.Revisions, rev)
+
+			continue
+		}
+
+		rev.Events = len(events)
+		history.Revisions = append(history.Revisions, rev)
+		tracker.apply(rev, events)
+	}
+
+	history.Events = tracker.histories()
+
+	return history, nil
+}
+
+// ListGitRevisions returns the commits reachable from ref that touched path, oldest first,
+// each with the file's path as of that commit.
+func ListGitRevisions(ctx context.Context, repo, ref, path string) ([]GitRevision, error) {
+	if _, err := ResolveGitRef(ctx, repo, ref); err != nil {
+		return nil, err
+	}
+
+	// --follow does not combine with --reverse, so the newest-first log is reversed below
+	out, err := runGit(ctx, repo, "log", "--follow", "--name-only",
+		"--format=%x1e%H%x1f%cI%x1f%an%x1f%s", ref, "--", path)
+	if err != nil {
+		return nil, err
+	}
+
+	var revisions []GitRevision
+
+	for record := range strings.SplitSeq(string(out), "\x1e") {
+		lines := strings.Split(strings.TrimSpace(record), "\n")
+		if len(lines) < 2 {
+			// Merges list no files unless they resolved a conflict in path
+			continue
+		}
+
+		fields := strings.Split(lines[0], "\x1f")
+		if len(fields) != 4 {
+			return nil, fmt.Errorf("%w: unexpected git log record %q", ErrGitFailed, lines[0])
+		}
+
+		committed, timeErr := time.Parse(time.RFC3339, fields[1])
+		if timeErr != nil {
+			return nil, fmt.Errorf("%w: invalid commit date %q: %w", ErrGitFailed, fields[1], timeErr)
+		}
+
+		revisions = append(revisions, GitRevision{
+			Time:    committed,
+			Commit:  fields[0],
+			Author:  fields[2],
+			Subject: fields[3],
+			Path:    strings.TrimSpace(lines[len(lines)-1]),
+		})
+	}
+
+	slices.Reverse(revisions)
+
+	return revisions, nil
+}
+
+// revisionEvents parses the file as of rev; a file deleted by rev has no events.
+func revisionEvents(ctx context.Context, parser *parsers.Parser, repo string, rev GitRevision) ([]models.TimelineEvent, error) {
+	content, err := runGit(ctx, repo, "cat-file", "blob", rev.Commit+":"+rev.Path)
+	if err != nil {
+		if _, existsErr := runGit(ctx, repo, "cat-file", "-e", rev.Commit+":"+rev.Path); existsErr != nil {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	doc, err := parser.ParseDocument(string(content))
+	if err != nil {
+		return nil, err
+	}
+
+	return doc.Events, nil
+}
+
+// historyTracker diffs consecutive revisions into per-event histories.
+type historyTracker struct {
+	current map[string]models.TimelineEvent
+	byID    map[string]*EventHistory
+	order   []string
+}
+
+func newHistoryTracker() *historyTracker {
+	return &historyTracker{
+		current: make(map[string]models.TimelineEvent),
+		byID:    make(map[string]*EventHistory),
+	}
+}
+
+// apply records the differences between the previous revision and events.
+func (t *historyTracker) apply(rev GitRevision, events []models.TimelineEvent) {
+	next := keyEvents(events)
+
+	for _, key := range orderedKeys(events) {
+		event := next[key]
+		change := EventChange{Time: rev.Time, Commit: rev.Commit}
+
+		h, seen := t.byID[key]
+		if !seen {
+			h = &EventHistory{ID: key, IntroducedIn: rev.Commit}
+			t.byID[key] = h
+			t.order = append(t.order, key)
+		}
+
+		previous, present := t.current[key]
+
+		switch {
+		case !seen:
+			change.Kind = ChangeAdded
+			change.After = event.Description
+			h.Changes = append(h.Changes, change)
+		case !present:
+			change.Kind = ChangeRestored
+			change.After = event.Description
+			h.RemovedIn = ""
+			h.Changes = append(h.Changes, change)
+		default:
+			if previous.Description != event.Description {
+				change.Kind = ChangeDescription
+				change.Before, change.After = previous.Description, event.Description
+				h.Changes = append(h.Changes, change)
+			}
+
+			if before, after := casualtySummary(previous.Casualties), casualtySummary(event.Casualties); before != after {
+				change.Kind = ChangeCasualties
+				change.Before, change.After = before, after
+				h.Changes = append(h.Changes, change)
+			}
+		}
+
+		h.Date, h.Time, h.Category, h.Description = event.Date, event.Time, event.Category, event.Description
+	}
+
+	for key, previous := range t.current {
+		if _, ok := next[key]; ok {
+			continue
+		}
+
+		h := t.byID[key]
+		h.RemovedIn = rev.Commit
+		h.Changes = append(h.Changes, EventChange{
+			Time:   rev.Time,
+			Kind:   ChangeRemoved,
+			Commit: rev.Commit,
+			Before: previous.Description,
+		})
+	}
+
+	t.current = next
+}
+
+// histories returns the event histories in order of first appearance.
+func (t *historyTracker) histories() []EventHistory {
+	out := make([]EventHistory, 0, len(t.order))
+	for _, key := range t.order {
+		out = append(out, *t.byID[key])
+	}
+
+	return out
+}
+
+// keyEvents indexes events by ID. Events sharing an ID (same date, time and category)
+// are told apart by their position among those duplicates: "id", "id#2", ...
+func keyEvents(events []models.TimelineEvent) map[string]models.TimelineEvent {
+	keys := orderedKeys(events)
+
+	indexed := make(map[string]models.TimelineEvent, len(events))
+	for i, key := range keys {
+		indexed[key] = events[i]
+	}
+
+	return indexed
+}
+
+// orderedKeys returns the history key of each event, in document order.
+func orderedKeys(events []models.TimelineEvent) []string {
+	seen := make(map[string]int, len(events))
+	keys := make([]string, len(events))
+
+	for i, event := range events {
+		seen[event.ID]++
+
+		keys[i] = event.ID
+		if n := seen[event.ID]; n > 1 {
+			keys[i] = event.ID + "#" + strconv.Itoa(n)
+		}
+	}
+
+	return keys
+}
+
+// casualtySummary renders casualties for comparison and display.
+func casualtySummary(c models.CasualtyData) string {
+	if c.Raw != "" {
+		return c.Raw
+	}
+
+	parts := make([]string, 0, len(c.Items))
+	for _, item := range c.Items {
+		parts = append(parts, item.Type+" "+strconv.Itoa(item.Count))
+	}
+
+	return strings.Join(parts, ", ")
+}
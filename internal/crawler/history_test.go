@@ -0,0 +1,87 @@
+package crawler
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"tpwfc/internal/crawler/parsers"
+)
+
+// historyDoc builds a timeline document with the given table rows.
+func historyDoc(rows ...string) string {
+	return "<!-- TIMELINE_TABLE_START -->\n\n" +
+		"| DATE | TIME | DESCRIPTION | CATEGORY | CASUALTIES | SOURCES |\n" +
+		"| ---- | ---- | ----------- | -------- | ---------- | ------- |\n" +
+		strings.Join(rows, "\n") + "\n\n<!-- TIMELINE_TABLE_END -->\n"
+}
+
+func TestBuildTimelineHistory(t *testing.T) {
+	repo := gitRepo(t,
+		historyDoc(
+			"| 2025-11-26 | 14:51 | Fire reported | Alarm | DEAD:0 | S1 |",
+			"| 2025-11-26 | 15:30 | Upgraded to No. 3 | Alarm | DEAD:0 | S1 |",
+		),
+		historyDoc(
+			"| 2025-11-26 | 14:51 | Fire reported at Wang Fuk Court | Alarm | DEAD:0 | S1 |",
+			"| 2025-11-26 | 18:22 | Upgraded to No. 5 | Alarm | DEAD:4 | S1 |",
+		),
+		historyDoc(
+			"| 2025-11-26 | 14:51 | Fire reported at Wang Fuk Court | Alarm | DEAD:1 | S1 |",
+			"| 2025-11-26 | 15:30 | Upgraded to No. 3 | Alarm | DEAD:0 | S1 |",
+			"| 2025-11-26 | 18:22 | Upgraded to No. 5 | Alarm | DEAD:4 | S1 |",
+		),
+	)
+
+	history, err := BuildTimelineHistory(context.Background(), parsers.NewParser(), repo, "main", "fire/timeline.md")
+	if err != nil {
+		t.Fatalf("BuildTimelineHistory() error = %v", err)
+	}
+
+	if len(history.Revisions) != 3 {
+		t.Fatalf("got %d revisions, want 3", len(history.Revisions))
+	}
+
+	first, second, third := history.Revisions[0].Commit, history.Revisions[1].Commit, history.Revisions[2].Commit
+
+	if len(history.Events) != 3 {
+		t.Fatalf("got %d events, want 3", len(history.Events))
+	}
+
+	kinds := func(h EventHistory) string {
+		var out []string
+		for _, c := range h.Changes {
+			out = append(out, c.Kind+"@"+c.Commit[:7])
+		}
+
+		return strings.Join(out, " ")
+	}
+
+	tests := []struct {
+		time       string
+		introduced string
+		removed    string
+		changes    string
+	}{
+		{"14:51", first, "", "added@" + first[:7] + " description@" + second[:7] + " casualties@" + third[:7]},
+		{"15:30", first, "", "added@" + first[:7] + " removed@" + second[:7] + " restored@" + third[:7]},
+		{"18:22", second, "", "added@" + second[:7]},
+	}
+
+	for i, tt := range tests {
+		got := history.Events[i]
+		if got.Time != tt.time || got.IntroducedIn != tt.introduced || got.RemovedIn != tt.removed {
+			t.Errorf("Events[%d] = %s introduced %s removed %q, want %s introduced %s removed %q",
+				i, got.Time, got.IntroducedIn, got.RemovedIn, tt.time, tt.introduced, tt.removed)
+		}
+
+		if kinds(got) != tt.changes {
+			t.Errorf("Events[%d] changes = %s, want %s", i, kinds(got), tt.changes)
+		}
+	}
+
+	description := history.Events[0].Changes[1]
+	if description.Before != "Fire reported" || description.After != "Fire reported at Wang Fuk Court" {
+		t.Errorf("description change = %+v", description)
+	}
+}
@@ -0,0 +1,104 @@
+package crawler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"tpwfc/internal/models"
+)
+
+// jsonlWriter encodes JSONL records into a buffer and keeps the first error.
+type jsonlWriter struct {
+	err error
+	buf bytes.Buffer
+}
+
+func (w *jsonlWriter) write(recordType, phaseID string, v any) {
+	if w.err != nil {
+		return
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		w.err = fmt.Errorf("failed to marshal %s record: %w", recordType, err)
+
+		return
+	}
+
+	line, err := json.Marshal(models.JSONLRecord{Type: recordType, PhaseID: phaseID, Data: data})
+	if err != nil {
+		w.err = fmt.Errorf("failed to marshal %s record: %w", recordType, err)
+
+		return
+	}
+
+	w.buf.Write(line)
+	w.buf.WriteByte('\n')
+}
+
+func (w *jsonlWriter) save(c *Client, outputPath string) error {
+	if w.err != nil {
+		return w.err
+	}
+
+	return c.writeOutput(outputPath, w.buf.Bytes())
+}
+
+// SaveTimelineJSONL saves the incident header and then one event per line; doc may be nil.
+func (c *Client) SaveTimelineJSONL(events []models.TimelineEvent, doc *models.TimelineDocument, outputPath string) error {
+	header := map[string]interface{}{
+		"summary": calculateSummary(events),
+	}
+
+	if doc != nil {
+		header["metadata"] = doc.Metadata
+		header["basicInfo"] = doc.BasicInfo
+		header["fireCause"] = doc.FireCause
+		header["severity"] = doc.Severity
+		header["keyStatistics"] = doc.KeyStatistics
+		header["sources"] = doc.Sources
+		header["notes"] = doc.Notes
+	}
+
+	var w jsonlWriter
+
+	w.write(models.RecordIncident, "", header)
+
+	for _, event := range events {
+		w.write(models.RecordEvent, "", event)
+	}
+
+	return w.save(c, outputPath)
+}
+
+// jsonlPhase is a phase record; its events follow as separate phaseEvent records.
+type jsonlPhase struct {
+	models.DetailedTimelinePhase
+	Events []models.DetailedTimelineEvent `json:"events,omitempty"`
+}
+
+// SaveDetailedTimelineJSONL saves the header, each phase followed by its events, and the tracking entries one per line.
+func (c *Client) SaveDetailedTimelineJSONL(doc *models.DetailedTimelineDocument, outputPath string) error {
+	var w jsonlWriter
+
+	w.write(models.RecordIncident, "", map[string]interface{}{
+		"metadata":        doc.Metadata,
+		"categoryMetrics": doc.CategoryMetrics,
+		"notes":           doc.Notes,
+	})
+
+	for _, phase := range doc.Phases {
+		w.write(models.RecordPhase, "", jsonlPhase{DetailedTimelinePhase: phase})
+
+		for _, event := range phase.Events {
+			w.write(models.RecordPhaseEvent, phase.ID, event)
+		}
+	}
+
+	for _, tracking := range doc.LongTermTracking {
+		w.write(models.RecordTracking, "", tracking)
+	}
+
+	return w.save(c, outputPath)
+}
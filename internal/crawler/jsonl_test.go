@@ -0,0 +1,131 @@
+package crawler
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"tpwfc/internal/models"
+	"tpwfc/internal/payload"
+)
+
+func TestClient_SaveTimelineJSONL(t *testing.T) {
+	events := []models.TimelineEvent{
+		{ID: "ev1", Date: "2025-11-26", Time: "14:51", Description: "Fire reported"},
+		{ID: "ev2", Date: "2025-11-27", Time: "09:00", Description: "Fire under control"},
+	}
+	doc := &models.TimelineDocument{BasicInfo: models.BasicInfo{IncidentID: "FIRE001", IncidentName: "Test Fire"}}
+
+	path := filepath.Join(t.TempDir(), "timeline.jsonl")
+	if err := NewClient().SaveTimeline(events, doc, path, "jsonl"); err != nil {
+		t.Fatalf("SaveTimeline failed: %v", err)
+	}
+
+	types := recordTypes(t, path)
+	want := []string{models.RecordIncident, models.RecordEvent, models.RecordEvent}
+
+	if len(types) != len(want) {
+		t.Fatalf("Record types = %v, want %v", types, want)
+	}
+
+	for i := range want {
+		if types[i] != want[i] {
+			t.Errorf("Record %d type = %q, want %q", i, types[i], want[i])
+		}
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer f.Close()
+
+	header, seq, err := payload.ReadTimelineJSONL(f)
+	if err != nil {
+		t.Fatalf("ReadTimelineJSONL failed: %v", err)
+	}
+
+	if header.BasicInfo.IncidentID != "FIRE001" || header.Summary.TotalEvents != 2 || header.Summary.EndDate != "2025-11-27" {
+		t.Errorf("Unexpected header: %+v", header)
+	}
+
+	var got []models.TimelineEvent
+
+	for event, readErr := range seq {
+		if readErr != nil {
+			t.Fatalf("Reading events failed: %v", readErr)
+		}
+
+		got = append(got, event)
+	}
+
+	if len(got) != 2 || got[1].ID != "ev2" {
+		t.Errorf("Events = %+v", got)
+	}
+}
+
+func TestClient_SaveDetailedTimelineJSONL(t *testing.T) {
+	doc := &models.DetailedTimelineDocument{
+		Phases: []models.DetailedTimelinePhase{
+			{ID: "p1", PhaseName: "Outbreak", Events: []models.DetailedTimelineEvent{{ID: "e1"}, {ID: "e2"}}},
+			{ID: "p2", PhaseName: "Relief", Events: []models.DetailedTimelineEvent{{ID: "e3"}}},
+		},
+		LongTermTracking: []models.LongTermTrackingEvent{{ID: "t1", Event: "Inquiry"}},
+		CategoryMetrics:  []models.CategoryMetric{{Category: "RELIEF", MetricKey: "AID_FUND", MetricValue: 300}},
+	}
+
+	path := filepath.Join(t.TempDir(), "timeline.jsonl")
+	if err := NewClient().SaveDetailedTimeline(doc, path, "jsonl"); err != nil {
+		t.Fatalf("SaveDetailedTimeline failed: %v", err)
+	}
+
+	if n := len(recordTypes(t, path)); n != 7 {
+		t.Errorf("Expected 7 records, got %d", n)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer f.Close()
+
+	data, err := payload.ReadDetailedTimelineJSONL(f)
+	if err != nil {
+		t.Fatalf("ReadDetailedTimelineJSONL failed: %v", err)
+	}
+
+	if len(data.Phases) != 2 || len(data.Phases[0].Events) != 2 || len(data.Phases[1].Events) != 1 {
+		t.Errorf("Unexpected phases: %+v", data.Phases)
+	}
+
+	if len(data.LongTermTracking) != 1 || len(data.CategoryMetrics) != 1 {
+		t.Errorf("Unexpected tracking/metrics: %+v %+v", data.LongTermTracking, data.CategoryMetrics)
+	}
+}
+
+// recordTypes returns the type of every line in a JSONL file.
+func recordTypes(t *testing.T, path string) []string {
+	t.Helper()
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer f.Close()
+
+	var types []string
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var rec models.JSONLRecord
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			t.Fatalf("Line is not a JSON record: %v", err)
+		}
+
+		types = append(types, rec.Type)
+	}
+
+	return types
+}
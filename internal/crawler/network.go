@@ -0,0 +1,181 @@
+package crawler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+
+	"tpwfc/internal/config"
+	"tpwfc/pkg/utils"
+)
+
+// Network policy errors.
+var (
+	// ErrHostNotAllowed indicates a request to a host outside network.allowed_hosts.
+	ErrHostNotAllowed = errors.New("host is not in the allowlist")
+	// ErrBlockedAddress indicates a connection to a private, loopback or link-local address.
+	ErrBlockedAddress = errors.New("address is in a blocked network")
+	// ErrTooManyRedirects indicates a request that exceeded network.max_redirects.
+	ErrTooManyRedirects = errors.New("too many redirects")
+)
+
+// blockedPrefixes are non-public ranges not covered by the netip.Addr predicates.
+var blockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),     // "this network"
+	netip.MustParsePrefix("100.64.0.0/10"), // carrier-grade NAT
+	netip.MustParsePrefix("192.0.0.0/24"),  // IETF protocol assignments
+	netip.MustParsePrefix("198.18.0.0/15"), // benchmarking
+}
+
+// NetworkPolicy restricts the hosts and addresses a Scraper may connect to.
+// It is enforced by the transport, so redirects and DNS answers are checked too.
+type NetworkPolicy struct {
+	// AllowedHosts lists permitted host names; "*.example.com" matches any subdomain.
+	// An empty list permits any host.
+	AllowedHosts []string
+	// MaxRedirects caps the redirects followed by one request.
+	MaxRedirects int
+	// AllowPrivateNetworks permits private, loopback and link-local addresses.
+	AllowPrivateNetworks bool
+}
+
+// NewNetworkPolicy builds the policy described by the crawler.network section.
+func NewNetworkPolicy(cfg *config.Config) *NetworkPolicy {
+	return &NetworkPolicy{
+		AllowedHosts:         cfg.Crawler.Network.AllowedHosts,
+		MaxRedirects:         cfg.GetMaxRedirects(),
+		AllowPrivateNetworks: cfg.Crawler.Network.AllowPrivateNetworks,
+	}
+}
+
+// CheckHost rejects a host outside the allowlist, and a literal IP in a blocked network.
+func (p *NetworkPolicy) CheckHost(host string) error {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+
+	if !p.hostAllowed(host) {
+		return fmt.Errorf("%w: %s", ErrHostNotAllowed, host)
+	}
+
+	if addr, err := netip.ParseAddr(strings.Trim(host, "[]")); err == nil {
+		return p.CheckAddr(addr)
+	}
+
+	return nil
+}
+
+// hostAllowed matches host against the allowlist.
+func (p *NetworkPolicy) hostAllowed(host string) bool {
+	if len(p.AllowedHosts) == 0 {
+		return true
+	}
+
+	for _, pattern := range p.AllowedHosts {
+		pattern = strings.ToLower(pattern)
+
+		if suffix, ok := strings.CutPrefix(pattern, "*"); ok {
+			if strings.HasSuffix(host, suffix) && len(host) > len(suffix) {
+				return true
+			}
+
+			continue
+		}
+
+		if host == pattern {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CheckAddr rejects private, loopback, link-local and other non-public addresses.
+func (p *NetworkPolicy) CheckAddr(addr netip.Addr) error {
+	if p.AllowPrivateNetworks {
+		return nil
+	}
+
+	addr = addr.Unmap()
+
+	blocked := addr.IsPrivate() || addr.IsLoopback() || addr.IsLinkLocalUnicast() ||
+		addr.IsLinkLocalMulticast() || addr.IsInterfaceLocalMulticast() ||
+		addr.IsMulticast() || addr.IsUnspecified()
+
+	for _, prefix := range blockedPrefixes {
+		blocked = blocked || prefix.Contains(addr)
+	}
+
+	if blocked {
+		return fmt.Errorf("%w: %s", ErrBlockedAddress, addr)
+	}
+
+	return nil
+}
+
+// control runs after DNS resolution, just before connecting, so a host name that
+// resolves (or rebinds) to an internal address is refused.
+func (p *NetworkPolicy) control(_, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrBlockedAddress, address)
+	}
+
+	return p.CheckAddr(addrPort.Addr())
+}
+
+// checkRedirect enforces the redirect limit and the allowlist on every hop.
+func (p *NetworkPolicy) checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) > p.MaxRedirects {
+		return fmt.Errorf("%w: stopped after %d", ErrTooManyRedirects, p.MaxRedirects)
+	}
+
+	return p.CheckHost(req.URL.Hostname())
+}
+
+// transport returns a tuned transport enforcing the policy. Without a proxy the dialer
+// checks both the host name and the resolved address. Through a proxy, which resolves
+// names itself, the target host is checked before the request is handed to the proxy.
+// Environment proxy settings are ignored; use the per-source proxy instead.
+func (p *NetworkPolicy) transport(proxy *url.URL) *http.Transport {
+	opts := utils.DefaultTransportOptions()
+	opts.Proxy = nil
+
+	if proxy != nil {
+		opts.Proxy = func(req *http.Request) (*url.URL, error) {
+			if err := p.CheckHost(req.URL.Hostname()); err != nil {
+				return nil, err
+			}
+
+			return proxy, nil
+		}
+
+		return utils.NewTransport(opts)
+	}
+
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second, Control: p.control}
+	opts.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		host, _, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid dial address %s: %w", addr, err)
+		}
+
+		if err := p.CheckHost(host); err != nil {
+			return nil, err
+		}
+
+		return dialer.DialContext(ctx, network, addr)
+	}
+
+	return utils.NewTransport(opts)
+}
+
+// isPolicyError reports whether err is a network policy refusal, which retrying cannot fix.
+func isPolicyError(err error) bool {
+	return errors.Is(err, ErrHostNotAllowed) || errors.Is(err, ErrBlockedAddress) || errors.Is(err, ErrTooManyRedirects)
+}
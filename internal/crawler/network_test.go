@@ -0,0 +1,116 @@
+package crawler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"sync/atomic"
+	"testing"
+
+	"tpwfc/internal/config"
+)
+
+func TestNetworkPolicy_CheckHost(t *testing.T) {
+	policy := &NetworkPolicy{AllowedHosts: []string{"raw.githubusercontent.com", "*.example.org"}}
+
+	tests := []struct {
+		host string
+		want error
+	}{
+		{"raw.githubusercontent.com", nil},
+		{"RAW.githubusercontent.com.", nil},
+		{"data.example.org", nil},
+		{"example.org", ErrHostNotAllowed},
+		{"evil-example.org", ErrHostNotAllowed},
+		{"payload.default.svc", ErrHostNotAllowed},
+	}
+
+	for _, tt := range tests {
+		if err := policy.CheckHost(tt.host); !errors.Is(err, tt.want) {
+			t.Errorf("CheckHost(%q) = %v, want %v", tt.host, err, tt.want)
+		}
+	}
+}
+
+func TestNetworkPolicy_CheckAddr(t *testing.T) {
+	policy := &NetworkPolicy{}
+
+	blocked := []string{"127.0.0.1", "10.1.2.3", "172.16.0.1", "192.168.1.1", "169.254.169.254",
+		"100.64.0.1", "0.0.0.0", "::1", "fe80::1", "fd00::1", "::ffff:127.0.0.1"}
+	for _, addr := range blocked {
+		if err := policy.CheckAddr(netip.MustParseAddr(addr)); !errors.Is(err, ErrBlockedAddress) {
+			t.Errorf("CheckAddr(%s) = %v, want %v", addr, err, ErrBlockedAddress)
+		}
+	}
+
+	for _, addr := range []string{"185.199.108.133", "2606:50c0:8000::154"} {
+		if err := policy.CheckAddr(netip.MustParseAddr(addr)); err != nil {
+			t.Errorf("CheckAddr(%s) = %v, want nil", addr, err)
+		}
+	}
+
+	policy.AllowPrivateNetworks = true
+	if err := policy.CheckAddr(netip.MustParseAddr("127.0.0.1")); err != nil {
+		t.Errorf("CheckAddr(127.0.0.1) with private networks allowed = %v", err)
+	}
+}
+
+func TestScraper_SetNetworkPolicy_BlocksLoopback(t *testing.T) {
+	var hits atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		hits.Add(1)
+		_, _ = w.Write([]byte("internal"))
+	}))
+	defer server.Close()
+
+	scraper := NewScraperWithConfig(&config.RetryPolicy{MaxAttempts: 3, BackoffMultiplier: 1, TimeoutSec: 5}, 64)
+	scraper.SetNetworkPolicy(&NetworkPolicy{MaxRedirects: config.DefaultMaxRedirects})
+
+	_, _, _, err := scraper.ScrapeWithMetricsContext(context.Background(), server.URL)
+	if !errors.Is(err, ErrBlockedAddress) {
+		t.Fatalf("expected %v, got %v", ErrBlockedAddress, err)
+	}
+
+	if hits.Load() != 0 {
+		t.Errorf("blocked server received %d requests", hits.Load())
+	}
+
+	// A host name that resolves to loopback is refused after DNS resolution
+	_, _, _, err = scraper.ScrapeWithMetricsContext(context.Background(), "http://localhost:1/timeline.md")
+	if !errors.Is(err, ErrBlockedAddress) {
+		t.Errorf("expected %v for localhost, got %v", ErrBlockedAddress, err)
+	}
+}
+
+func TestScraper_SetNetworkPolicy_Redirects(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/loop", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/loop", http.StatusFound)
+	})
+	mux.HandleFunc("/away", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "http://metadata.internal/latest", http.StatusFound)
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	scraper := NewScraperWithConfig(&config.RetryPolicy{MaxAttempts: 1, BackoffMultiplier: 1, TimeoutSec: 5}, 64)
+	scraper.SetNetworkPolicy(&NetworkPolicy{
+		AllowedHosts:         []string{"127.0.0.1"},
+		MaxRedirects:         2,
+		AllowPrivateNetworks: true,
+	})
+
+	_, _, _, err := scraper.ScrapeWithMetricsContext(context.Background(), server.URL+"/loop")
+	if !errors.Is(err, ErrTooManyRedirects) {
+		t.Errorf("expected %v, got %v", ErrTooManyRedirects, err)
+	}
+
+	_, _, _, err = scraper.ScrapeWithMetricsContext(context.Background(), server.URL+"/away")
+	if !errors.Is(err, ErrHostNotAllowed) {
+		t.Errorf("expected %v, got %v", ErrHostNotAllowed, err)
+	}
+}
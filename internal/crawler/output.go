@@ -0,0 +1,207 @@
+package crawler
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Backup errors.
+var (
+	ErrNoBackups       = errors.New("no backups found")
+	ErrBackupAmbiguous = errors.New("backup choice is ambiguous")
+)
+
+// backupTimeFormat names backups so that lexical order is chronological.
+const backupTimeFormat = "20060102T150405.000000000Z"
+
+// Backup is a timestamped copy of an output file.
+type Backup struct {
+	CreatedAt time.Time
+	Path      string
+	Size      int64
+}
+
+// WriteFileAtomic writes data to a temp file in the same directory, syncs it and renames it
+// over path, so readers see either the old or the new content and never a partial file.
+func WriteFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+
+	tmpPath := tmp.Name()
+
+	if _, err := tmp.Write(data); err != nil {
+		return errors.Join(fmt.Errorf("failed to write temp file: %w", err), tmp.Close(), os.Remove(tmpPath))
+	}
+
+	if err := tmp.Sync(); err != nil {
+		return errors.Join(fmt.Errorf("failed to sync temp file: %w", err), tmp.Close(), os.Remove(tmpPath))
+	}
+
+	if err := tmp.Close(); err != nil {
+		return errors.Join(fmt.Errorf("failed to close temp file: %w", err), os.Remove(tmpPath))
+	}
+
+	if err := os.Chmod(tmpPath, 0644); err != nil {
+		return errors.Join(fmt.Errorf("failed to set file mode: %w", err), os.Remove(tmpPath))
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		return errors.Join(fmt.Errorf("failed to replace %s: %w", path, err), os.Remove(tmpPath))
+	}
+
+	syncDir(filepath.Dir(path))
+
+	return nil
+}
+
+// syncDir flushes a directory entry after a rename; failures are ignored since not every
+// platform supports syncing directories.
+func syncDir(dir string) {
+	d, err := os.Open(dir)
+	if err != nil {
+		return
+	}
+
+	_ = d.Sync()
+	_ = d.Close()
+}
+
+// BackupOutput copies an existing output file to {path}.{timestamp}.bak and removes all but the
+// newest keep backups. It returns the new backup path, or "" if path does not exist yet.
+func BackupOutput(path string, keep int) (string, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("failed to read output for backup: %w", err)
+	}
+
+	backupPath := path + "." + time.Now().UTC().Format(backupTimeFormat) + ".bak"
+	if err := WriteFileAtomic(backupPath, data); err != nil {
+		return "", fmt.Errorf("failed to write backup: %w", err)
+	}
+
+	return backupPath, pruneBackups(path, keep)
+}
+
+// pruneBackups deletes all but the newest keep backups of path.
+func pruneBackups(path string, keep int) error {
+	backups, err := ListBackups(path)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+
+	for i := keep; i < len(backups); i++ {
+		if err := os.Remove(backups[i].Path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			errs = append(errs, fmt.Errorf("failed to remove old backup: %w", err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// ListBackups returns the timestamped backups of an output file, newest first.
+func ListBackups(path string) ([]Backup, error) {
+	matches, err := filepath.Glob(globEscape(path) + ".*.bak")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list backups: %w", err)
+	}
+
+	backups := make([]Backup, 0, len(matches))
+
+	for _, match := range matches {
+		stamp := strings.TrimSuffix(strings.TrimPrefix(match, path+"."), ".bak")
+
+		createdAt, parseErr := time.Parse(backupTimeFormat, stamp)
+		if parseErr != nil {
+			continue
+		}
+
+		info, statErr := os.Stat(match)
+		if statErr != nil {
+			continue
+		}
+
+		backups = append(backups, Backup{CreatedAt: createdAt, Path: match, Size: info.Size()})
+	}
+
+	sort.Slice(backups, func(i, j int) bool { return backups[i].CreatedAt.After(backups[j].CreatedAt) })
+
+	return backups, nil
+}
+
+// RestoreBackup atomically puts a backup back in place of its output file. An empty choice
+// restores the newest backup; otherwise choice is a backup path or a unique prefix of its timestamp.
+// The current output, if any, is backed up first so the restore can be undone.
+func RestoreBackup(path, choice string, keep int) (Backup, error) {
+	backups, err := ListBackups(path)
+	if err != nil {
+		return Backup{}, err
+	}
+
+	if len(backups) == 0 {
+		return Backup{}, fmt.Errorf("%w for %s", ErrNoBackups, path)
+	}
+
+	chosen, err := chooseBackup(backups, path, choice)
+	if err != nil {
+		return Backup{}, err
+	}
+
+	data, err := os.ReadFile(chosen.Path)
+	if err != nil {
+		return Backup{}, fmt.Errorf("failed to read backup: %w", err)
+	}
+
+	// Keep the chosen backup even if rotation would otherwise drop it
+	if _, err := BackupOutput(path, max(keep, len(backups)+1)); err != nil {
+		return Backup{}, err
+	}
+
+	if err := WriteFileAtomic(path, data); err != nil {
+		return Backup{}, err
+	}
+
+	return chosen, pruneBackups(path, keep)
+}
+
+// chooseBackup picks the newest backup, or the one matching a path or timestamp prefix.
+func chooseBackup(backups []Backup, path, choice string) (Backup, error) {
+	if choice == "" {
+		return backups[0], nil
+	}
+
+	var found []Backup
+
+	for _, b := range backups {
+		stamp := strings.TrimSuffix(strings.TrimPrefix(b.Path, path+"."), ".bak")
+		if b.Path == choice || filepath.Base(b.Path) == choice || strings.HasPrefix(stamp, choice) {
+			found = append(found, b)
+		}
+	}
+
+	switch len(found) {
+	case 0:
+		return Backup{}, fmt.Errorf("%w matching %q for %s", ErrNoBackups, choice, path)
+	case 1:
+		return found[0], nil
+	default:
+		return Backup{}, fmt.Errorf("%w: %q matches %d backups", ErrBackupAmbiguous, choice, len(found))
+	}
+}
+
+// globEscape escapes glob metacharacters in a literal path.
+func globEscape(path string) string {
+	return strings.NewReplacer(`\`, `\\`, "*", `\*`, "?", `\?`, "[", `\[`).Replace(path)
+}
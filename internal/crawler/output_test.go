@@ -0,0 +1,160 @@
+package crawler
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"tpwfc/internal/crawler/parsers"
+	"tpwfc/internal/models"
+)
+
+func TestWriteFileAtomic(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "timeline.json")
+
+	for _, content := range []string{"first", "second"} {
+		if err := WriteFileAtomic(path, []byte(content)); err != nil {
+			t.Fatalf("WriteFileAtomic failed: %v", err)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != "second" {
+		t.Fatalf("ReadFile = %q, %v; want second", data, err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir failed: %v", err)
+	}
+
+	if len(entries) != 1 {
+		t.Errorf("Expected only the output file, found %d entries", len(entries))
+	}
+}
+
+func TestClient_BackupRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fire_timeline.json")
+	client := NewClient()
+	client.SetBackupRetention(2)
+
+	for i := 0; i < 4; i++ {
+		events := make([]models.TimelineEvent, i)
+		if err := client.SaveTimelineJSON(events, path); err != nil {
+			t.Fatalf("SaveTimelineJSON failed: %v", err)
+		}
+	}
+
+	backups, err := ListBackups(path)
+	if err != nil {
+		t.Fatalf("ListBackups failed: %v", err)
+	}
+
+	if len(backups) != 2 {
+		t.Fatalf("Expected 2 backups, got %d", len(backups))
+	}
+
+	if !backups[0].CreatedAt.After(backups[1].CreatedAt) {
+		t.Errorf("Backups not sorted newest first: %v, %v", backups[0].CreatedAt, backups[1].CreatedAt)
+	}
+}
+
+func TestRestoreBackup(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fire_timeline.json")
+
+	if _, err := RestoreBackup(path, "", 3); !errors.Is(err, ErrNoBackups) {
+		t.Fatalf("Expected ErrNoBackups, got %v", err)
+	}
+
+	client := NewClient()
+	client.SetBackupRetention(3)
+
+	for _, content := range []string{"v1", "v2", "v3"} {
+		if err := client.writeOutput(path, []byte(content)); err != nil {
+			t.Fatalf("writeOutput failed: %v", err)
+		}
+	}
+
+	// Backups hold v2 (newest) and v1
+	backups, err := ListBackups(path)
+	if err != nil || len(backups) != 2 {
+		t.Fatalf("ListBackups = %d, %v; want 2 backups", len(backups), err)
+	}
+
+	restored, err := RestoreBackup(path, filepath.Base(backups[1].Path), 3)
+	if err != nil {
+		t.Fatalf("RestoreBackup failed: %v", err)
+	}
+
+	if restored.Path != backups[1].Path {
+		t.Errorf("Restored %s, want %s", restored.Path, backups[1].Path)
+	}
+
+	if data, _ := os.ReadFile(path); string(data) != "v1" {
+		t.Errorf("Output = %q after restore, want v1", data)
+	}
+
+	// The replaced v3 is kept as the newest backup
+	backups, err = ListBackups(path)
+	if err != nil || len(backups) != 3 {
+		t.Fatalf("ListBackups = %d, %v; want 3 backups", len(backups), err)
+	}
+
+	if data, _ := os.ReadFile(backups[0].Path); string(data) != "v3" {
+		t.Errorf("Newest backup = %q, want v3", data)
+	}
+
+	if _, err := RestoreBackup(path, "19990101", 3); !errors.Is(err, ErrNoBackups) {
+		t.Errorf("Expected ErrNoBackups for unknown choice, got %v", err)
+	}
+}
+
+func TestClient_SaveParsed(t *testing.T) {
+	parser := parsers.NewParser()
+	client := NewClientWithDeps(nil, parser, nil)
+	dir := t.TempDir()
+
+	for _, fixture := range []string{"investigation.md", "responses.md", "detailed_timeline.md"} {
+		content, err := os.ReadFile(filepath.Join("..", "..", "test", "fixtures", fixture))
+		if err != nil {
+			t.Fatalf("failed to read fixture: %v", err)
+		}
+
+		handler, err := parser.FileTypeHandler(string(content))
+		if err != nil {
+			t.Fatalf("FileTypeHandler(%s) error = %v", fixture, err)
+		}
+
+		doc, _, err := handler.Parse(parser, string(content))
+		if err != nil {
+			t.Fatalf("Parse(%s) error = %v", fixture, err)
+		}
+
+		path := filepath.Join(dir, fixture+".json")
+		if err = client.SaveParsed(doc, path, "json"); err != nil {
+			t.Fatalf("SaveParsed(%s) error = %v", fixture, err)
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("failed to read output: %v", err)
+		}
+
+		var saved map[string]json.RawMessage
+		if err = json.Unmarshal(data, &saved); err != nil || len(saved) == 0 {
+			t.Errorf("%s saved as %s (%v)", fixture, data, err)
+		}
+
+		key := map[string]string{
+			parsers.FileTypeInvestigation:    "findings",
+			parsers.FileTypeResponses:        "responses",
+			parsers.FileTypeDetailedTimeline: "phases",
+		}[handler.FileType]
+		if _, ok := saved[key]; !ok {
+			t.Errorf("%s (%s) output has no %q", fixture, handler.FileType, key)
+		}
+	}
+}
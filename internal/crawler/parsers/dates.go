@@ -0,0 +1,84 @@
+package parsers
+
+import (
+	"fmt"
+	"strconv"
+
+	"tpwfc/internal/models"
+	"tpwfc/pkg/metadata"
+)
+
+// fallbackYear is assumed for month-day headings in a document that gives no year anywhere.
+// It is the year of the Wang Fuk Court fire, the first document the parser was written for.
+const fallbackYear = 2025
+
+// documentYear returns the year a document's month-day headings start in: the year of the
+// incident's start date from BASIC_INFO DATE_RANGE, else the year of the metadata LAST_MODIFY.
+// It returns 0 when the document gives neither.
+func documentYear(info models.BasicInfo, meta *metadata.Metadata) int {
+	for _, date := range []string{info.StartDate, info.DateRange} {
+		if len(date) >= 4 {
+			if year, err := strconv.Atoi(date[:4]); err == nil && year > 0 {
+				return year
+			}
+		}
+	}
+
+	if meta != nil && !meta.LastModify.IsZero() {
+		return meta.LastModify.Year()
+	}
+
+	return 0
+}
+
+// dateResolver turns month-day headings into full dates. The year carries forward from
+// heading to heading, and moves to the next year when the month goes backwards, so the
+// January headings after December in a long recovery timeline land in the following year.
+type dateResolver struct {
+	year  int
+	month int
+	// assumed is set while the year is fallbackYear rather than read from the document.
+	assumed bool
+}
+
+// newDateResolver starts at year, or at fallbackYear when year is 0.
+func newDateResolver(year int) *dateResolver {
+	if year == 0 {
+		return &dateResolver{year: fallbackYear, assumed: true}
+	}
+
+	return &dateResolver{year: year}
+}
+
+// resolve returns the YYYY-MM-DD date of a heading without a year.
+func (r *dateResolver) resolve(month, day int) string {
+	if r.month > 0 && month < r.month {
+		r.year++
+	}
+
+	r.month = month
+
+	return r.format(day)
+}
+
+// resolveYear returns the date of a heading with its own year, which also fixes the year
+// of the headings that follow it.
+func (r *dateResolver) resolveYear(year, month, day int) string {
+	r.year, r.month, r.assumed = year, month, false
+
+	return r.format(day)
+}
+
+// observe records the YYYY-MM-DD date of a dated table row, so later headings continue from it.
+func (r *dateResolver) observe(date string) {
+	var year, month, day int
+	if _, err := fmt.Sscanf(date, "%4d-%2d-%2d", &year, &month, &day); err != nil {
+		return
+	}
+
+	r.year, r.month, r.assumed = year, month, false
+}
+
+func (r *dateResolver) format(day int) string {
+	return fmt.Sprintf("%04d-%02d-%02d", r.year, r.month, day)
+}
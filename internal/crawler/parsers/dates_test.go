@@ -0,0 +1,102 @@
+package parsers
+
+import (
+	"strings"
+	"testing"
+)
+
+// legacyTimeline builds a document with one single-row table under each date heading.
+func legacyTimeline(basicInfo, metadataBlock string, headings ...string) string {
+	var b strings.Builder
+
+	if basicInfo != "" {
+		b.WriteString("<!-- BASIC_INFO_START -->\n" + basicInfo + "\n<!-- BASIC_INFO_END -->\n\n")
+	}
+
+	for _, heading := range headings {
+		b.WriteString(heading + "\n\n<!-- TIMELINE_TABLE_START -->\n| TIME | EVENT |\n| --- | --- |\n| 10:00 | " +
+			heading + " |\n<!-- TIMELINE_TABLE_END -->\n\n")
+	}
+
+	b.WriteString(metadataBlock)
+
+	return b.String()
+}
+
+func eventDates(t *testing.T, markdown string) ([]string, Diagnostics) {
+	t.Helper()
+
+	doc, diags, err := NewParser().ParseDocumentWithDiagnostics(markdown)
+	if err != nil {
+		t.Fatalf("ParseDocumentWithDiagnostics failed: %v", err)
+	}
+
+	dates := make([]string, 0, len(doc.Events))
+	for _, event := range doc.Events {
+		dates = append(dates, event.Date)
+	}
+
+	return dates, diags
+}
+
+func TestParser_LegacyDateHeadings(t *testing.T) {
+	tests := []struct {
+		name      string
+		basicInfo string
+		metadata  string
+		headings  []string
+		want      []string
+	}{
+		{
+			name:      "year from DATE_RANGE",
+			basicInfo: "| DATE_RANGE | 2016-06-21/2016-06-25 |",
+			headings:  []string{"**6月21日**", "### 6月22日（星期三）"},
+			want:      []string{"2016-06-21", "2016-06-22"},
+		},
+		{
+			name:      "December to January rolls the year",
+			basicInfo: "| DATE_RANGE | 2024-12-30 - 2025-03-01 |",
+			headings:  []string{"**12月30日**", "**12月31日**", "### 1月2日", "**2月1日**"},
+			want:      []string{"2024-12-30", "2024-12-31", "2025-01-02", "2025-02-01"},
+		},
+		{
+			name:     "explicit year in heading",
+			headings: []string{"**2008年8月10日**", "### 8月11日（星期一）", "### 2009年1月3日"},
+			want:     []string{"2008-08-10", "2008-08-11", "2009-01-03"},
+		},
+		{
+			name:     "year from metadata",
+			metadata: "<!-- METADATA_START\nVALIDATION: TRUE\nLAST_MODIFY: 2019-03-02T10:00:00Z\nHASH: x\nMETADATA_END -->\n",
+			headings: []string{"**3月1日**"},
+			want:     []string{"2019-03-01"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, diags := eventDates(t, legacyTimeline(tt.basicInfo, tt.metadata, tt.headings...))
+
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("dates = %v, want %v", got, tt.want)
+			}
+
+			for _, d := range diags {
+				if d.Code == CodeAssumedYear {
+					t.Errorf("unexpected %s", d)
+				}
+			}
+		})
+	}
+}
+
+func TestParser_LegacyDateHeadings_AssumedYear(t *testing.T) {
+	got, diags := eventDates(t, legacyTimeline("", "", "**11月26日**", "**11月27日**"))
+
+	if strings.Join(got, ",") != "2025-11-26,2025-11-27" {
+		t.Errorf("dates = %v, want the fallback year", got)
+	}
+
+	if len(diags) != 1 || diags[0].Code != CodeAssumedYear || diags[0].Line != 1 {
+		t.Errorf("Expected one ASSUMED_YEAR warning on line 1, got %v", diags)
+	}
+}
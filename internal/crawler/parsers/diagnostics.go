@@ -0,0 +1,146 @@
+package parsers
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// Diagnostic severities.
+const (
+	// SeverityError marks content that was dropped or could not be parsed.
+	SeverityError = "error"
+	// SeverityWarning marks content that was parsed but is probably not what the author meant.
+	SeverityWarning = "warning"
+)
+
+// Diagnostic codes. Codes are stable and safe to match on; messages are for people.
+const (
+	// CodeMissingSection marks a document without a section its file type needs.
+	CodeMissingSection = "MISSING_SECTION"
+	// CodeUnterminatedSection marks a section start marker without its end marker.
+	CodeUnterminatedSection = "UNTERMINATED_SECTION"
+	// CodeRowBeforeHeader marks a table row that comes before the table's header row.
+	CodeRowBeforeHeader = "ROW_BEFORE_HEADER"
+	// CodeTooFewCells marks a table row with fewer cells than the table needs.
+	CodeTooFewCells = "TOO_FEW_CELLS"
+	// CodeColumnCount marks a table row that was parsed but has more or fewer cells than its header.
+	CodeColumnCount = "COLUMN_COUNT"
+	// CodeMissingTime marks a timeline row without a time.
+	CodeMissingTime = "MISSING_TIME"
+	// CodeInvalidTime marks a timeline row whose time is not HH:MM or a time keyword.
+	CodeInvalidTime = "INVALID_TIME"
+	// CodeInvalidDate marks a row whose date is missing or not YYYY-MM-DD.
+	CodeInvalidDate = "INVALID_DATE"
+	// CodeNoDate marks a timeline row parsed before any date was given.
+	CodeNoDate = "NO_DATE"
+	// CodeAssumedYear marks a date heading whose year could not be found in the document.
+	CodeAssumedYear = "ASSUMED_YEAR"
+	// CodeMissingKey marks a key-value row without its key.
+	CodeMissingKey = "MISSING_KEY"
+	// CodeInvalidNumber marks a cell that should hold a number but does not.
+	CodeInvalidNumber = "INVALID_NUMBER"
+)
+
+// Diagnostic is a problem found while parsing, positioned in the source document.
+type Diagnostic struct {
+	Severity string `json:"severity"`
+	Code     string `json:"code"`
+	// Section is the marker of the section the problem is in, e.g. TIMELINE_TABLE.
+	Section string `json:"section,omitempty"`
+	Message string `json:"message"`
+	// Raw is the offending source line.
+	Raw string `json:"raw,omitempty"`
+	// Line and Column are 1-based; Column is counted in characters and 0 when unknown.
+	Line   int `json:"line"`
+	Column int `json:"column,omitempty"`
+}
+
+// String formats the diagnostic as "line:column: severity CODE [SECTION] message".
+// The position is left out for problems with the document as a whole.
+func (d Diagnostic) String() string {
+	pos := ""
+
+	switch {
+	case d.Line > 0 && d.Column > 0:
+		pos = fmt.Sprintf("%d:%d: ", d.Line, d.Column)
+	case d.Line > 0:
+		pos = fmt.Sprintf("%d: ", d.Line)
+	}
+
+	section := ""
+	if d.Section != "" {
+		section = " [" + d.Section + "]"
+	}
+
+	return fmt.Sprintf("%s%s %s%s %s", pos, d.Severity, d.Code, section, d.Message)
+}
+
+// Diagnostics is the list of problems found in one document, in document order.
+type Diagnostics []Diagnostic
+
+// Errors returns the number of error diagnostics.
+func (ds Diagnostics) Errors() int {
+	n := 0
+
+	for _, d := range ds {
+		if d.Severity == SeverityError {
+			n++
+		}
+	}
+
+	return n
+}
+
+// Fprint writes the diagnostics to w, one per line with the offending source below it.
+// Nothing is written when there are none.
+func (ds Diagnostics) Fprint(w io.Writer) {
+	if len(ds) == 0 {
+		return
+	}
+
+	_, _ = fmt.Fprintf(w, "🩺 Parse diagnostics: %d errors, %d warnings\n", ds.Errors(), len(ds)-ds.Errors())
+
+	for _, d := range ds {
+		icon := "⚠️ "
+		if d.Severity == SeverityError {
+			icon = "❌"
+		}
+
+		_, _ = fmt.Fprintf(w, "  %s %s\n", icon, d)
+
+		if d.Raw != "" {
+			_, _ = fmt.Fprintf(w, "      %s\n", truncateRaw(d.Raw))
+		}
+	}
+}
+
+// diagCollector collects diagnostics for one section while parsing.
+type diagCollector struct {
+	list    Diagnostics
+	section string
+}
+
+func (c *diagCollector) add(severity, code string, line, column int, raw, format string, args ...any) {
+	c.list = append(c.list, Diagnostic{
+		Severity: severity,
+		Code:     code,
+		Section:  c.section,
+		Message:  fmt.Sprintf(format, args...),
+		Raw:      strings.TrimSpace(raw),
+		Line:     line,
+		Column:   column,
+	})
+}
+
+// truncateRaw shortens long source lines for display.
+func truncateRaw(raw string) string {
+	const maxRunes = 120
+
+	if utf8.RuneCountInString(raw) <= maxRunes {
+		return raw
+	}
+
+	return string([]rune(raw)[:maxRunes]) + "…"
+}
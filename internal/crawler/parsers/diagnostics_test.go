@@ -0,0 +1,165 @@
+package parsers
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestParser_ParseDocumentWithDiagnostics(t *testing.T) {
+	markdown := strings.Join([]string{
+		"<!-- TIMELINE_TABLE_START -->",                    // 1
+		"| 2025-11-26 | 14:51 | Before the header |",       // 2
+		"| DATE | TIME | EVENT |",                          // 3
+		"| --- | --- | --- |",                              // 4
+		"| | 14:00 | No date yet |",                        // 5
+		"| 2025-11-26 | 14:51 | Fire reported |",           // 6
+		"| 2025-11-26 | 2pm | Bad time |",                  // 7
+		"| 2025-11-26 |  | No time |",                      // 8
+		"| 2025-11-26 |",                                   // 9
+		"| 2025-11-27 | TIME_ALL_DAY | Search continues |", // 10
+		"<!-- TIMELINE_TABLE_END -->",                      // 11
+	}, "\n")
+
+	doc, diags, err := NewParser().ParseDocumentWithDiagnostics(markdown)
+	if err != nil {
+		t.Fatalf("ParseDocumentWithDiagnostics failed: %v", err)
+	}
+
+	if len(doc.Events) != 3 {
+		t.Errorf("Expected 3 events, got %d", len(doc.Events))
+	}
+
+	want := []struct {
+		severity, code string
+		line, column   int
+	}{
+		{SeverityError, CodeRowBeforeHeader, 2, 1},
+		{SeverityWarning, CodeNoDate, 5, 2},
+		{SeverityError, CodeInvalidTime, 7, 16},
+		{SeverityError, CodeMissingTime, 8, 15},
+		{SeverityError, CodeTooFewCells, 9, 1},
+	}
+
+	if len(diags) != len(want) {
+		t.Fatalf("Expected %d diagnostics, got %d: %v", len(want), len(diags), diags)
+	}
+
+	for i, w := range want {
+		d := diags[i]
+		if d.Severity != w.severity || d.Code != w.code || d.Line != w.line || d.Column != w.column {
+			t.Errorf("diagnostic %d = %s (column %d), want %s %s at %d:%d", i, d, d.Column, w.severity, w.code, w.line, w.column)
+		}
+
+		if d.Section != "TIMELINE_TABLE" || d.Raw == "" {
+			t.Errorf("diagnostic %d has section %q and raw %q", i, d.Section, d.Raw)
+		}
+	}
+
+	if diags.Errors() != 4 {
+		t.Errorf("Expected 4 errors, got %d", diags.Errors())
+	}
+
+	var buf bytes.Buffer
+
+	diags.Fprint(&buf)
+
+	if !strings.Contains(buf.String(), "7:16: error INVALID_TIME [TIMELINE_TABLE]") {
+		t.Errorf("Unexpected output:\n%s", buf.String())
+	}
+}
+
+func TestParser_ParseDocumentWithDiagnostics_Sections(t *testing.T) {
+	_, diags, _ := NewParser().ParseDocumentWithDiagnostics("# No table here")
+	if len(diags) != 1 || diags[0].Code != CodeMissingSection {
+		t.Errorf("Expected MISSING_SECTION, got %v", diags)
+	}
+
+	_, diags, _ = NewParser().ParseDocumentWithDiagnostics("<!-- TIMELINE_TABLE_START -->\n| DATE | TIME | EVENT |")
+	if len(diags) != 1 || diags[0].Code != CodeUnterminatedSection || diags[0].Line != 1 {
+		t.Errorf("Expected UNTERMINATED_SECTION on line 1, got %v", diags)
+	}
+}
+
+func TestParser_ParseDetailedTimelineWithDiagnostics(t *testing.T) {
+	markdown := strings.Join([]string{
+		"<!-- PHASE_START -->",                                            // 1
+		"<!-- TIMELINE_TABLE_START -->",                                   // 2
+		"| DATE | TIME | EVENT | CATEGORY | STATUS | SOURCES |",           // 3
+		"|------|------|-------|----------|--------|---------|",           // 4
+		"| 2025-11-26 | TIME_ALL_DAY | Search | RESCUE | Note | S1 |",     // 5
+		"| 26/11 | 10:00 | Bad date | RESCUE | Note | S1 |",               // 6
+		"| 2025-11-26 | 10:00 | Short |",                                  // 7
+		"<!-- TIMELINE_TABLE_END -->",                                     // 8
+		"<!-- PHASE_END -->",                                              // 9
+		"<!-- LONG_TERM_TRACKING_START -->",                               // 10
+		"| DATE | CATEGORY | EVENT | STATUS | NOTE |",                     // 11
+		"| ---- | -------- | ----- | ------ | ---- |",                     // 12
+		"| 2025-12-01 | HOUSING | UPDATE on rehousing | Pending | Note |", // 13
+		"<!-- LONG_TERM_TRACKING_END -->",                                 // 14
+		"<!-- PHASE_START -->",                                            // 15
+		"Never closed",                                                    // 16
+	}, "\n")
+
+	doc, diags, err := NewParser().ParseDetailedTimelineWithDiagnostics(markdown)
+	if err != nil {
+		t.Fatalf("ParseDetailedTimelineWithDiagnostics failed: %v", err)
+	}
+
+	// Rows containing TIME or DATE in their content are not mistaken for headers
+	if len(doc.Phases) != 1 || len(doc.Phases[0].Events) != 1 {
+		t.Fatalf("Expected 1 phase with 1 event, got %+v", doc.Phases)
+	}
+
+	if len(doc.LongTermTracking) != 1 {
+		t.Errorf("Expected 1 tracking event, got %d", len(doc.LongTermTracking))
+	}
+
+	want := []struct {
+		code    string
+		section string
+		line    int
+	}{
+		{CodeInvalidDate, "TIMELINE_TABLE phase-1", 6},
+		{CodeTooFewCells, "TIMELINE_TABLE phase-1", 7},
+		{CodeUnterminatedSection, "PHASE", 15},
+	}
+
+	if len(diags) != len(want) {
+		t.Fatalf("Expected %d diagnostics, got %d: %v", len(want), len(diags), diags)
+	}
+
+	for i, w := range want {
+		if diags[i].Code != w.code || diags[i].Section != w.section || diags[i].Line != w.line {
+			t.Errorf("diagnostic %d = %s, want %s [%s] on line %d", i, diags[i], w.code, w.section, w.line)
+		}
+	}
+}
+
+func TestParser_ParseDocumentWithDiagnostics_Cells(t *testing.T) {
+	markdown := strings.Join([]string{
+		"<!-- TIMELINE_TABLE_START -->",
+		"| DATE | TIME | EVENT | SOURCE |",
+		"| --- | --- | --- | --- |",
+		`| 2025-11-26 | 14:51 | 火警 \| 四級 | [報告 | 2025](https://example.com) |`,
+		"| 2025-11-26 | 15:02 | `a|b` |",
+		"<!-- TIMELINE_TABLE_END -->",
+	}, "\n")
+
+	doc, diags, err := NewParser().ParseDocumentWithDiagnostics(markdown)
+	if err != nil {
+		t.Fatalf("ParseDocumentWithDiagnostics failed: %v", err)
+	}
+
+	if len(doc.Events) != 2 || doc.Events[0].Description != "火警 | 四級" || doc.Events[1].Description != "`a|b`" {
+		t.Fatalf("Unexpected events: %+v", doc.Events)
+	}
+
+	if len(doc.Events[0].Sources) != 1 || doc.Events[0].Sources[0].URL != "https://example.com" {
+		t.Errorf("Unexpected sources: %+v", doc.Events[0].Sources)
+	}
+
+	if len(diags) != 1 || diags[0].Code != CodeColumnCount || diags[0].Line != 5 {
+		t.Errorf("Expected one COLUMN_COUNT warning on line 5, got %v", diags)
+	}
+}
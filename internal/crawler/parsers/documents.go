@@ -0,0 +1,173 @@
+package parsers
+
+import (
+	"context"
+	"fmt"
+
+	"tpwfc/internal/models"
+	"tpwfc/internal/normalizer"
+	"tpwfc/internal/payload"
+)
+
+// timelineDocument is a parsed FIRE_TIMELINE document.
+type timelineDocument struct {
+	doc *models.TimelineDocument
+}
+
+func (d timelineDocument) Model() any { return d.doc }
+
+func (d timelineDocument) Summary() string { return fmt.Sprintf("%d events", len(d.doc.Events)) }
+
+func (d timelineDocument) Len() int { return len(d.doc.Events) }
+
+func (d timelineDocument) IncidentID() string { return d.doc.BasicInfo.IncidentID }
+
+func (d timelineDocument) Validate() error { return normalizer.NewValidator().Validate(d.doc) }
+
+// Normalize returns the document as a *models.Timeline, with its summary statistics.
+func (d timelineDocument) Normalize() (any, error) {
+	return normalizer.NewTransformer().Transform(d.doc)
+}
+
+// Upload creates or updates the fire incident declared in BASIC_INFO, then its events.
+func (d timelineDocument) Upload(ctx context.Context, u *payload.Uploader, _ int, language string) (*UploadReport, error) {
+	normalized, err := d.Normalize()
+	if err != nil {
+		return nil, err
+	}
+
+	timeline, ok := normalized.(*models.Timeline)
+	if !ok {
+		return nil, fmt.Errorf("%w: %T", ErrUnexpectedOutput, normalized)
+	}
+
+	result, err := u.UploadContext(ctx, timeline, language)
+	if err != nil {
+		return nil, err
+	}
+
+	return &UploadReport{
+		Errors:     result.Errors,
+		Counts:     []UploadCount{{Name: "Events", Created: result.EventsCreated, Updated: result.EventsUpdated}},
+		IncidentID: result.IncidentID,
+	}, nil
+}
+
+// detailedTimelineDocument is a parsed DETAILED_TIMELINE document.
+type detailedTimelineDocument struct {
+	doc *models.DetailedTimelineDocument
+}
+
+func (d detailedTimelineDocument) Model() any { return d.doc }
+
+func (d detailedTimelineDocument) Summary() string {
+	return fmt.Sprintf("%d phases, %d events, %d long-term tracking events, %d category metrics, %d notes",
+		len(d.doc.Phases), d.Len(), len(d.doc.LongTermTracking), len(d.doc.CategoryMetrics), len(d.doc.Notes))
+}
+
+// Len returns the number of events across all phases.
+func (d detailedTimelineDocument) Len() int {
+	n := 0
+	for _, phase := range d.doc.Phases {
+		n += len(phase.Events)
+	}
+
+	return n
+}
+
+func (d detailedTimelineDocument) IncidentID() string { return "" }
+
+func (d detailedTimelineDocument) Validate() error { return normalizer.NewValidator().Validate(d.doc) }
+
+// Normalize returns the document as the *payload.DetailedTimelineData read by the uploader.
+func (d detailedTimelineDocument) Normalize() (any, error) {
+	return d.data(), nil
+}
+
+func (d detailedTimelineDocument) data() *payload.DetailedTimelineData {
+	return &payload.DetailedTimelineData{
+		Phases:           d.doc.Phases,
+		LongTermTracking: d.doc.LongTermTracking,
+		CategoryMetrics:  d.doc.CategoryMetrics,
+		Notes:            d.doc.Notes,
+	}
+}
+
+func (d detailedTimelineDocument) Upload(ctx context.Context, u *payload.Uploader, incidentID int, language string) (*UploadReport, error) {
+	result, err := u.UploadDetailedTimelineContext(ctx, d.data(), incidentID, language)
+	if err != nil {
+		return nil, err
+	}
+
+	return &UploadReport{
+		Errors: result.Errors,
+		Counts: []UploadCount{
+			{Name: "Phases", Created: result.PhasesCreated, Updated: result.PhasesUpdated},
+			{Name: "Events", Created: result.EventsCreated, Updated: result.EventsUpdated},
+			{Name: "Tracking", Created: result.TrackingCreated, Updated: result.TrackingUpdated},
+		},
+		IncidentID: incidentID,
+	}, nil
+}
+
+// investigationDocument is a parsed FIRE_INVESTIGATION document.
+type investigationDocument struct {
+	doc *models.InvestigationDocument
+}
+
+func (d investigationDocument) Model() any { return d.doc }
+
+func (d investigationDocument) Summary() string {
+	return fmt.Sprintf("%d findings", len(d.doc.Findings))
+}
+
+func (d investigationDocument) Len() int { return len(d.doc.Findings) }
+
+func (d investigationDocument) IncidentID() string { return d.doc.Info.IncidentID }
+
+func (d investigationDocument) Validate() error { return normalizer.NewValidator().Validate(d.doc) }
+
+func (d investigationDocument) Normalize() (any, error) { return d.doc, nil }
+
+func (d investigationDocument) Upload(ctx context.Context, u *payload.Uploader, incidentID int, language string) (*UploadReport, error) {
+	result, err := u.UploadInvestigationContext(ctx, d.doc, incidentID, language)
+	if err != nil {
+		return nil, err
+	}
+
+	return &UploadReport{
+		Errors:     result.Errors,
+		Counts:     []UploadCount{{Name: "Findings", Created: result.FindingsCreated, Updated: result.FindingsUpdated}},
+		IncidentID: incidentID,
+	}, nil
+}
+
+// responsesDocument is a parsed FIRE_RESPONSES document.
+type responsesDocument struct {
+	doc *models.ResponsesDocument
+}
+
+func (d responsesDocument) Model() any { return d.doc }
+
+func (d responsesDocument) Summary() string { return fmt.Sprintf("%d responses", len(d.doc.Responses)) }
+
+func (d responsesDocument) Len() int { return len(d.doc.Responses) }
+
+func (d responsesDocument) IncidentID() string { return d.doc.Info.IncidentID }
+
+func (d responsesDocument) Validate() error { return normalizer.NewValidator().Validate(d.doc) }
+
+func (d responsesDocument) Normalize() (any, error) { return d.doc, nil }
+
+func (d responsesDocument) Upload(ctx context.Context, u *payload.Uploader, incidentID int, language string) (*UploadReport, error) {
+	result, err := u.UploadResponsesContext(ctx, d.doc, incidentID, language)
+	if err != nil {
+		return nil, err
+	}
+
+	return &UploadReport{
+		Errors:     result.Errors,
+		Counts:     []UploadCount{{Name: "Responses", Created: result.ResponsesCreated, Updated: result.ResponsesUpdated}},
+		IncidentID: incidentID,
+	}, nil
+}
@@ -0,0 +1,201 @@
+package parsers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+
+	"tpwfc/internal/payload"
+)
+
+// Registered FILE_TYPE values.
+const (
+	FileTypeTimeline         = "FIRE_TIMELINE"
+	FileTypeDetailedTimeline = "DETAILED_TIMELINE"
+	FileTypeInvestigation    = "FIRE_INVESTIGATION"
+	FileTypeResponses        = "FIRE_RESPONSES"
+)
+
+// File type errors.
+var (
+	ErrUnknownFileType   = errors.New("unknown file type")
+	ErrUnexpectedOutput  = errors.New("normalization returned unexpected type")
+	ErrDuplicateFileType = errors.New("file type already registered")
+)
+
+// Document is a parsed document of a registered file type.
+type Document interface {
+	// Model returns the parsed model, e.g. a *models.TimelineDocument.
+	Model() any
+	// Summary describes what was parsed, e.g. "12 events".
+	Summary() string
+	// Len returns the number of rows parsed: events, findings or responses.
+	Len() int
+	// IncidentID returns the incident ID the document declares, or "" when its type has none.
+	IncidentID() string
+	// Validate checks the document is complete enough to sign and upload.
+	Validate() error
+	// Normalize returns the document in the JSON form the normalizer writes and the uploader reads.
+	Normalize() (any, error)
+	// Upload upserts the document to Payload CMS. incidentID is the CMS ID of the fire incident
+	// and is ignored by types that create their own incident (see FileTypeHandler.NeedsIncident).
+	Upload(ctx context.Context, u *payload.Uploader, incidentID int, language string) (*UploadReport, error)
+}
+
+// UploadReport is the outcome of Document.Upload.
+type UploadReport struct {
+	Errors []error
+	Counts []UploadCount
+	// IncidentID is the CMS ID of the fire incident the document was uploaded to.
+	IncidentID int
+}
+
+// UploadCount is the number of records of one kind that an upload created and updated.
+type UploadCount struct {
+	Name    string
+	Created int
+	Updated int
+}
+
+// FileTypeHandler knows how to parse, validate, normalize and upload one FILE_TYPE.
+type FileTypeHandler struct {
+	// Parse parses a document of this type.
+	Parse func(p *Parser, markdown string) (Document, Diagnostics, error)
+	// FileType is the value of the <!-- FILE_TYPE: ... --> tag.
+	FileType string
+	// Name describes the type in command output, e.g. "detailed timeline".
+	Name string
+	// ValidateTable reports whether the markdown validator's timeline table checks apply.
+	ValidateTable bool
+	// NeedsIncident reports whether Upload needs the CMS ID of an existing fire incident.
+	NeedsIncident bool
+}
+
+var fileTypes = map[string]*FileTypeHandler{
+	FileTypeTimeline: {
+		FileType:      FileTypeTimeline,
+		Name:          "timeline",
+		ValidateTable: true,
+		Parse: func(p *Parser, markdown string) (Document, Diagnostics, error) {
+			doc, diags, err := p.ParseDocumentWithDiagnostics(markdown)
+			if err != nil {
+				return nil, diags, err
+			}
+
+			return timelineDocument{doc}, diags, nil
+		},
+	},
+	FileTypeDetailedTimeline: {
+		FileType:      FileTypeDetailedTimeline,
+		Name:          "detailed timeline",
+		ValidateTable: true,
+		NeedsIncident: true,
+		Parse: func(p *Parser, markdown string) (Document, Diagnostics, error) {
+			doc, diags, err := p.ParseDetailedTimelineWithDiagnostics(markdown)
+			if err != nil {
+				return nil, diags, err
+			}
+
+			return detailedTimelineDocument{doc}, diags, nil
+		},
+	},
+	FileTypeInvestigation: {
+		FileType:      FileTypeInvestigation,
+		Name:          "investigation",
+		NeedsIncident: true,
+		Parse: func(p *Parser, markdown string) (Document, Diagnostics, error) {
+			doc, diags, err := p.ParseInvestigationWithDiagnostics(markdown)
+			if err != nil {
+				return nil, diags, err
+			}
+
+			return investigationDocument{doc}, diags, nil
+		},
+	},
+	FileTypeResponses: {
+		FileType:      FileTypeResponses,
+		Name:          "responses",
+		NeedsIncident: true,
+		Parse: func(p *Parser, markdown string) (Document, Diagnostics, error) {
+			doc, diags, err := p.ParseResponsesWithDiagnostics(markdown)
+			if err != nil {
+				return nil, diags, err
+			}
+
+			return responsesDocument{doc}, diags, nil
+		},
+	},
+}
+
+// fileTypeHeuristics maps the section markers of documents without a FILE_TYPE tag to their
+// type, most specific first: detailed timelines also have TIMELINE_TABLE sections.
+var fileTypeHeuristics = []struct {
+	marker   *regexp.Regexp
+	fileType string
+}{
+	{regexp.MustCompile(`<!--\s*PHASE_START\s*-->`), FileTypeDetailedTimeline},
+	{regexp.MustCompile(`<!--\s*FINDINGS_START\s*-->`), FileTypeInvestigation},
+	{regexp.MustCompile(`<!--\s*RESPONSES_START\s*-->`), FileTypeResponses},
+	{regexp.MustCompile(`<!--\s*(?:TIMELINE_TABLE|BASIC_INFO)_START\s*-->`), FileTypeTimeline},
+}
+
+// Register adds a handler for h.FileType. It is not safe for concurrent use and is meant to be
+// called from an init function: the crawler, normalizer, signer and worker then pick the type up.
+func Register(h *FileTypeHandler) error {
+	if _, ok := fileTypes[h.FileType]; ok {
+		return fmt.Errorf("%w: %s", ErrDuplicateFileType, h.FileType)
+	}
+
+	fileTypes[h.FileType] = h
+
+	return nil
+}
+
+// LookupFileType returns the handler registered for fileType.
+func LookupFileType(fileType string) (*FileTypeHandler, bool) {
+	h, ok := fileTypes[fileType]
+
+	return h, ok
+}
+
+// FileTypes returns the registered file types, sorted.
+func FileTypes() []string {
+	types := make([]string, 0, len(fileTypes))
+	for fileType := range fileTypes {
+		types = append(types, fileType)
+	}
+
+	sort.Strings(types)
+
+	return types
+}
+
+// DetectFileType returns the FILE_TYPE of content. Documents without the tag are recognised by
+// their section markers, and default to DETAILED_TIMELINE, the only type that predates the tag.
+func (p *Parser) DetectFileType(content string) string {
+	if fileType := p.ParseFileType(content); fileType != "" {
+		return fileType
+	}
+
+	for _, h := range fileTypeHeuristics {
+		if h.marker.MatchString(content) {
+			return h.fileType
+		}
+	}
+
+	return FileTypeDetailedTimeline
+}
+
+// FileTypeHandler returns the handler for the detected FILE_TYPE of content.
+func (p *Parser) FileTypeHandler(content string) (*FileTypeHandler, error) {
+	fileType := p.DetectFileType(content)
+
+	h, ok := LookupFileType(fileType)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownFileType, fileType)
+	}
+
+	return h, nil
+}
@@ -0,0 +1,186 @@
+package parsers
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"tpwfc/internal/models"
+	"tpwfc/internal/normalizer"
+	"tpwfc/internal/payload"
+)
+
+func TestParser_DetectFileType(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"tag", "<!-- FILE_TYPE: FIRE_RESPONSES -->\n<!-- PHASE_START -->", FileTypeResponses},
+		{"unknown tag", "<!-- FILE_TYPE: PRESS_RELEASE -->", "PRESS_RELEASE"},
+		{"phases", "<!-- PHASE_START -->\n<!-- TIMELINE_TABLE_START -->", FileTypeDetailedTimeline},
+		{"findings", "<!-- FINDINGS_START -->", FileTypeInvestigation},
+		{"responses", "<!--RESPONSES_START-->", FileTypeResponses},
+		{"timeline table", "<!-- TIMELINE_TABLE_START -->", FileTypeTimeline},
+		{"basic info", "<!-- BASIC_INFO_START -->", FileTypeTimeline},
+		{"no markers", "# Notes", FileTypeDetailedTimeline},
+	}
+
+	p := NewParser()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := p.DetectFileType(tt.content); got != tt.want {
+				t.Errorf("DetectFileType() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParser_FileTypeHandler(t *testing.T) {
+	p := NewParser()
+
+	if _, err := p.FileTypeHandler("<!-- FILE_TYPE: PRESS_RELEASE -->"); !errors.Is(err, ErrUnknownFileType) {
+		t.Errorf("FileTypeHandler(unknown) error = %v, want ErrUnknownFileType", err)
+	}
+
+	want := []string{FileTypeDetailedTimeline, FileTypeInvestigation, FileTypeResponses, FileTypeTimeline}
+	if got := FileTypes(); !slices.Equal(got, want) {
+		t.Errorf("FileTypes() = %v, want %v", got, want)
+	}
+
+	if err := Register(&FileTypeHandler{FileType: FileTypeTimeline}); !errors.Is(err, ErrDuplicateFileType) {
+		t.Errorf("Register(duplicate) error = %v, want ErrDuplicateFileType", err)
+	}
+}
+
+func TestFileTypeHandler_Documents(t *testing.T) {
+	timeline := strings.Join([]string{
+		"<!-- FILE_TYPE: FIRE_TIMELINE -->",
+		"<!-- BASIC_INFO_START -->",
+		"| KEY | VALUE |",
+		"| --- | --- |",
+		"| INCIDENT_ID | TEST_FIRE |",
+		"| INCIDENT_NAME | Test Fire |",
+		"<!-- BASIC_INFO_END -->",
+		"<!-- SOURCES_START -->",
+		"| SOURCE_ID | SOURCE_NAME | SOURCE_TITLE | SOURCE_URL |",
+		"| --- | --- | --- | --- |",
+		"| S1 | A | Title | https://a |",
+		"<!-- SOURCES_END -->",
+		"**2025年1月2日**",
+		"<!-- TIMELINE_TABLE_START -->",
+		"| TIME | EVENT |",
+		"| --- | --- |",
+		"| 10:00 | Alarm raised |",
+		"<!-- TIMELINE_TABLE_END -->",
+	}, "\n")
+
+	detailed := strings.Join([]string{
+		"<!-- PHASE_START -->",
+		"<!-- LONG_TERM_TRACKING_START -->",
+		"| DATE | CATEGORY | EVENT | STATUS | NOTE |",
+		"| --- | --- | --- | --- | --- |",
+		"| 2025-02-01 | REVIEW | Inquiry opened | ONGOING | |",
+		"<!-- LONG_TERM_TRACKING_END -->",
+		"<!-- PHASE_END -->",
+	}, "\n")
+
+	responses := strings.Join([]string{
+		"<!-- FILE_TYPE: FIRE_RESPONSES -->",
+		"<!-- RESPONSES_INFO_START -->",
+		"| INCIDENT_ID | TEST_FIRE |",
+		"<!-- RESPONSES_INFO_END -->",
+		"<!-- RESPONSES_START -->",
+		"| DATE | ORGANIZATION | ORGANIZATION_TYPE | RESPONSE_TYPE | RESPONSE | SOURCES |",
+		"| --- | --- | --- | --- | --- | --- |",
+		"| 2025-01-03 | Government | GOVERNMENT | RELIEF | Relief fund | |",
+		"<!-- RESPONSES_END -->",
+	}, "\n")
+
+	tests := []struct {
+		name           string
+		markdown       string
+		fileType       string
+		incidentID     string
+		normalized     func(any) bool
+		wantValidation error
+		wantLen        int
+	}{
+		{
+			name:       "timeline",
+			markdown:   timeline,
+			fileType:   FileTypeTimeline,
+			incidentID: "TEST_FIRE",
+			normalized: func(v any) bool {
+				tl, ok := v.(*models.Timeline)
+
+				return ok && tl.Summary.TotalEvents == 1
+			},
+			wantLen: 1,
+		},
+		{
+			name:     "detailed timeline",
+			markdown: detailed,
+			fileType: FileTypeDetailedTimeline,
+			normalized: func(v any) bool {
+				data, ok := v.(*payload.DetailedTimelineData)
+
+				return ok && len(data.LongTermTracking) == 1
+			},
+		},
+		{
+			name:       "responses",
+			markdown:   responses,
+			fileType:   FileTypeResponses,
+			incidentID: "TEST_FIRE",
+			normalized: func(v any) bool {
+				doc, ok := v.(*models.ResponsesDocument)
+
+				return ok && len(doc.Responses) == 1
+			},
+			wantLen: 1,
+		},
+		{
+			name:           "empty detailed timeline",
+			markdown:       "<!-- FILE_TYPE: DETAILED_TIMELINE -->",
+			fileType:       FileTypeDetailedTimeline,
+			normalized:     func(any) bool { return true },
+			wantValidation: normalizer.ErrNoPhases,
+		},
+	}
+
+	p := NewParser()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, err := p.FileTypeHandler(tt.markdown)
+			if err != nil || h.FileType != tt.fileType {
+				t.Fatalf("FileTypeHandler() = %v, %v, want %s", h, err, tt.fileType)
+			}
+
+			doc, diags, err := h.Parse(p, tt.markdown)
+			if err != nil || diags.Errors() > 0 {
+				t.Fatalf("Parse() error = %v, diagnostics = %v", err, diags)
+			}
+
+			if got := doc.IncidentID(); got != tt.incidentID {
+				t.Errorf("IncidentID() = %q, want %q", got, tt.incidentID)
+			}
+
+			if got := doc.Len(); got != tt.wantLen {
+				t.Errorf("Len() = %d, want %d", got, tt.wantLen)
+			}
+
+			if validateErr := doc.Validate(); !errors.Is(validateErr, tt.wantValidation) {
+				t.Errorf("Validate() = %v, want %v", validateErr, tt.wantValidation)
+			}
+
+			normalized, err := doc.Normalize()
+			if err != nil || !tt.normalized(normalized) {
+				t.Errorf("Normalize() = %#v, %v", normalized, err)
+			}
+		})
+	}
+}
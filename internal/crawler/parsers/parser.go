@@ -8,6 +8,8 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+
+	"tpwfc/internal/mdtable"
 )
 
 // Common constants.
@@ -23,42 +25,20 @@ const (
 
 // Column Constants for dynamic parsing.
 const (
-	ColDate       = "DATE"
-	ColTime       = "TIME"
-	ColEvent      = "EVENT"
-	ColCategory   = "CATEGORY"
-	ColCasualties = "CASUALTIES"
-	ColSource     = "SOURCE"
-	ColVideo      = "VIDEO"
-	ColPhoto      = "PHOTO"
-	ColEnd        = "END"
+	ColDate       = mdtable.ColDate
+	ColTime       = mdtable.ColTime
+	ColEvent      = mdtable.ColEvent
+	ColCategory   = mdtable.ColCategory
+	ColCasualties = mdtable.ColCasualties
+	ColSource     = mdtable.ColSource
+	ColVideo      = mdtable.ColVideo
+	ColPhoto      = mdtable.ColPhoto
+	ColEnd        = mdtable.ColEnd
 )
 
 // NormalizeHeader standardizes header names to internal constants.
 func NormalizeHeader(header string) string {
-	h := strings.ToUpper(strings.TrimSpace(header))
-	switch h {
-	case "DATE", "日期":
-		return ColDate
-	case "TIME", "時間", "时间":
-		return ColTime
-	case "EVENT", "事件", "DESCRIPTION", "描述":
-		return ColEvent
-	case "CATEGORY", "類別", "类别":
-		return ColCategory
-	case "CASUALTIES", "死傷狀況", "死伤状况":
-		return ColCasualties
-	case "SOURCE", "SOURCES", "來源", "来源":
-		return ColSource
-	case "VIDEO", "影片", "视频":
-		return ColVideo
-	case "PHOTO", "PHOTOS", "圖片", "图片", "PHOTO/IMAGE":
-		return ColPhoto
-	case "END", "結束", "结束":
-		return ColEnd
-	default:
-		return h
-	}
+	return mdtable.NormalizeHeader(header)
 }
 
 // Parser errors.
@@ -96,10 +76,10 @@ type Parser struct {
 // NewParser creates a new parser instance.
 func NewParser() *Parser {
 	return &Parser{
-		// Pattern for **11月26日** format
-		datePattern: regexp.MustCompile(`\*\*(\d{1,2})月(\d{1,2})日\*\*`),
-		// Pattern for ### 11月26日（星期一） format (markdown heading with optional weekday)
-		datePatternAlt: regexp.MustCompile(`^#{1,3}\s*(\d{1,2})月(\d{1,2})日`),
+		// Pattern for **11月26日** and **2025年11月26日** formats
+		datePattern: regexp.MustCompile(`\*\*(?:(\d{4})年)?(\d{1,2})月(\d{1,2})日\*\*`),
+		// Pattern for ### 11月26日（星期一） format (markdown heading with optional year and weekday)
+		datePatternAlt: regexp.MustCompile(`^#{1,3}\s*(?:(\d{4})年)?(\d{1,2})月(\d{1,2})日`),
 		// Pattern for ISO date format YYYY-MM-DD
 		datePatternISO: regexp.MustCompile(`^(\d{4})-(\d{1,2})-(\d{1,2})$`),
 		linkPattern:    regexp.MustCompile(`\[(.*?)\]\((.*?)\)`),
@@ -424,14 +404,6 @@ type Photo struct {
 
 // Helper functions
 
-func padZero(s string) string {
-	if len(s) == 1 {
-		return "0" + s
-	}
-
-	return s
-}
-
 func normalizeTime(timeStr string) string {
 	// Convert "14:50左右" or "14:50" to "14:50"
 	timeStr = strings.TrimSpace(timeStr)
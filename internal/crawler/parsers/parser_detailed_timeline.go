@@ -4,14 +4,24 @@ package parsers
 import (
 	"fmt"
 	"regexp"
+	"slices"
 	"strings"
 
+	"tpwfc/internal/mdtable"
 	"tpwfc/internal/models"
 	"tpwfc/pkg/metadata"
 )
 
 // ParseDetailedTimeline parses the detailed timeline markdown and returns a DetailedTimelineDocument.
 func (p *Parser) ParseDetailedTimeline(markdown string) (*models.DetailedTimelineDocument, error) {
+	doc, _, err := p.ParseDetailedTimelineWithDiagnostics(markdown)
+
+	return doc, err
+}
+
+// ParseDetailedTimelineWithDiagnostics parses the detailed timeline markdown and also reports
+// the phases and rows it had to drop or could only partly understand.
+func (p *Parser) ParseDetailedTimelineWithDiagnostics(markdown string) (*models.DetailedTimelineDocument, Diagnostics, error) {
 	// Strip metadata block if present
 	meta, cleanMarkdown := metadata.Extract(markdown)
 	markdown = cleanMarkdown
@@ -20,25 +30,40 @@ func (p *Parser) ParseDetailedTimeline(markdown string) (*models.DetailedTimelin
 		Metadata: meta,
 	}
 
+	var diags, sectionDiags Diagnostics
+
 	// Parse phases
-	doc.Phases = p.parsePhases(markdown)
+	doc.Phases, sectionDiags = p.parsePhases(markdown)
+	diags = append(diags, sectionDiags...)
 
 	// Parse long-term tracking
-	doc.LongTermTracking = p.parseLongTermTracking(markdown)
+	doc.LongTermTracking, sectionDiags = p.parseLongTermTracking(markdown)
+	diags = append(diags, sectionDiags...)
 
 	// Parse category metrics
-	doc.CategoryMetrics = p.parseCategoryMetrics(markdown)
+	doc.CategoryMetrics, sectionDiags = p.parseCategoryMetrics(markdown)
+	diags = append(diags, sectionDiags...)
 
 	// Parse notes
 	doc.Notes = p.parseNotes(markdown)
 
-	return doc, nil
+	slices.SortStableFunc(diags, func(a, b Diagnostic) int { return a.Line - b.Line })
+
+	return doc, diags, nil
+}
+
+// isHeaderRow reports whether row is a header row whose first column is the date, as in the
+// detailed timeline and tracking tables.
+func isHeaderRow(row mdtable.Row) bool {
+	return NormalizeHeader(row.Text(0)) == ColDate
 }
 
 // parseCategoryMetrics extracts category metrics from the CATEGORY_METRICS section.
-func (p *Parser) parseCategoryMetrics(markdown string) []models.CategoryMetric {
+func (p *Parser) parseCategoryMetrics(markdown string) ([]models.CategoryMetric, Diagnostics) {
 	var metrics []models.CategoryMetric
 
+	diags := &diagCollector{section: "CATEGORY_METRICS"}
+
 	lines := strings.Split(markdown, "\n")
 
 	startPattern := regexp.MustCompile(`<!--\s*CATEGORY_METRICS_START\s*-->`)
@@ -46,7 +71,7 @@ func (p *Parser) parseCategoryMetrics(markdown string) []models.CategoryMetric {
 
 	inSection := false
 
-	for _, line := range lines {
+	for i, line := range lines {
 		if startPattern.MatchString(line) {
 			inSection = true
 
@@ -59,30 +84,43 @@ func (p *Parser) parseCategoryMetrics(markdown string) []models.CategoryMetric {
 
 		if inSection && strings.HasPrefix(line, "|") {
 			// Skip header and separator rows
-			if strings.Contains(line, "CATEGORY") || strings.Contains(line, "METRIC_KEY") || strings.HasPrefix(line, "|---") || strings.Contains(line, "---") {
+			row := mdtable.ParseRow(line)
+			if strings.Contains(line, "CATEGORY") || strings.Contains(line, "METRIC_KEY") || row.IsSeparator() {
 				continue
 			}
 
-			cells := strings.Split(line, "|")
-			// Expected columns: Empty, Category, MetricKey, MetricLabel, MetricValue, MetricUnit, Empty
-			if len(cells) < 6 {
+			// Expected columns: Category, MetricKey, MetricLabel, MetricValue, MetricUnit
+			if row.Len() < 5 {
+				diags.add(SeverityError, CodeTooFewCells, i+1, row.Start(), line,
+					"row dropped: %d cells, expected 5 (CATEGORY | METRIC_KEY | METRIC_LABEL | METRIC_VALUE | METRIC_UNIT)", row.Len())
+
 				continue
 			}
 
-			category := strings.TrimSpace(cells[1])
-			metricKey := strings.TrimSpace(cells[2])
-			metricLabel := strings.TrimSpace(cells[3])
-			metricValueStr := strings.TrimSpace(cells[4])
-			metricUnit := strings.TrimSpace(cells[5])
+			if err := row.CheckWidth(5); err != nil {
+				diags.add(SeverityWarning, CodeColumnCount, i+1, row.Start(), line, "%v", err)
+			}
+
+			category := row.Text(0)
+			metricKey := row.Text(1)
+			metricLabel := row.Text(2)
+			metricValueStr := row.Text(3)
+			metricUnit := row.Text(4)
 
 			// Skip invalid rows (empty or separator-like content)
 			if category == "" || metricKey == "" || strings.HasPrefix(category, "-") {
+				diags.add(SeverityError, CodeMissingKey, i+1, row.Column(0), line,
+					"row dropped: CATEGORY and METRIC_KEY are required")
+
 				continue
 			}
 
 			// Parse metric value as float64
 			var metricValue float64
-			_, _ = fmt.Sscanf(metricValueStr, "%f", &metricValue)
+			if _, err := fmt.Sscanf(metricValueStr, "%f", &metricValue); err != nil {
+				diags.add(SeverityWarning, CodeInvalidNumber, i+1, row.Column(3), line,
+					"METRIC_VALUE %q is not a number, using 0", metricValueStr)
+			}
 
 			metric := models.CategoryMetric{
 				Category:    category,
@@ -95,13 +133,17 @@ func (p *Parser) parseCategoryMetrics(markdown string) []models.CategoryMetric {
 		}
 	}
 
-	return metrics
+	return metrics, diags.list
 }
 
 // parsePhases extracts all phases from the detailed timeline markdown.
-func (p *Parser) parsePhases(markdown string) []models.DetailedTimelinePhase {
+func (p *Parser) parsePhases(markdown string) ([]models.DetailedTimelinePhase, Diagnostics) {
 	var phases []models.DetailedTimelinePhase
 
+	var diags Diagnostics
+
+	unterminated := &diagCollector{section: "PHASE"}
+
 	lines := strings.Split(markdown, "\n")
 
 	phaseStartPattern := regexp.MustCompile(`<!--\s*PHASE_START\s*-->`)
@@ -115,10 +157,17 @@ func (p *Parser) parsePhases(markdown string) []models.DetailedTimelinePhase {
 
 	inPhase := false
 	phaseCount := 0
+	phaseStart := 0
 
-	for _, line := range lines {
+	for i, line := range lines {
 		if phaseStartPattern.MatchString(line) {
+			if inPhase {
+				unterminated.add(SeverityError, CodeUnterminatedSection, phaseStart, 0, lines[phaseStart-1],
+					"phase dropped: <!-- PHASE_START --> is followed by another PHASE_START before its PHASE_END")
+			}
+
 			inPhase = true
+			phaseStart = i + 1
 			phaseLines = []string{}
 
 			continue
@@ -130,8 +179,9 @@ func (p *Parser) parsePhases(markdown string) []models.DetailedTimelinePhase {
 
 			// Parse the collected phase
 			phaseContent := strings.Join(phaseLines, "\n")
-			phase := p.parseSinglePhase(phaseContent, phaseCount, phaseInfoStartPattern, phaseInfoEndPattern, phaseDescStartPattern, phaseDescEndPattern)
+			phase, phaseDiags := p.parseSinglePhase(phaseContent, phaseCount, phaseStart+1, phaseInfoStartPattern, phaseInfoEndPattern, phaseDescStartPattern, phaseDescEndPattern)
 			phases = append(phases, phase)
+			diags = append(diags, phaseDiags...)
 
 			continue
 		}
@@ -141,11 +191,16 @@ func (p *Parser) parsePhases(markdown string) []models.DetailedTimelinePhase {
 		}
 	}
 
-	return phases
+	if inPhase {
+		unterminated.add(SeverityError, CodeUnterminatedSection, phaseStart, 0, lines[phaseStart-1],
+			"phase dropped: <!-- PHASE_START --> has no matching <!-- PHASE_END -->")
+	}
+
+	return phases, append(diags, unterminated.list...)
 }
 
-// parseSinglePhase parses a single phase block.
-func (p *Parser) parseSinglePhase(content string, phaseNum int, infoStart, infoEnd, descStart, descEnd *regexp.Regexp) models.DetailedTimelinePhase {
+// parseSinglePhase parses a single phase block whose first line is line firstLine of the document.
+func (p *Parser) parseSinglePhase(content string, phaseNum, firstLine int, infoStart, infoEnd, descStart, descEnd *regexp.Regexp) (models.DetailedTimelinePhase, Diagnostics) {
 	phase := models.DetailedTimelinePhase{
 		ID: fmt.Sprintf("phase-%d", phaseNum),
 	}
@@ -185,10 +240,10 @@ func (p *Parser) parseSinglePhase(content string, phaseNum int, infoStart, infoE
 		}
 
 		if inInfo && strings.HasPrefix(line, "|") && !strings.Contains(line, "KEY") && !strings.HasPrefix(line, "|---") {
-			cells := strings.Split(line, "|")
-			if len(cells) >= 3 {
-				key := strings.TrimSpace(cells[1])
-				value := strings.TrimSpace(cells[2])
+			row := mdtable.ParseRow(line)
+			if row.Len() >= 2 {
+				key := row.Text(0)
+				value := row.Text(1)
 
 				switch key {
 				case "PHASE_NAME":
@@ -215,21 +270,27 @@ func (p *Parser) parseSinglePhase(content string, phaseNum int, infoStart, infoE
 	}
 
 	// Parse events within this phase
-	phase.Events = p.parseDetailedTimelineEvents(content)
+	events, diags := p.parseDetailedTimelineEvents(content, phase.ID, firstLine)
+	phase.Events = events
 
-	return phase
+	return phase, diags
 }
 
 // parseDetailedTimelineEvents extracts events from a phase's timeline table.
-func (p *Parser) parseDetailedTimelineEvents(phaseContent string) []models.DetailedTimelineEvent {
+// firstLine is the document line number of the first line of phaseContent.
+func (p *Parser) parseDetailedTimelineEvents(phaseContent, phaseID string, firstLine int) ([]models.DetailedTimelineEvent, Diagnostics) {
 	var events []models.DetailedTimelineEvent
 
 	lines := strings.Split(phaseContent, "\n")
+	diags := &diagCollector{section: "TIMELINE_TABLE " + phaseID}
 
 	inTable := false
 	eventCount := 0
+	headerWidth := 0
+
+	for i, line := range lines {
+		lineNum := firstLine + i
 
-	for _, line := range lines {
 		if p.tableStartPattern.MatchString(line) {
 			inTable = true
 
@@ -243,47 +304,68 @@ func (p *Parser) parseDetailedTimelineEvents(phaseContent string) []models.Detai
 		}
 
 		if inTable && strings.HasPrefix(line, "|") {
+			row := mdtable.ParseRow(line)
+
 			// Skip header and separator rows
-			if strings.Contains(line, "DATE") || strings.Contains(line, "TIME") || strings.HasPrefix(line, "|---") {
+			if isHeaderRow(row) {
+				headerWidth = row.Len()
+
 				continue
 			}
 
-			cells := strings.Split(line, "|")
-			if len(cells) < 7 {
+			if row.IsSeparator() {
 				continue
 			}
 
-			dateStr := strings.TrimSpace(cells[1])
-			timeStr := strings.TrimSpace(cells[2])
-			eventDesc := strings.TrimSpace(cells[3])
-			category := strings.TrimSpace(cells[4])
-			statusNote := strings.TrimSpace(cells[5])
-			sourcesStr := strings.TrimSpace(cells[6])
+			if row.Len() < 6 {
+				diags.add(SeverityError, CodeTooFewCells, lineNum, row.Start(), line,
+					"row dropped: %d cells, expected at least 6 (DATE | TIME | EVENT | CATEGORY | STATUS | SOURCES)", row.Len())
+
+				continue
+			}
+
+			if headerWidth > 0 {
+				if err := row.CheckWidth(headerWidth); err != nil {
+					diags.add(SeverityWarning, CodeColumnCount, lineNum, row.Start(), line, "%v", err)
+				}
+			}
+
+			dateStr := row.Text(0)
+			timeStr := row.Text(1)
+			eventDesc := row.Text(2)
+			category := row.Text(3)
+			statusNote := row.Text(4)
+			sourcesStr := row.Text(5)
 
 			// Skip invalid rows
 			if dateStr == "" || !regexp.MustCompile(`\d{4}-\d{2}-\d{2}`).MatchString(dateStr) {
+				diags.add(SeverityError, CodeInvalidDate, lineNum, row.Column(0), line,
+					"row dropped: DATE %q is not YYYY-MM-DD", dateStr)
+
 				continue
 			}
 
+			if !isValidTime(timeStr) {
+				diags.add(SeverityWarning, CodeInvalidTime, lineNum, row.Column(1), line,
+					"TIME %q is not HH:MM, %s or %s", timeStr, TimeAllDay, TimeOngoing)
+			}
+
 			eventCount++
 
 			// Parse optional video and photo columns
 			var videoURL, photoURL string
-			if len(cells) > 7 {
-				videoURL = parseVideoURL(strings.TrimSpace(cells[7]))
+			if row.Len() > 6 {
+				videoURL = parseVideoURL(row.Text(6))
 			}
 
-			if len(cells) > 8 {
-				photoURL = parseVideoURL(strings.TrimSpace(cells[8])) // Reuse same link extractor
+			if row.Len() > 7 {
+				photoURL = parseVideoURL(row.Text(7)) // Reuse same link extractor
 			}
 
 			// Extract end flag from cell 9 (if present)
 			var isCategoryEnd bool
-			if len(cells) > 9 {
-				endStr := strings.TrimSpace(cells[9])
-				if strings.EqualFold(endStr, "x") || strings.EqualFold(endStr, "true") {
-					isCategoryEnd = true
-				}
+			if endStr := row.Text(8); strings.EqualFold(endStr, "x") || strings.EqualFold(endStr, "true") {
+				isCategoryEnd = true
 			}
 
 			// Parse sources for URL extraction
@@ -328,13 +410,15 @@ func (p *Parser) parseDetailedTimelineEvents(phaseContent string) []models.Detai
 		}
 	}
 
-	return events
+	return events, diags.list
 }
 
 // parseLongTermTracking extracts long-term tracking events.
-func (p *Parser) parseLongTermTracking(markdown string) []models.LongTermTrackingEvent {
+func (p *Parser) parseLongTermTracking(markdown string) ([]models.LongTermTrackingEvent, Diagnostics) {
 	var events []models.LongTermTrackingEvent
 
+	diags := &diagCollector{section: "LONG_TERM_TRACKING"}
+
 	lines := strings.Split(markdown, "\n")
 
 	startPattern := regexp.MustCompile(`<!--\s*LONG_TERM_TRACKING_START\s*-->`)
@@ -343,7 +427,7 @@ func (p *Parser) parseLongTermTracking(markdown string) []models.LongTermTrackin
 	inSection := false
 	eventCount := 0
 
-	for _, line := range lines {
+	for i, line := range lines {
 		if startPattern.MatchString(line) {
 			inSection = true
 
@@ -355,24 +439,35 @@ func (p *Parser) parseLongTermTracking(markdown string) []models.LongTermTrackin
 		}
 
 		if inSection && strings.HasPrefix(line, "|") {
+			row := mdtable.ParseRow(line)
+
 			// Skip header and separator rows
-			if strings.Contains(line, "DATE") || strings.Contains(line, "CATEGORY") || strings.HasPrefix(line, "|---") {
+			if isHeaderRow(row) || row.IsSeparator() {
 				continue
 			}
 
-			cells := strings.Split(line, "|")
-			if len(cells) < 6 {
+			if row.Len() < 5 {
+				diags.add(SeverityError, CodeTooFewCells, i+1, row.Start(), line,
+					"row dropped: %d cells, expected 5 (DATE | CATEGORY | EVENT | STATUS | NOTE)", row.Len())
+
 				continue
 			}
 
-			dateStr := strings.TrimSpace(cells[1])
-			category := strings.TrimSpace(cells[2])
-			eventDesc := strings.TrimSpace(cells[3])
-			status := strings.TrimSpace(cells[4])
-			note := strings.TrimSpace(cells[5])
+			if err := row.CheckWidth(5); err != nil {
+				diags.add(SeverityWarning, CodeColumnCount, i+1, row.Start(), line, "%v", err)
+			}
+
+			dateStr := row.Text(0)
+			category := row.Text(1)
+			eventDesc := row.Text(2)
+			status := row.Text(3)
+			note := row.Text(4)
 
 			// Skip invalid rows
 			if dateStr == "" || !regexp.MustCompile(`\d{4}-\d{2}-\d{2}`).MatchString(dateStr) {
+				diags.add(SeverityError, CodeInvalidDate, i+1, row.Column(0), line,
+					"row dropped: DATE %q is not YYYY-MM-DD", dateStr)
+
 				continue
 			}
 
@@ -397,7 +492,7 @@ func (p *Parser) parseLongTermTracking(markdown string) []models.LongTermTrackin
 		}
 	}
 
-	return events
+	return events, diags.list
 }
 
 // parseDateRange normalizes date range string and extracts start/end dates.
@@ -0,0 +1,168 @@
+package parsers
+
+import (
+	"fmt"
+
+	"tpwfc/internal/mdtable"
+	"tpwfc/internal/models"
+	"tpwfc/pkg/metadata"
+)
+
+// Investigation and response table columns, as named by NormalizeHeader.
+const (
+	ColFinding          = mdtable.ColFinding
+	ColStatus           = mdtable.ColStatus
+	ColOrganization     = mdtable.ColOrganization
+	ColOrganizationType = mdtable.ColOrganizationType
+	ColResponseType     = mdtable.ColResponseType
+	ColResponse         = mdtable.ColResponse
+)
+
+// ParseInvestigation parses a FIRE_INVESTIGATION document.
+func (p *Parser) ParseInvestigation(markdown string) (*models.InvestigationDocument, error) {
+	doc, _, err := p.ParseInvestigationWithDiagnostics(markdown)
+
+	return doc, err
+}
+
+// ParseInvestigationWithDiagnostics parses a FIRE_INVESTIGATION document and also reports the
+// findings it had to drop or could only partly understand.
+//
+// The document has an INVESTIGATION_INFO key-value table, a SUMMARY section, a FINDINGS table
+// (DATE | CATEGORY | FINDING | STATUS | SOURCES) and the usual SOURCES and NOTES sections.
+func (p *Parser) ParseInvestigationWithDiagnostics(markdown string) (*models.InvestigationDocument, Diagnostics, error) {
+	// Strip metadata block if present
+	meta, cleanMarkdown := metadata.Extract(markdown)
+	markdown = cleanMarkdown
+
+	info := parseKeyValues(markdown, "INVESTIGATION_INFO")
+	doc := &models.InvestigationDocument{
+		Metadata: meta,
+		Info: models.InvestigationInfo{
+			IncidentID:   info["INCIDENT_ID"],
+			Title:        info["TITLE"],
+			Investigator: info["INVESTIGATOR"],
+			Status:       info["STATUS"],
+			StartDate:    info["START_DATE"],
+			ReportDate:   info["REPORT_DATE"],
+		},
+	}
+
+	summaryStart, summaryEnd := sectionPatterns("SUMMARY")
+	doc.Summary = p.parseSection(markdown, summaryStart, summaryEnd)
+
+	diags := &diagCollector{section: "FINDINGS"}
+	table := parseMarkedTable(markdown, "FINDINGS", diags)
+	ordinals := make(map[string]int)
+
+	for i, row := range table.rows {
+		line := table.lines[i]
+		date := table.cell(row, ColDate)
+		finding := table.cell(row, ColFinding)
+
+		if !p.datePatternISO.MatchString(date) {
+			diags.add(SeverityError, CodeInvalidDate, line, table.column(row, ColDate), row.Line,
+				"row dropped: DATE %q is not YYYY-MM-DD", date)
+
+			continue
+		}
+
+		if finding == "" {
+			diags.add(SeverityError, CodeMissingKey, line, table.column(row, ColFinding), row.Line,
+				"row dropped: FINDING is required")
+
+			continue
+		}
+
+		category := table.cell(row, ColCategory)
+		ordinals[date+"|"+category]++
+
+		doc.Findings = append(doc.Findings, models.InvestigationFinding{
+			ID:       generateEventID(date, fmt.Sprintf("FINDING#%d", ordinals[date+"|"+category]), category),
+			Date:     date,
+			Category: category,
+			Finding:  finding,
+			Status:   table.cell(row, ColStatus),
+			Sources:  p.eventSources(table.cell(row, ColSource)),
+		})
+	}
+
+	doc.Sources = p.parseSourcesSection(markdown)
+	doc.Notes = p.parseNotes(markdown)
+
+	return doc, diags.list, nil
+}
+
+// ParseResponses parses a FIRE_RESPONSES document.
+func (p *Parser) ParseResponses(markdown string) (*models.ResponsesDocument, error) {
+	doc, _, err := p.ParseResponsesWithDiagnostics(markdown)
+
+	return doc, err
+}
+
+// ParseResponsesWithDiagnostics parses a FIRE_RESPONSES document and also reports the
+// responses it had to drop or could only partly understand.
+//
+// The document has a RESPONSES_INFO key-value table, a RESPONSES table
+// (DATE | ORGANIZATION | ORGANIZATION_TYPE | RESPONSE_TYPE | RESPONSE | SOURCES) and the usual
+// SOURCES and NOTES sections.
+func (p *Parser) ParseResponsesWithDiagnostics(markdown string) (*models.ResponsesDocument, Diagnostics, error) {
+	// Strip metadata block if present
+	meta, cleanMarkdown := metadata.Extract(markdown)
+	markdown = cleanMarkdown
+
+	info := parseKeyValues(markdown, "RESPONSES_INFO")
+	doc := &models.ResponsesDocument{
+		Metadata: meta,
+		Info: models.ResponsesInfo{
+			IncidentID: info["INCIDENT_ID"],
+			Title:      info["TITLE"],
+		},
+	}
+
+	diags := &diagCollector{section: "RESPONSES"}
+	table := parseMarkedTable(markdown, "RESPONSES", diags)
+	ordinals := make(map[string]int)
+
+	for i, row := range table.rows {
+		line := table.lines[i]
+		date := table.cell(row, ColDate)
+		organization := table.cell(row, ColOrganization)
+		response := table.cell(row, ColResponse)
+
+		if !p.datePatternISO.MatchString(date) {
+			diags.add(SeverityError, CodeInvalidDate, line, table.column(row, ColDate), row.Line,
+				"row dropped: DATE %q is not YYYY-MM-DD", date)
+
+			continue
+		}
+
+		if organization == "" || response == "" {
+			diags.add(SeverityError, CodeMissingKey, line, row.Start(), row.Line,
+				"row dropped: ORGANIZATION and RESPONSE are required")
+
+			continue
+		}
+
+		// Organization names are translated, so the locale-independent types identify a response.
+		orgType := table.cell(row, ColOrganizationType)
+		responseType := table.cell(row, ColResponseType)
+		key := date + "|" + orgType + "|" + responseType
+		ordinals[key]++
+
+		doc.Responses = append(doc.Responses, models.FireResponse{
+			ID:               generateEventID(date, fmt.Sprintf("RESPONSE#%d", ordinals[key]), orgType+"/"+responseType),
+			Date:             date,
+			Organization:     organization,
+			OrganizationType: orgType,
+			ResponseType:     responseType,
+			Description:      response,
+			Sources:          p.eventSources(table.cell(row, ColSource)),
+		})
+	}
+
+	doc.Sources = p.parseSourcesSection(markdown)
+	doc.Notes = p.parseNotes(markdown)
+
+	return doc, diags.list, nil
+}
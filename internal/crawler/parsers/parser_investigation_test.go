@@ -0,0 +1,150 @@
+package parsers
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestParser_ParseInvestigationWithDiagnostics(t *testing.T) {
+	markdown := strings.Join([]string{
+		"<!-- INVESTIGATION_INFO_START -->",                             // 1
+		"| INCIDENT_ID | TEST_FIRE |",                                   // 2
+		"| TITLE | Inquiry |",                                           // 3
+		"<!-- INVESTIGATION_INFO_END -->",                               // 4
+		"<!-- FINDINGS_START -->",                                       // 5
+		"| DATE | CATEGORY | FINDING | STATUS | SOURCES |",              // 6
+		"| --- | --- | --- | --- | --- |",                               // 7
+		"| 2025-01-03 | CAUSE | Netting | CONFIRMED | [A](https://a) |", // 8
+		"| 2025-01-03 | CAUSE | Foam \\| boards | PENDING |",            // 9
+		"| 03/01 | CAUSE | Bad date | PENDING | B |",                    // 10
+		"| 2025-01-04 | SPREAD |  | PENDING | B |",                      // 11
+		"<!-- FINDINGS_END -->",                                         // 12
+	}, "\n")
+
+	doc, diags, err := NewParser().ParseInvestigationWithDiagnostics(markdown)
+	if err != nil {
+		t.Fatalf("ParseInvestigationWithDiagnostics failed: %v", err)
+	}
+
+	if doc.Info.IncidentID != "TEST_FIRE" || doc.Info.Title != "Inquiry" {
+		t.Errorf("Unexpected info: %+v", doc.Info)
+	}
+
+	if len(doc.Findings) != 2 || doc.Findings[1].Finding != "Foam | boards" {
+		t.Fatalf("Unexpected findings: %+v", doc.Findings)
+	}
+
+	// Findings on the same date and category get distinct IDs
+	if doc.Findings[0].ID == doc.Findings[1].ID {
+		t.Errorf("Expected distinct IDs, got %s twice", doc.Findings[0].ID)
+	}
+
+	want := []struct {
+		code string
+		line int
+	}{
+		{CodeColumnCount, 9},
+		{CodeInvalidDate, 10},
+		{CodeMissingKey, 11},
+	}
+
+	if len(diags) != len(want) {
+		t.Fatalf("Expected %d diagnostics, got %d: %v", len(want), len(diags), diags)
+	}
+
+	for i, w := range want {
+		if diags[i].Code != w.code || diags[i].Line != w.line || diags[i].Section != "FINDINGS" {
+			t.Errorf("diagnostic %d = %s, want %s on line %d", i, diags[i], w.code, w.line)
+		}
+	}
+}
+
+func TestParser_ParseResponsesWithDiagnostics(t *testing.T) {
+	table := func(org, response string) string {
+		return strings.Join([]string{
+			"<!-- RESPONSES_INFO_START -->",
+			"| INCIDENT_ID | TEST_FIRE |",
+			"<!-- RESPONSES_INFO_END -->",
+			"<!-- RESPONSES_START -->",
+			"| DATE | ORGANIZATION | ORGANIZATION_TYPE | RESPONSE_TYPE | RESPONSE | SOURCES |",
+			"| --- | --- | --- | --- | --- | --- |",
+			fmt.Sprintf("| 2025-01-02 | %s | GOVERNMENT | RELIEF | %s | S |", org, response),
+			"| 2025-01-02 |  | NGO | RELIEF | No organization | S |",
+			"<!-- RESPONSES_END -->",
+		}, "\n")
+	}
+
+	en, diags, err := NewParser().ParseResponsesWithDiagnostics(table("Government", "Relief fund"))
+	if err != nil {
+		t.Fatalf("ParseResponsesWithDiagnostics failed: %v", err)
+	}
+
+	if en.Info.IncidentID != "TEST_FIRE" || len(en.Responses) != 1 {
+		t.Fatalf("Unexpected document: %+v", en)
+	}
+
+	if len(diags) != 1 || diags[0].Code != CodeMissingKey || diags[0].Line != 8 {
+		t.Errorf("Expected MISSING_KEY on line 8, got %v", diags)
+	}
+
+	// Translated organization names and descriptions keep the response ID
+	zh, _ := NewParser().ParseResponses(table("特區政府", "成立援助基金"))
+	if len(zh.Responses) != 1 || zh.Responses[0].ID != en.Responses[0].ID {
+		t.Errorf("Expected the same ID across locales, got %+v and %+v", zh.Responses, en.Responses)
+	}
+}
+
+func TestParser_ParseResponsesWithDiagnostics_MissingSection(t *testing.T) {
+	_, diags, _ := NewParser().ParseResponsesWithDiagnostics("# No responses")
+	if len(diags) != 1 || diags[0].Code != CodeMissingSection {
+		t.Errorf("Expected MISSING_SECTION, got %v", diags)
+	}
+}
+
+func TestParser_ChineseHeaders(t *testing.T) {
+	findings := strings.Join([]string{
+		"<!-- INVESTIGATION_INFO_START -->",
+		"| INCIDENT_ID | TEST_FIRE |",
+		"<!-- INVESTIGATION_INFO_END -->",
+		"<!-- FINDINGS_START -->",
+		"| 日期 | 類別 | 調查結果 | 狀態 | 來源 |",
+		"| --- | --- | --- | --- | --- |",
+		"| 2025-01-03 | CAUSE | 棚網不合規格 | CONFIRMED | S |",
+		"<!-- FINDINGS_END -->",
+	}, "\n")
+
+	investigation, diags, err := NewParser().ParseInvestigationWithDiagnostics(findings)
+	if err != nil || len(diags) != 0 {
+		t.Fatalf("ParseInvestigationWithDiagnostics() error = %v, diagnostics = %v", err, diags)
+	}
+
+	if len(investigation.Findings) != 1 || investigation.Findings[0].Finding != "棚網不合規格" || investigation.Findings[0].Status != "CONFIRMED" {
+		t.Errorf("Unexpected findings: %+v", investigation.Findings)
+	}
+
+	responses := strings.Join([]string{
+		"<!-- RESPONSES_INFO_START -->",
+		"| INCIDENT_ID | TEST_FIRE |",
+		"<!-- RESPONSES_INFO_END -->",
+		"<!-- RESPONSES_START -->",
+		"| 日期 | 机构 | 机构类型 | 回应类型 | 回应 | 来源 |",
+		"| --- | --- | --- | --- | --- | --- |",
+		"| 2025-01-02 | 特区政府 | GOVERNMENT | RELIEF | 成立援助基金 | S |",
+		"<!-- RESPONSES_END -->",
+	}, "\n")
+
+	doc, diags, err := NewParser().ParseResponsesWithDiagnostics(responses)
+	if err != nil || len(diags) != 0 {
+		t.Fatalf("ParseResponsesWithDiagnostics() error = %v, diagnostics = %v", err, diags)
+	}
+
+	if len(doc.Responses) != 1 {
+		t.Fatalf("Unexpected responses: %+v", doc.Responses)
+	}
+
+	got := doc.Responses[0]
+	if got.Organization != "特区政府" || got.OrganizationType != "GOVERNMENT" || got.ResponseType != "RELIEF" || got.Description != "成立援助基金" {
+		t.Errorf("Unexpected response: %+v", got)
+	}
+}
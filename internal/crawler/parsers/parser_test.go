@@ -150,6 +150,44 @@ func TestParser_ParseDocument_DynamicColumns(t *testing.T) {
 	}
 }
 
+func TestParser_ParseMarkdownTableWithRejects(t *testing.T) {
+	markdown := `<!-- TIMELINE_TABLE_START -->
+| DATE | TIME | EVENT |
+|------|------|-------|
+| 2025-11-26 | 14:00 | Event 1 |
+| 2025-11-26 | 2pm | Typo in time |
+| 2025-11-26 | | Missing time |
+| 2025-11-26 | 15:00 | Flagged by validator |
+<!-- TIMELINE_TABLE_END -->
+`
+	parser := NewParser()
+
+	events, rejects, err := parser.ParseMarkdownTableWithRejects(markdown, map[int]string{7: "date field is empty"})
+	if err != nil {
+		t.Fatalf("ParseMarkdownTableWithRejects failed: %v", err)
+	}
+
+	if len(events) != 1 || events[0].Description != "Event 1" {
+		t.Fatalf("Expected only Event 1, got %+v", events)
+	}
+
+	if len(rejects) != 3 {
+		t.Fatalf("Expected 3 rejects, got %d: %+v", len(rejects), rejects)
+	}
+
+	if rejects[0].Line != 5 || rejects[0].Reason != "invalid time format: 2pm" || rejects[0].Columns[ColTime] != "2pm" {
+		t.Errorf("Unexpected first reject: %+v", rejects[0])
+	}
+
+	if rejects[1].Line != 6 || rejects[1].Reason != ErrInvalidRow.Error() {
+		t.Errorf("Unexpected second reject: %+v", rejects[1])
+	}
+
+	if rejects[2].Line != 7 || rejects[2].Reason != "date field is empty" || rejects[2].Raw != "| 2025-11-26 | 15:00 | Flagged by validator |" {
+		t.Errorf("Unexpected third reject: %+v", rejects[2])
+	}
+}
+
 func TestParser_ParseCategoryMetrics(t *testing.T) {
 	markdown := `
 <!-- CATEGORY_METRICS_START -->
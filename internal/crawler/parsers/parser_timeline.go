@@ -2,10 +2,13 @@
 package parsers
 
 import (
+	"errors"
 	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
 
+	"tpwfc/internal/mdtable"
 	"tpwfc/internal/models"
 	"tpwfc/pkg/metadata"
 )
@@ -41,6 +44,15 @@ func ParseDuration(durationStr string) (models.Duration, error) {
 
 // ParseDocument parses the entire markdown document and returns a TimelineDocument.
 func (p *Parser) ParseDocument(markdown string) (*models.TimelineDocument, error) {
+	doc, _, err := p.ParseDocumentWithDiagnostics(markdown)
+
+	return doc, err
+}
+
+// ParseDocumentWithDiagnostics parses the entire markdown document and also reports the
+// content it had to drop or could only partly understand. The metadata block is written
+// last, so line numbers match the original document.
+func (p *Parser) ParseDocumentWithDiagnostics(markdown string) (*models.TimelineDocument, Diagnostics, error) {
 	// Strip metadata block if present
 	meta, cleanMarkdown := metadata.Extract(markdown)
 	markdown = cleanMarkdown
@@ -59,11 +71,7 @@ func (p *Parser) ParseDocument(markdown string) (*models.TimelineDocument, error
 	doc.Severity = p.parseSection(markdown, p.severityStartPattern, p.severityEndPattern)
 
 	// Parse timeline events
-	events, err := p.ParseMarkdownTable(markdown)
-	if err != nil {
-		return nil, err
-	}
-
+	events, _, diags := p.parseTimelineTable(markdown, nil, documentYear(doc.BasicInfo, meta))
 	doc.Events = events
 
 	// Parse key statistics
@@ -75,7 +83,7 @@ func (p *Parser) ParseDocument(markdown string) (*models.TimelineDocument, error
 	// Parse notes
 	doc.Notes = p.parseNotes(markdown)
 
-	return doc, nil
+	return doc, diags, nil
 }
 
 // parseBasicInfo extracts basic information from the BASIC_INFO section.
@@ -92,10 +100,10 @@ func (p *Parser) parseBasicInfo(markdown string) models.BasicInfo {
 		}
 
 		if inSection && strings.HasPrefix(line, "|") && !strings.Contains(line, "項目") && !strings.Contains(line, "KEY") && !strings.HasPrefix(line, "|---") {
-			cells := strings.Split(line, "|")
-			if len(cells) >= 3 {
-				key := strings.TrimSpace(cells[1])
-				value := strings.TrimSpace(cells[2])
+			row := mdtable.ParseRow(line)
+			if row.Len() >= 2 {
+				key := row.Text(0)
+				value := row.Text(1)
 
 				switch key {
 				case "INCIDENT_ID":
@@ -178,10 +186,10 @@ func (p *Parser) parseKeyStatistics(markdown string) models.KeyStatistics {
 		}
 
 		if inSection && strings.HasPrefix(line, "|") && !strings.Contains(line, "項目") && !strings.Contains(line, "KEY") && !strings.HasPrefix(line, "|---") {
-			cells := strings.Split(line, "|")
-			if len(cells) >= 3 {
-				key := strings.TrimSpace(cells[1])
-				value := strings.TrimSpace(cells[2])
+			row := mdtable.ParseRow(line)
+			if row.Len() >= 2 {
+				key := row.Text(0)
+				value := row.Text(1)
 
 				switch key {
 				case "FINAL_DEATHS":
@@ -260,18 +268,17 @@ func (p *Parser) parseSourcesSection(markdown string) []models.Source {
 		if inSection && strings.HasPrefix(trimmedLine, "|") &&
 			!strings.Contains(line, "SOURCE_NAME") &&
 			!separatorPattern.MatchString(trimmedLine) {
-			cells := strings.Split(line, "|")
 			// Table format: | NAME | TITLE | URL |
-			// After split: ["", NAME, TITLE, URL, ""]
-			if len(cells) >= 4 {
-				url := strings.TrimSpace(cells[3])
+			row := mdtable.ParseRow(line)
+			if row.Len() >= 3 {
+				url := row.Text(2)
 				// Remove angle brackets if present
 				url = strings.TrimPrefix(url, "<")
 				url = strings.TrimSuffix(url, ">")
 
 				source := models.Source{
-					Name:  strings.TrimSpace(cells[1]),
-					Title: strings.TrimSpace(cells[2]),
+					Name:  row.Text(0),
+					Title: row.Text(1),
 					URL:   url,
 				}
 				sources = append(sources, source)
@@ -282,12 +289,58 @@ func (p *Parser) parseSourcesSection(markdown string) []models.Source {
 	return sources
 }
 
+// RejectedRow is a timeline table row that failed to parse or validate.
+type RejectedRow struct {
+	Columns map[string]string `json:"columns,omitempty"`
+	Raw     string            `json:"raw"`
+	Reason  string            `json:"reason"`
+	Line    int               `json:"line"`
+}
+
 // ParseMarkdownTable extracts timeline events from markdown table.
 func (p *Parser) ParseMarkdownTable(markdown string) ([]models.TimelineEvent, error) {
+	events, _, err := p.ParseMarkdownTableWithRejects(markdown, nil)
+
+	return events, err
+}
+
+// ParseMarkdownTableWithDiagnostics extracts timeline events and reports every table row
+// that was dropped or parsed doubtfully.
+func (p *Parser) ParseMarkdownTableWithDiagnostics(markdown string) ([]models.TimelineEvent, Diagnostics, error) {
+	events, _, diags := p.parseTimelineTable(markdown, nil, p.tableYear(markdown))
+
+	return events, diags, nil
+}
+
+// ParseMarkdownTableWithRejects extracts timeline events and also returns the table rows that
+// could not be parsed. invalid maps 1-based line numbers to validation failures; rows on those
+// lines are reported as rejected and left out of the events.
+func (p *Parser) ParseMarkdownTableWithRejects(markdown string, invalid map[int]string) ([]models.TimelineEvent, []RejectedRow, error) {
+	events, rejects, _ := p.parseTimelineTable(markdown, invalid, p.tableYear(markdown))
+
+	return events, rejects, nil
+}
+
+// tableYear returns the documentYear of a document given only its markdown.
+func (p *Parser) tableYear(markdown string) int {
+	meta, clean := metadata.Extract(markdown)
+
+	return documentYear(p.parseBasicInfo(clean), meta)
+}
+
+// parseTimelineTable parses the TIMELINE_TABLE section, returning the events, the rejected
+// rows and the diagnostics. Month-day headings outside the table start in year (see dateResolver).
+func (p *Parser) parseTimelineTable(markdown string, invalid map[int]string, year int) ([]models.TimelineEvent, []RejectedRow, Diagnostics) {
 	var events []models.TimelineEvent
+	var rejects []RejectedRow
 	var currentDate string
-	var inTable bool
+	var inTable, sawTable bool
 	var colMap map[string]int
+	var tableStart, headerWidth int
+
+	diags := &diagCollector{section: "TIMELINE_TABLE"}
+	dates := newDateResolver(year)
+	warnedYear := false
 
 	// Split by lines
 	lines := strings.Split(markdown, "\n")
@@ -297,7 +350,8 @@ func (p *Parser) ParseMarkdownTable(markdown string) ([]models.TimelineEvent, er
 
 		// Check for table start marker
 		if p.tableStartPattern.MatchString(line) {
-			inTable = true
+			inTable, sawTable = true, true
+			tableStart = i + 1
 			colMap = nil // Reset column map for new table
 			continue
 		}
@@ -309,24 +363,15 @@ func (p *Parser) ParseMarkdownTable(markdown string) ([]models.TimelineEvent, er
 		}
 
 		// Skip empty lines and table separators
-		if line == "" || strings.HasPrefix(line, "|-") || strings.HasPrefix(line, "| -") || strings.Contains(line, "|---") {
+		if line == "" || mdtable.ParseRow(line).IsSeparator() {
 			continue
 		}
 
 		// If we found table boundaries, only parse between them
 		if inTable {
 			if strings.HasPrefix(line, "|") {
-				cells := strings.Split(line, "|")
-				// Filter empty cells from split
-				var cleanCells []string
-				cleanCells = append(cleanCells, cells...)
-				// Remove first and last empty elements often caused by "| data |" split
-				if len(cleanCells) > 0 && strings.TrimSpace(cleanCells[0]) == "" {
-					cleanCells = cleanCells[1:]
-				}
-				if len(cleanCells) > 0 && strings.TrimSpace(cleanCells[len(cleanCells)-1]) == "" {
-					cleanCells = cleanCells[:len(cleanCells)-1]
-				}
+				row := mdtable.ParseRow(lines[i])
+				cleanCells := row.Texts()
 
 				// Check if this is a header row
 				isHeader := false
@@ -339,49 +384,148 @@ func (p *Parser) ParseMarkdownTable(markdown string) ([]models.TimelineEvent, er
 				}
 
 				if isHeader {
-					colMap = make(map[string]int)
-					for idx, cell := range cleanCells {
-						colMap[NormalizeHeader(cell)] = idx
-					}
+					colMap = row.Header()
+					headerWidth = row.Len()
+					continue
+				}
+
+				if colMap == nil {
+					diags.add(SeverityError, CodeRowBeforeHeader, i+1, row.Start(), lines[i],
+						"row dropped: it comes before the table header (DATE | TIME | EVENT ...)")
+
 					continue
 				}
 
 				// Only parse if we have a valid column map
-				if colMap != nil {
-					event, err := p.parseTableRow(cleanCells, currentDate, colMap)
-					if err == nil && event != nil {
+				event, err := p.parseTableRow(cleanCells, currentDate, colMap)
+				invalidReason, flagged := invalid[i+1]
+
+				if err == nil && event != nil {
+					// A row that failed validation still dates the rows after it
+					if !flagged {
 						events = append(events, *event)
-						// Update current date if the row had a specific date
-						if event.Date != "" {
-							currentDate = event.Date
-						}
+					}
+
+					// Update current date if the row had a specific date
+					if event.Date != "" {
+						currentDate = event.Date
+						dates.observe(event.Date)
+					} else {
+						diags.add(SeverityWarning, CodeNoDate, i+1, columnOf(row, colMap, ColDate), lines[i],
+							"event has no date: no DATE cell or earlier dated row precedes it")
 					}
 				}
+
+				if err != nil {
+					p.diagnoseRow(diags, err, row, i+1, colMap)
+				} else if widthErr := row.CheckWidth(headerWidth); widthErr != nil {
+					diags.add(SeverityWarning, CodeColumnCount, i+1, row.Start(), lines[i], "%v", widthErr)
+				}
+
+				reasons := make([]string, 0, 2)
+				if err != nil {
+					reasons = append(reasons, err.Error())
+				}
+
+				if flagged {
+					reasons = append(reasons, invalidReason)
+				}
+
+				if len(reasons) > 0 {
+					rejects = append(rejects, RejectedRow{
+						Columns: rowColumns(cleanCells, colMap),
+						Raw:     line,
+						Reason:  strings.Join(reasons, "; "),
+						Line:    i + 1,
+					})
+				}
 			}
 			continue
 		}
 
 		// Legacy parsing mode (when no table markers present or strictly for date headers)
-		// Check for date header (multiple formats)
+		// Check for date header (**11月26日** or ### 11月26日（星期一）, optionally with 2025年)
 		dateMatch := p.datePattern.FindStringSubmatch(line)
-		if len(dateMatch) > 0 {
-			month := dateMatch[1]
-			day := dateMatch[2]
-			currentDate = fmt.Sprintf("2025-%s-%s", padZero(month), padZero(day))
-			continue
+		if dateMatch == nil {
+			dateMatch = p.datePatternAlt.FindStringSubmatch(line)
 		}
 
-		// Check alternative date format (### 11月26日（星期一）)
-		dateMatchAlt := p.datePatternAlt.FindStringSubmatch(line)
-		if len(dateMatchAlt) > 0 {
-			month := dateMatchAlt[1]
-			day := dateMatchAlt[2]
-			currentDate = fmt.Sprintf("2025-%s-%s", padZero(month), padZero(day))
+		if dateMatch != nil {
+			year, _ := strconv.Atoi(dateMatch[1])
+			month, _ := strconv.Atoi(dateMatch[2])
+			day, _ := strconv.Atoi(dateMatch[3])
+
+			if year > 0 {
+				currentDate = dates.resolveYear(year, month, day)
+
+				continue
+			}
+
+			currentDate = dates.resolve(month, day)
+
+			if dates.assumed && !warnedYear {
+				warnedYear = true
+				diags.add(SeverityWarning, CodeAssumedYear, i+1, 0, lines[i],
+					"date heading has no year and neither BASIC_INFO DATE_RANGE nor metadata gives one: assuming %d", fallbackYear)
+			}
+
 			continue
 		}
 	}
 
-	return events, nil
+	switch {
+	case !sawTable:
+		diags.add(SeverityWarning, CodeMissingSection, 0, 0, "",
+			"no <!-- TIMELINE_TABLE_START --> marker: no events were parsed")
+	case inTable:
+		diags.add(SeverityWarning, CodeUnterminatedSection, tableStart, 0, lines[tableStart-1],
+			"<!-- TIMELINE_TABLE_START --> has no matching <!-- TIMELINE_TABLE_END -->")
+	}
+
+	return events, rejects, diags.list
+}
+
+// diagnoseRow records why parseTableRow rejected a row.
+func (p *Parser) diagnoseRow(diags *diagCollector, err error, row mdtable.Row, line int, colMap map[string]int) {
+	timeIdx, hasTime := colMap[ColTime]
+	column := columnOf(row, colMap, ColTime)
+
+	switch {
+	case errors.Is(err, ErrInvalidTimeFormat):
+		diags.add(SeverityError, CodeInvalidTime, line, column, row.Line,
+			"row dropped: %v (expected HH:MM, %s or %s)", err, TimeAllDay, TimeOngoing)
+	case !hasTime:
+		diags.add(SeverityError, CodeMissingTime, line, 0, row.Line, "row dropped: the table header has no TIME column")
+	case timeIdx >= row.Len():
+		diags.add(SeverityError, CodeTooFewCells, line, row.Start(), row.Line,
+			"row dropped: %d cells, but the TIME column is cell %d", row.Len(), timeIdx+1)
+	default:
+		diags.add(SeverityError, CodeMissingTime, line, column, row.Line, "row dropped: the TIME cell is empty")
+	}
+}
+
+// columnOf returns the column where the named cell of a timeline row starts, or 0 when the
+// table or the row has no such column.
+func columnOf(row mdtable.Row, colMap map[string]int, name string) int {
+	idx, ok := colMap[name]
+	if !ok {
+		return 0
+	}
+
+	return row.Column(idx)
+}
+
+// rowColumns maps each header in colMap to its trimmed cell value.
+func rowColumns(cells []string, colMap map[string]int) map[string]string {
+	columns := make(map[string]string, len(colMap))
+
+	for name, idx := range colMap {
+		if idx < len(cells) {
+			columns[name] = strings.TrimSpace(cells[idx])
+		}
+	}
+
+	return columns
 }
 
 // parseTableRow parses a single table row using the column map.
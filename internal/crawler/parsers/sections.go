@@ -0,0 +1,150 @@
+package parsers
+
+import (
+	"regexp"
+	"strings"
+
+	"tpwfc/internal/mdtable"
+	"tpwfc/internal/models"
+)
+
+// sectionPatterns returns the patterns of the <!-- NAME_START --> and <!-- NAME_END --> markers.
+func sectionPatterns(name string) (start, end *regexp.Regexp) {
+	return regexp.MustCompile(`<!--\s*` + name + `_START\s*-->`), regexp.MustCompile(`<!--\s*` + name + `_END\s*-->`)
+}
+
+// parseKeyValues reads the KEY | VALUE rows of section name. The header row is skipped.
+func parseKeyValues(markdown, name string) map[string]string {
+	values := make(map[string]string)
+	start, end := sectionPatterns(name)
+	inSection := false
+
+	for _, line := range strings.Split(markdown, "\n") {
+		if start.MatchString(line) {
+			inSection = true
+
+			continue
+		}
+
+		if end.MatchString(line) {
+			break
+		}
+
+		if !inSection || !strings.HasPrefix(strings.TrimSpace(line), "|") {
+			continue
+		}
+
+		row := mdtable.ParseRow(line)
+		if row.Len() < 2 || row.IsSeparator() || row.Text(0) == "KEY" || row.Text(0) == "項目" {
+			continue
+		}
+
+		values[row.Text(0)] = row.Text(1)
+	}
+
+	return values
+}
+
+// markedTable is the table of a marked section, such as FINDINGS or RESPONSES.
+type markedTable struct {
+	header map[string]int
+	rows   []mdtable.Row
+	// lines holds the document line number of each row.
+	lines []int
+}
+
+// parseMarkedTable reads the table of section name. The header is the first row with a DATE
+// column; rows before it, rows whose cell count differs from it and problems with the section
+// markers are reported to diags.
+func parseMarkedTable(markdown, name string, diags *diagCollector) markedTable {
+	var table markedTable
+
+	start, end := sectionPatterns(name)
+	lines := strings.Split(markdown, "\n")
+	startLine := 0
+
+	for i, line := range lines {
+		if start.MatchString(line) {
+			startLine = i + 1
+
+			continue
+		}
+
+		if end.MatchString(line) {
+			return table
+		}
+
+		if startLine == 0 || !strings.HasPrefix(strings.TrimSpace(line), "|") {
+			continue
+		}
+
+		row := mdtable.ParseRow(line)
+		if row.IsSeparator() {
+			continue
+		}
+
+		if table.header == nil {
+			if header := row.Header(); hasColumn(header, ColDate) {
+				table.header = header
+
+				continue
+			}
+
+			diags.add(SeverityError, CodeRowBeforeHeader, i+1, row.Start(), line,
+				"row dropped: it comes before the table header (DATE | ...)")
+
+			continue
+		}
+
+		if err := row.CheckWidth(len(table.header)); err != nil {
+			diags.add(SeverityWarning, CodeColumnCount, i+1, row.Start(), line, "%v", err)
+		}
+
+		table.rows = append(table.rows, row)
+		table.lines = append(table.lines, i+1)
+	}
+
+	if startLine == 0 {
+		diags.add(SeverityWarning, CodeMissingSection, 0, 0, "", "no <!-- %s_START --> marker: no rows were parsed", name)
+	} else {
+		diags.add(SeverityWarning, CodeUnterminatedSection, startLine, 0, lines[startLine-1],
+			"<!-- %s_START --> has no matching <!-- %s_END -->", name, name)
+	}
+
+	return table
+}
+
+// cell returns the trimmed cell of row under the named column, or "" when there is none.
+func (t markedTable) cell(row mdtable.Row, name string) string {
+	idx, ok := t.header[name]
+	if !ok {
+		return ""
+	}
+
+	return row.Text(idx)
+}
+
+// column returns the column where the named cell of row starts, or 0 when there is none.
+func (t markedTable) column(row mdtable.Row, name string) int {
+	return columnOf(row, t.header, name)
+}
+
+func hasColumn(header map[string]int, name string) bool {
+	_, ok := header[name]
+
+	return ok
+}
+
+// eventSources parses a SOURCES cell into model sources.
+func (p *Parser) eventSources(text string) []models.EventSource {
+	var sources []models.EventSource
+
+	for _, s := range p.parseSources(text) {
+		sources = append(sources, models.EventSource{
+			Name: s.Name,
+			URL:  s.URL,
+		})
+	}
+
+	return sources
+}
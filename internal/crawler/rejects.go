@@ -0,0 +1,51 @@
+package crawler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"tpwfc/internal/crawler/parsers"
+)
+
+// RejectsPath returns the rejects file written next to an output file: timeline.json -> timeline.rejects.jsonl.
+func RejectsPath(outputPath string) string {
+	return strings.TrimSuffix(outputPath, filepath.Ext(outputPath)) + ".rejects.jsonl"
+}
+
+// WriteRejects writes one JSON line per rejected row to path.
+// With no rows, a rejects file left by an earlier run is removed so it never looks current.
+func WriteRejects(path string, rows []parsers.RejectedRow) error {
+	if len(rows) == 0 {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to remove stale rejects file: %w", err)
+		}
+
+		return nil
+	}
+
+	var buf bytes.Buffer
+
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+
+	for _, row := range rows {
+		if err := enc.Encode(row); err != nil {
+			return fmt.Errorf("failed to marshal rejected row: %w", err)
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create rejects directory: %w", err)
+	}
+
+	if err := WriteFileAtomic(path, buf.Bytes()); err != nil {
+		return fmt.Errorf("failed to write rejects file: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,58 @@
+package crawler
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"tpwfc/internal/crawler/parsers"
+)
+
+func TestRejectsPath(t *testing.T) {
+	if got := RejectsPath("data/fire/FIRE001/zh-hk/timeline.json"); got != "data/fire/FIRE001/zh-hk/timeline.rejects.jsonl" {
+		t.Errorf("RejectsPath() = %q", got)
+	}
+}
+
+func TestWriteRejects(t *testing.T) {
+	path := RejectsPath(filepath.Join(t.TempDir(), "FIRE001", "timeline.json"))
+	rows := []parsers.RejectedRow{
+		{Line: 5, Raw: "| 2025-11-26 | 2pm | 事件 |", Reason: "invalid time format: 2pm", Columns: map[string]string{"TIME": "2pm"}},
+		{Line: 9, Raw: "| 2025-11-26 | | x |", Reason: "invalid row"},
+	}
+
+	if err := WriteRejects(path, rows); err != nil {
+		t.Fatalf("WriteRejects failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 lines, got %d", len(lines))
+	}
+
+	var first parsers.RejectedRow
+	if err := json.Unmarshal([]byte(lines[0]), &first); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	if first.Line != 5 || first.Columns["TIME"] != "2pm" || first.Raw != rows[0].Raw {
+		t.Errorf("Unexpected row: %+v", first)
+	}
+
+	// A clean run removes the stale file
+	if err := WriteRejects(path, nil); err != nil {
+		t.Fatalf("WriteRejects failed: %v", err)
+	}
+
+	if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected rejects file to be removed, got %v", err)
+	}
+}
@@ -0,0 +1,150 @@
+package crawler
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"tpwfc/internal/crawler/parsers"
+)
+
+// ReportLatestFile is the name of the copy of the most recent report in the report directory.
+const ReportLatestFile = "latest.json"
+
+// Source report statuses.
+const (
+	ReportStatusSaved   = "saved"
+	ReportStatusSkipped = "skipped"
+	ReportStatusFailed  = "failed"
+)
+
+// CrawlReport is the machine-readable record of one crawler run.
+type CrawlReport struct {
+	StartedAt  time.Time      `json:"startedAt"`
+	FinishedAt time.Time      `json:"finishedAt"`
+	ConfigPath string         `json:"configPath,omitempty"`
+	Sources    []SourceReport `json:"sources"`
+	Totals     ReportTotals   `json:"totals"`
+	DurationMs int64          `json:"durationMs"`
+	Workers    int            `json:"workers"`
+}
+
+// SourceReport records how one source was fetched, checked, parsed and saved.
+type SourceReport struct {
+	Validation    *ValidationSummary  `json:"validation,omitempty"`
+	Verification  *Verification       `json:"verification,omitempty"`
+	Name          string              `json:"name"`
+	FireID        string              `json:"fireId"`
+	Language      string              `json:"language"`
+	FileType      string              `json:"fileType,omitempty"`
+	Status        string              `json:"status"`
+	Error         string              `json:"error,omitempty"`
+	FetchedFrom   string              `json:"fetchedFrom,omitempty"`
+	FetchedKind   string              `json:"fetchedKind,omitempty"`
+	OutputPath    string              `json:"outputPath,omitempty"`
+	SnapshotHash  string              `json:"snapshotHash,omitempty"`
+	RejectsPath   string              `json:"rejectsPath,omitempty"`
+	Commit        string              `json:"commit,omitempty"`
+	Attempts      []AttempResult      `json:"attempts"`
+	Diagnostics   parsers.Diagnostics `json:"diagnostics,omitempty"`
+	BytesFetched  int                 `json:"bytesFetched"`
+	EventsParsed  int                 `json:"eventsParsed"`
+	RowsRejected  int                 `json:"rowsRejected"`
+	FromCache     bool                `json:"fromCache"`
+	UsedLocalFile bool                `json:"usedLocalFile"`
+}
+
+// ValidationSummary holds the markdown validation counts for a source.
+type ValidationSummary struct {
+	TotalRows   int  `json:"totalRows"`
+	ValidRows   int  `json:"validRows"`
+	InvalidRows int  `json:"invalidRows"`
+	Errors      int  `json:"errors"`
+	Warnings    int  `json:"warnings"`
+	Valid       bool `json:"valid"`
+}
+
+// ReportTotals aggregates the per-source results.
+type ReportTotals struct {
+	Sources        int `json:"sources"`
+	Saved          int `json:"saved"`
+	Skipped        int `json:"skipped"`
+	Failed         int `json:"failed"`
+	Attempts       int `json:"attempts"`
+	FailedAttempts int `json:"failedAttempts"`
+	Events         int `json:"events"`
+	RowsRejected   int `json:"rowsRejected"`
+	BytesFetched   int `json:"bytesFetched"`
+}
+
+// Finish stamps the end time and computes totals; status values are counted as saved, skipped or failed.
+func (r *CrawlReport) Finish(savedStatus, skippedStatus string) {
+	r.FinishedAt = time.Now().UTC()
+	r.DurationMs = r.FinishedAt.Sub(r.StartedAt).Milliseconds()
+	r.Totals = ReportTotals{Sources: len(r.Sources)}
+
+	for i := range r.Sources {
+		src := &r.Sources[i]
+
+		switch src.Status {
+		case savedStatus:
+			r.Totals.Saved++
+		case skippedStatus:
+			r.Totals.Skipped++
+		default:
+			r.Totals.Failed++
+		}
+
+		r.Totals.Events += src.EventsParsed
+		r.Totals.RowsRejected += src.RowsRejected
+		r.Totals.BytesFetched += src.BytesFetched
+		r.Totals.Attempts += len(src.Attempts)
+
+		for _, a := range src.Attempts {
+			if !a.Success {
+				r.Totals.FailedAttempts++
+			}
+		}
+	}
+}
+
+// Write saves the report as crawl-{timestamp}.json in dir and refreshes latest.json.
+// It returns the path of the timestamped report.
+func (r *CrawlReport) Write(dir string) (string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create report directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal crawl report: %w", err)
+	}
+
+	path := filepath.Join(dir, "crawl-"+r.StartedAt.UTC().Format("20060102T150405Z")+".json")
+	if err := WriteFileAtomic(path, data); err != nil {
+		return "", fmt.Errorf("failed to write crawl report: %w", err)
+	}
+
+	if err := WriteFileAtomic(filepath.Join(dir, ReportLatestFile), data); err != nil {
+		return path, fmt.Errorf("failed to update latest crawl report: %w", err)
+	}
+
+	return path, nil
+}
+
+// LoadCrawlReport reads a report written by Write.
+func LoadCrawlReport(path string) (*CrawlReport, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read crawl report: %w", err)
+	}
+
+	var report CrawlReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("failed to parse crawl report: %w", err)
+	}
+
+	return &report, nil
+}
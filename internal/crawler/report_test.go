@@ -0,0 +1,53 @@
+package crawler
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCrawlReport_WriteAndLoad(t *testing.T) {
+	report := &CrawlReport{
+		StartedAt: time.Now().Add(-time.Second),
+		Sources: []SourceReport{
+			{
+				Name:         "A",
+				Status:       ReportStatusSaved,
+				BytesFetched: 100,
+				EventsParsed: 3,
+				RowsRejected: 2,
+				Attempts: []AttempResult{
+					{URL: "https://example.com/a.md", Attempt: 1, StatusCode: 503},
+					{URL: "https://example.com/a.md", Attempt: 2, StatusCode: 200, Success: true},
+				},
+			},
+			{Name: "B", Status: "skipped", Error: "metadata verification failed"},
+			{Name: "C", Status: "failed", Error: "all fetch attempts failed"},
+		},
+	}
+
+	report.Finish(ReportStatusSaved, ReportStatusSkipped)
+
+	want := ReportTotals{Sources: 3, Saved: 1, Skipped: 1, Failed: 1, Attempts: 2, FailedAttempts: 1, Events: 3, RowsRejected: 2, BytesFetched: 100}
+	if report.Totals != want {
+		t.Errorf("Totals = %+v, want %+v", report.Totals, want)
+	}
+
+	dir := t.TempDir()
+
+	path, err := report.Write(dir)
+	if err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+
+	for _, p := range []string{path, filepath.Join(dir, ReportLatestFile)} {
+		loaded, err := LoadCrawlReport(p)
+		if err != nil {
+			t.Fatalf("LoadCrawlReport(%s) failed: %v", p, err)
+		}
+
+		if len(loaded.Sources) != 3 || len(loaded.Sources[0].Attempts) != 2 {
+			t.Errorf("loaded report from %s does not round-trip: %+v", p, loaded)
+		}
+	}
+}
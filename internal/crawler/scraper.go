@@ -1,90 +1,253 @@
 package crawler
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
+	"strings"
+	"sync"
 	"time"
 
 	"tpwfc/internal/config"
+	"tpwfc/pkg/metadata"
+	"tpwfc/pkg/utils"
 )
 
-// ErrUnexpectedStatusCode indicates an HTTP response with unexpected status.
-var ErrUnexpectedStatusCode = errors.New("unexpected status code")
+// Scraper errors.
+var (
+	// ErrUnexpectedStatusCode indicates an HTTP response with unexpected status.
+	ErrUnexpectedStatusCode = errors.New("unexpected status code")
+	// ErrResponseTooLarge indicates a response body larger than advanced.buffer_size_kb.
+	ErrResponseTooLarge = errors.New("response exceeds buffer size limit")
+	// ErrIncompleteDocument indicates a response body that was cut off before its end.
+	ErrIncompleteDocument = errors.New("incomplete document")
+)
 
 // Scraper handles web scraping operations with config-driven retry logic.
 type Scraper struct {
 	client       *http.Client
 	retryPolicy  *config.RetryPolicy
+	cache        *ResponseCache
 	bufferSizeKb int
+	policy       *NetworkPolicy
+
+	mu           sync.Mutex
+	proxyClients map[string]*http.Client
+}
+
+// RequestOptions holds the per-source settings applied to every request for that source.
+type RequestOptions struct {
+	// Header holds the source's custom headers, merged over the scraper's defaults.
+	Header http.Header
+	// Proxy routes the requests through the given proxy; nil uses the environment.
+	Proxy *url.URL
+	// Token is sent as a bearer token, but only to TokenHosts.
+	Token string
+	// TokenHosts lists the hosts (host or host:port) that may receive Token.
+	TokenHosts []string
+}
+
+// RequestOptionsFor builds the request options of a source: its custom headers,
+// a bearer token from token_env or token_file, and its proxy. The token is only sent
+// to the hosts in token_hosts, or by default to the host of the primary URL, so
+// backup mirrors on other hosts never see it.
+func RequestOptionsFor(src *config.SourceConfig) (*RequestOptions, error) {
+	header := http.Header{}
+	for key, value := range src.Headers {
+		header.Set(key, value)
+	}
+
+	token, err := src.Token()
+	if err != nil {
+		return nil, fmt.Errorf("source %s: %w", src.Name, err)
+	}
+
+	proxy, err := src.ProxyURL()
+	if err != nil {
+		return nil, fmt.Errorf("source %s: %w", src.Name, err)
+	}
+
+	opts := &RequestOptions{Header: header, Proxy: proxy, Token: token, TokenHosts: src.TokenHosts}
+
+	if token != "" && len(opts.TokenHosts) == 0 {
+		if primary, parseErr := url.Parse(src.URL); parseErr == nil && primary.Host != "" {
+			opts.TokenHosts = []string{primary.Host}
+		}
+	}
+
+	return opts, nil
+}
+
+// authorizes reports whether the token may be sent to target.
+func (o *RequestOptions) authorizes(target *url.URL) bool {
+	if o.Token == "" {
+		return false
+	}
+
+	for _, host := range o.TokenHosts {
+		// An entry without a port matches the host on any port
+		if strings.EqualFold(host, target.Host) || !strings.Contains(host, ":") && strings.EqualFold(host, target.Hostname()) {
+			return true
+		}
+	}
+
+	return false
 }
 
 // NewScraper creates a new scraper instance with default config.
 func NewScraper() *Scraper {
-	return &Scraper{
-		client: &http.Client{
-			Timeout: 30 * time.Second,
-		},
-		retryPolicy: &config.RetryPolicy{
-			MaxAttempts:       3,
-			InitialDelayMs:    500,
-			MaxDelayMs:        30000,
-			BackoffMultiplier: 2.0,
-			TimeoutSec:        30,
-		},
+	retryPolicy := config.DefaultRetryPolicy()
+
+	s := &Scraper{
+		retryPolicy:  &retryPolicy,
 		bufferSizeKb: 1024,
 	}
+	s.client = &http.Client{
+		Transport:     s.decoding(utils.SharedTransport()),
+		CheckRedirect: redirectPolicy(nil),
+		Timeout:       retryPolicy.GetTimeout(),
+	}
+
+	return s
 }
 
 // NewScraperWithConfig creates a new scraper with custom retry policy.
 func NewScraperWithConfig(retryPolicy *config.RetryPolicy, bufferSizeKb int) *Scraper {
 	timeout := time.Duration(retryPolicy.TimeoutSec) * time.Second
 
-	return &Scraper{
-		client: &http.Client{
-			Timeout: timeout,
-		},
+	s := &Scraper{
 		retryPolicy:  retryPolicy,
 		bufferSizeKb: bufferSizeKb,
 	}
+	s.client = &http.Client{
+		Transport:     s.decoding(utils.SharedTransport()),
+		CheckRedirect: redirectPolicy(nil),
+		Timeout:       timeout,
+	}
+
+	return s
+}
+
+// decoding wraps base with gzip and brotli decoding, capping the decoded body at buffer_size_kb.
+func (s *Scraper) decoding(base http.RoundTripper) http.RoundTripper {
+	return utils.NewDecodingTransport(base, s.limit())
+}
+
+// limit returns the largest body accepted, in bytes.
+func (s *Scraper) limit() int64 {
+	return int64(s.bufferSizeKb) * 1024
+}
+
+// SetNetworkPolicy restricts every later request to the hosts and addresses allowed by policy.
+func (s *Scraper) SetNetworkPolicy(policy *NetworkPolicy) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.policy = policy
+	s.client = &http.Client{
+		Transport:     s.decoding(policy.transport(nil)),
+		CheckRedirect: redirectPolicy(policy),
+		Timeout:       s.client.Timeout,
+	}
+	s.proxyClients = nil
+}
+
+// SetCache enables conditional requests backed by the given response cache.
+func (s *Scraper) SetCache(cache *ResponseCache) {
+	s.cache = cache
 }
 
 // ScrapeWithMetrics returns (content, statusCode, duration, error).
+// When a cache is set, a 304 response returns the cached body with http.StatusNotModified.
 func (s *Scraper) ScrapeWithMetrics(url string) (string, int, time.Duration, error) {
+	return s.ScrapeWithMetricsContext(context.Background(), url)
+}
+
+// ScrapeWithMetricsContext is ScrapeWithMetrics with cancellation of in-flight requests and backoff sleeps.
+func (s *Scraper) ScrapeWithMetricsContext(ctx context.Context, url string) (string, int, time.Duration, error) {
+	return s.ScrapeWithOptionsContext(ctx, url, nil)
+}
+
+// ScrapeWithOptionsContext is ScrapeWithMetricsContext with per-source headers and proxy.
+// Nil options send the default headers through the scraper's own client.
+func (s *Scraper) ScrapeWithOptionsContext(ctx context.Context, url string, opts *RequestOptions) (string, int, time.Duration, error) {
 	var lastErr error
 
+	header := utils.NewHTTPHelper().BuildHeaders(nil)
+	client := s.client
+
+	if opts != nil {
+		for key, values := range opts.Header {
+			header[key] = values
+		}
+
+		if opts.Proxy != nil {
+			client = s.proxyClient(opts.Proxy)
+		}
+	}
+
+	var cached *CacheEntry
+	if s.cache != nil {
+		if entry, ok := s.cache.Get(url); ok {
+			cached = entry
+		}
+	}
+
 	var lastStatusCode int
 
 	totalDuration := time.Duration(0)
 
 	for attempt := 1; attempt <= s.retryPolicy.MaxAttempts; attempt++ {
+		if err := ctx.Err(); err != nil {
+			return "", lastStatusCode, totalDuration, fmt.Errorf("scrape cancelled: %w", err)
+		}
+
 		startTime := time.Now()
 
-		req, err := http.NewRequest(http.MethodGet, url, http.NoBody)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
 		if err != nil {
 			lastErr = fmt.Errorf("failed to create request: %w", err)
 
 			continue
 		}
 
-		// Set user agent to avoid being blocked
-		req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/91.0.4472.124 Safari/537.36")
-		req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8")
+		req.Header = header.Clone()
+
+		if opts != nil && opts.authorizes(req.URL) {
+			req.Header.Set("Authorization", "Bearer "+opts.Token)
+		}
+
+		// Ask the server to skip the body if our cached copy is still current
+		if cached != nil {
+			if cached.ETag != "" {
+				req.Header.Set("If-None-Match", cached.ETag)
+			}
+
+			if cached.LastModified != "" {
+				req.Header.Set("If-Modified-Since", cached.LastModified)
+			}
+		}
 
-		resp, err := s.client.Do(req)
+		resp, err := client.Do(req)
 		duration := time.Since(startTime)
 		totalDuration += duration
 
 		if err != nil {
 			lastErr = fmt.Errorf("request failed (attempt %d/%d): %w", attempt, s.retryPolicy.MaxAttempts, err)
 
+			// The policy will refuse the same request again
+			if isPolicyError(err) {
+				return "", lastStatusCode, totalDuration, lastErr
+			}
+
 			// Calculate backoff delay
 			if attempt < s.retryPolicy.MaxAttempts {
-				delay := s.retryPolicy.GetRetryDelay(attempt)
-				if delay > 0 {
-					time.Sleep(delay)
+				if sleepErr := utils.SleepContext(ctx, s.retryPolicy.BackoffDelay(attempt, 0)); sleepErr != nil {
+					return "", lastStatusCode, totalDuration, errors.Join(lastErr, sleepErr)
 				}
 			}
 
@@ -98,41 +261,183 @@ func (s *Scraper) ScrapeWithMetrics(url string) (string, int, time.Duration, err
 		}()
 		lastStatusCode = resp.StatusCode
 
+		if resp.StatusCode == http.StatusNotModified && cached != nil {
+			return cached.Body, resp.StatusCode, totalDuration, nil
+		}
+
 		if resp.StatusCode != http.StatusOK {
 			lastErr = fmt.Errorf("%w: %d", ErrUnexpectedStatusCode, resp.StatusCode)
 
 			// Only retry on specific status codes
-			if attempt < s.retryPolicy.MaxAttempts && isRetryableStatus(resp.StatusCode) {
-				delay := s.retryPolicy.GetRetryDelay(attempt)
-				if delay > 0 {
-					time.Sleep(delay)
+			if attempt < s.retryPolicy.MaxAttempts && utils.IsRetryableStatus(resp.StatusCode) {
+				// Honour the server's throttling hint over our own schedule
+				retryAfter, _ := utils.ParseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+
+				if sleepErr := utils.SleepContext(ctx, s.retryPolicy.BackoffDelay(attempt, retryAfter)); sleepErr != nil {
+					return "", lastStatusCode, totalDuration, errors.Join(lastErr, sleepErr)
 				}
 			}
 
 			continue
 		}
 
-		// Read with buffer limit
-		// bufferSizeKb is in KB, convert to bytes
-		limit := int64(s.bufferSizeKb) * 1024
-		reader := io.LimitReader(resp.Body, limit)
+		// Read with buffer limit; compressed bodies are capped again after decoding
+		limit := s.limit()
+		if resp.ContentLength > limit {
+			return "", resp.StatusCode, totalDuration,
+				fmt.Errorf("%w: Content-Length %d > %d bytes", ErrResponseTooLarge, resp.ContentLength, limit)
+		}
+
+		// Read one byte past the limit so an oversized body is detected, not silently cut
+		reader := io.LimitReader(resp.Body, limit+1)
 
 		body, err := io.ReadAll(reader)
+		if errors.Is(err, utils.ErrDecodedTooLarge) {
+			return "", resp.StatusCode, totalDuration, fmt.Errorf("%w: %w", ErrResponseTooLarge, err)
+		}
+
 		if err != nil {
 			lastErr = fmt.Errorf("failed to read response body: %w", err)
 
+			if sleepErr := s.retryDelay(ctx, attempt); sleepErr != nil {
+				return "", resp.StatusCode, totalDuration, errors.Join(lastErr, sleepErr)
+			}
+
+			continue
+		}
+
+		if int64(len(body)) > limit {
+			return "", resp.StatusCode, totalDuration,
+				fmt.Errorf("%w: body > %d bytes", ErrResponseTooLarge, limit)
+		}
+
+		if err := checkComplete(body, resp.ContentLength); err != nil {
+			lastErr = err
+
+			if sleepErr := s.retryDelay(ctx, attempt); sleepErr != nil {
+				return "", resp.StatusCode, totalDuration, errors.Join(lastErr, sleepErr)
+			}
+
 			continue
 		}
 
+		s.storeInCache(url, resp.Header, body)
+
 		return string(body), resp.StatusCode, totalDuration, nil
 	}
 
 	return "", lastStatusCode, totalDuration, lastErr
 }
 
+// redirectPolicy returns a CheckRedirect function that drops the Authorization header when a
+// redirect leaves the host of the original request, then enforces policy, or Go's default
+// limit of 10 redirects when policy is nil.
+func redirectPolicy(policy *NetworkPolicy) func(*http.Request, []*http.Request) error {
+	return func(req *http.Request, via []*http.Request) error {
+		if !strings.EqualFold(req.URL.Host, via[0].URL.Host) {
+			req.Header.Del("Authorization")
+		}
+
+		if policy != nil {
+			return policy.checkRedirect(req, via)
+		}
+
+		if len(via) >= 10 {
+			return fmt.Errorf("%w: stopped after 10", ErrTooManyRedirects)
+		}
+
+		return nil
+	}
+}
+
+// proxyClient returns a client that routes through proxy, creating it on first use
+// so connections to the same proxy are pooled across sources.
+func (s *Scraper) proxyClient(proxy *url.URL) *http.Client {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := proxy.String()
+	if client, ok := s.proxyClients[key]; ok {
+		return client
+	}
+
+	opts := utils.DefaultTransportOptions()
+	opts.Proxy = http.ProxyURL(proxy)
+
+	client := &http.Client{
+		Transport:     s.decoding(utils.NewTransport(opts)),
+		CheckRedirect: redirectPolicy(s.policy),
+		Timeout:       s.client.Timeout,
+	}
+
+	if s.policy != nil {
+		client.Transport = s.decoding(s.policy.transport(proxy))
+	}
+
+	if s.proxyClients == nil {
+		s.proxyClients = make(map[string]*http.Client)
+	}
+
+	s.proxyClients[key] = client
+
+	return client
+}
+
+// retryDelay waits out the backoff before the attempt after attempt, if there is one.
+func (s *Scraper) retryDelay(ctx context.Context, attempt int) error {
+	if attempt >= s.retryPolicy.MaxAttempts {
+		return nil
+	}
+
+	return utils.SleepContext(ctx, s.retryPolicy.BackoffDelay(attempt, 0))
+}
+
+// checkComplete rejects a body shorter than its declared Content-Length or one that
+// metadata.CheckComplete finds cut off: ending mid-comment or inside a section, or with an
+// unclosed METADATA block. Hash mismatches are left to VerifyDocument.
+func checkComplete(body []byte, contentLength int64) error {
+	if contentLength >= 0 && int64(len(body)) < contentLength {
+		return fmt.Errorf("%w: got %d of %d bytes", ErrIncompleteDocument, len(body), contentLength)
+	}
+
+	if err := metadata.CheckComplete(string(body)); err != nil {
+		return fmt.Errorf("%w: %w", ErrIncompleteDocument, err)
+	}
+
+	return nil
+}
+
+// storeInCache saves a successful response if the server sent validators for it.
+func (s *Scraper) storeInCache(url string, header http.Header, body []byte) {
+	if s.cache == nil {
+		return
+	}
+
+	etag := header.Get("ETag")
+	lastModified := header.Get("Last-Modified")
+
+	if etag == "" && lastModified == "" {
+		return
+	}
+
+	// A failed cache write only costs us a full download next run
+	_ = s.cache.Put(&CacheEntry{
+		URL:          url,
+		ETag:         etag,
+		LastModified: lastModified,
+		Body:         string(body),
+		FetchedAt:    time.Now(),
+	})
+}
+
 // Scrape fetches and returns content from the given URL (legacy method).
 func (s *Scraper) Scrape(url string) (string, error) {
-	content, _, _, err := s.ScrapeWithMetrics(url)
+	return s.ScrapeContext(context.Background(), url)
+}
+
+// ScrapeContext fetches the content from the given URL, giving up once ctx is done.
+func (s *Scraper) ScrapeContext(ctx context.Context, url string) (string, error) {
+	content, _, _, err := s.ScrapeWithMetricsContext(ctx, url)
 
 	return content, err
 }
@@ -165,20 +470,3 @@ func (s *Scraper) ReadLocalFileWithMetrics(filePath string) (string, int64, time
 
 	return string(content), fileInfo.Size(), duration, nil
 }
-
-// isRetryableStatus determines if we should retry based on HTTP status code.
-func isRetryableStatus(statusCode int) bool {
-	// Retry on temporary failures
-	switch statusCode {
-	case http.StatusServiceUnavailable: // 503
-		return true
-	case http.StatusGatewayTimeout: // 504
-		return true
-	case http.StatusTooManyRequests: // 429
-		return true
-	case http.StatusRequestTimeout: // 408
-		return true
-	}
-
-	return false
-}
@@ -0,0 +1,314 @@
+package crawler
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"tpwfc/internal/config"
+	"tpwfc/pkg/metadata"
+)
+
+func TestScraper_ScrapeWithMetricsContext_CancelsBackoff(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	// A long backoff that the context must cut short
+	scraper := NewScraperWithConfig(&config.RetryPolicy{
+		MaxAttempts:       3,
+		InitialDelayMs:    60000,
+		MaxDelayMs:        60000,
+		BackoffMultiplier: 1,
+		TimeoutSec:        5,
+	}, 64)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+
+	_, _, _, err := scraper.ScrapeWithMetricsContext(ctx, server.URL)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+	}
+
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("scrape did not stop promptly after cancellation: %v", elapsed)
+	}
+}
+
+func TestScraper_ScrapeWithMetrics_ResponseTooLarge(t *testing.T) {
+	body := strings.Repeat("x", 2*1024)
+
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+	}{
+		{"content-length", func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(body))
+		}},
+		{"chunked", func(w http.ResponseWriter, _ *http.Request) {
+			// Flushing before writing the body forces chunked encoding with no Content-Length
+			w.(http.Flusher).Flush()
+			_, _ = w.Write([]byte(body))
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(tt.handler)
+			defer server.Close()
+
+			scraper := NewScraperWithConfig(&config.RetryPolicy{MaxAttempts: 1, TimeoutSec: 5}, 1)
+
+			_, _, _, err := scraper.ScrapeWithMetrics(server.URL)
+			if !errors.Is(err, ErrResponseTooLarge) {
+				t.Errorf("expected ErrResponseTooLarge, got %v", err)
+			}
+		})
+	}
+}
+
+func TestScraper_ScrapeWithMetrics_UnterminatedMetadata(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("# Timeline\n\n<!-- METADATA_START\nVALIDATION: TRUE\nHASH: abc"))
+	}))
+	defer server.Close()
+
+	scraper := NewScraperWithConfig(&config.RetryPolicy{MaxAttempts: 1, TimeoutSec: 5}, 64)
+
+	_, _, _, err := scraper.ScrapeWithMetrics(server.URL)
+	if !errors.Is(err, ErrIncompleteDocument) {
+		t.Errorf("expected ErrIncompleteDocument, got %v", err)
+	}
+}
+
+func TestScraper_ScrapeWithMetrics_IncompleteRetriesWithBackoff(t *testing.T) {
+	body := "<!-- TIMELINE_TABLE_START -->\n| DATE | TIME |\n<!-- TIMELINE_TABLE_END -->"
+	signed := metadata.Sign(body, true, nil)
+
+	var times []time.Time
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		times = append(times, time.Now())
+
+		// Flushing first drops the Content-Length, so only the content shows the cut
+		w.(http.Flusher).Flush()
+
+		if len(times) < 3 {
+			_, _ = w.Write([]byte(signed[:strings.Index(signed, "<!-- TIMELINE_TABLE_END")]))
+
+			return
+		}
+
+		_, _ = w.Write([]byte(signed))
+	}))
+	defer server.Close()
+
+	policy := &config.RetryPolicy{MaxAttempts: 3, InitialDelayMs: 100, MaxDelayMs: 1000, BackoffMultiplier: 1, TimeoutSec: 5, Jitter: config.JitterNone}
+	scraper := NewScraperWithConfig(policy, 64)
+
+	got, _, _, err := scraper.ScrapeWithMetrics(server.URL)
+	if err != nil || got != signed {
+		t.Fatalf("ScrapeWithMetrics() = %q, %v, want the complete document", got, err)
+	}
+
+	if len(times) != 3 {
+		t.Fatalf("server was hit %d times, want 3", len(times))
+	}
+
+	// The policy's delay before the third attempt is InitialDelayMs
+	if gap := times[2].Sub(times[1]); gap < 100*time.Millisecond {
+		t.Errorf("retried an incomplete document after %v, want the 100ms backoff", gap)
+	}
+}
+
+func TestScraper_ScrapeWithOptionsContext_SourceHeaders(t *testing.T) {
+	var got http.Header
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+		_, _ = w.Write([]byte("# Timeline\n"))
+	}))
+	defer server.Close()
+
+	t.Setenv("TPWFC_TEST_TOKEN", "secret")
+
+	opts, err := RequestOptionsFor(&config.SourceConfig{
+		Name:     "staging",
+		URL:      server.URL,
+		Headers:  map[string]string{"X-Branch": "staging", "Accept": "text/markdown"},
+		TokenEnv: "TPWFC_TEST_TOKEN",
+	})
+	if err != nil {
+		t.Fatalf("RequestOptionsFor() error = %v", err)
+	}
+
+	scraper := NewScraperWithConfig(&config.RetryPolicy{MaxAttempts: 1, BackoffMultiplier: 1, TimeoutSec: 5}, 64)
+
+	if _, _, _, err := scraper.ScrapeWithOptionsContext(context.Background(), server.URL, opts); err != nil {
+		t.Fatalf("ScrapeWithOptionsContext() error = %v", err)
+	}
+
+	want := map[string]string{
+		"Authorization": "Bearer secret",
+		"X-Branch":      "staging",
+		"Accept":        "text/markdown",
+		"User-Agent":    "TPWFC-Worker/1.0",
+	}
+	for name, value := range want {
+		if got.Get(name) != value {
+			t.Errorf("header %s = %q, want %q", name, got.Get(name), value)
+		}
+	}
+}
+
+func TestScraper_ScrapeWithOptionsContext_TokenOnlyForPrimaryHost(t *testing.T) {
+	var mirrorAuth, redirectAuth []string
+
+	mirror := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/redirected" {
+			redirectAuth = append(redirectAuth, r.Header.Get("Authorization"))
+		} else {
+			mirrorAuth = append(mirrorAuth, r.Header.Get("Authorization"))
+		}
+
+		_, _ = w.Write([]byte("# Timeline\n"))
+	}))
+	defer mirror.Close()
+
+	var primaryAuth string
+
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		primaryAuth = r.Header.Get("Authorization")
+
+		if r.URL.Path == "/moved" {
+			http.Redirect(w, r, mirror.URL+"/redirected", http.StatusFound)
+
+			return
+		}
+
+		_, _ = w.Write([]byte("# Timeline\n"))
+	}))
+	defer primary.Close()
+
+	t.Setenv("TPWFC_TEST_TOKEN", "secret")
+
+	opts, err := RequestOptionsFor(&config.SourceConfig{
+		Name:       "staging",
+		URL:        primary.URL + "/timeline.md",
+		BackupURLs: []string{mirror.URL + "/timeline.md"},
+		TokenEnv:   "TPWFC_TEST_TOKEN",
+	})
+	if err != nil {
+		t.Fatalf("RequestOptionsFor() error = %v", err)
+	}
+
+	scraper := NewScraperWithConfig(&config.RetryPolicy{MaxAttempts: 1, BackoffMultiplier: 1, TimeoutSec: 5}, 64)
+
+	for _, target := range []string{primary.URL + "/timeline.md", mirror.URL + "/timeline.md", primary.URL + "/moved"} {
+		if _, _, _, err := scraper.ScrapeWithOptionsContext(context.Background(), target, opts); err != nil {
+			t.Fatalf("ScrapeWithOptionsContext(%s) error = %v", target, err)
+		}
+	}
+
+	if primaryAuth != "Bearer secret" {
+		t.Errorf("primary Authorization = %q, want the token", primaryAuth)
+	}
+
+	if len(mirrorAuth) != 1 || mirrorAuth[0] != "" {
+		t.Errorf("mirror on another host got Authorization %q", mirrorAuth)
+	}
+
+	if len(redirectAuth) != 1 || redirectAuth[0] != "" {
+		t.Errorf("cross-host redirect kept Authorization %q", redirectAuth)
+	}
+}
+
+func TestRequestOptions_TokenHosts(t *testing.T) {
+	opts := &RequestOptions{Token: "secret", TokenHosts: []string{"raw.githubusercontent.com", "mirror.example:8443"}}
+
+	tests := map[string]bool{
+		"https://raw.githubusercontent.com/a.md":      true,
+		"https://RAW.githubusercontent.com:8080/a.md": true,
+		"https://mirror.example:8443/a.md":            true,
+		"https://mirror.example/a.md":                 false,
+		"https://evil.example/raw.githubusercontent":  false,
+	}
+
+	for raw, want := range tests {
+		target, _ := url.Parse(raw)
+		if got := opts.authorizes(target); got != want {
+			t.Errorf("authorizes(%s) = %v, want %v", raw, got, want)
+		}
+	}
+}
+
+func TestRequestOptionsFor_MissingToken(t *testing.T) {
+	_, err := RequestOptionsFor(&config.SourceConfig{Name: "staging", TokenEnv: "TPWFC_TEST_TOKEN_UNSET"})
+	if !errors.Is(err, config.ErrTokenUnavailable) {
+		t.Errorf("RequestOptionsFor() error = %v, want %v", err, config.ErrTokenUnavailable)
+	}
+}
+
+func TestScraper_ScrapeWithOptionsContext_Proxy(t *testing.T) {
+	var requested string
+
+	// A plain-HTTP proxy receives the absolute target URL as the request URI
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = r.RequestURI
+		_, _ = w.Write([]byte("# Timeline\n"))
+	}))
+	defer proxy.Close()
+
+	opts, err := RequestOptionsFor(&config.SourceConfig{Name: "proxied", Proxy: proxy.URL})
+	if err != nil {
+		t.Fatalf("RequestOptionsFor() error = %v", err)
+	}
+
+	scraper := NewScraperWithConfig(&config.RetryPolicy{MaxAttempts: 1, BackoffMultiplier: 1, TimeoutSec: 5}, 64)
+
+	target := "http://data.example.invalid/timeline.md"
+	if _, _, _, err := scraper.ScrapeWithOptionsContext(context.Background(), target, opts); err != nil {
+		t.Fatalf("ScrapeWithOptionsContext() error = %v", err)
+	}
+
+	if requested != target {
+		t.Errorf("proxy saw %q, want %q", requested, target)
+	}
+}
+
+func TestScraper_ScrapeWithMetrics_DecodedTooLarge(t *testing.T) {
+	var buf bytes.Buffer
+
+	zw := gzip.NewWriter(&buf)
+	_, _ = zw.Write([]byte(strings.Repeat("x", 8*1024)))
+	_ = zw.Close()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept-Encoding") == "" {
+			t.Error("scraper did not negotiate compression")
+		}
+
+		w.Header().Set("Content-Encoding", "gzip")
+		_, _ = w.Write(buf.Bytes())
+	}))
+	defer server.Close()
+
+	// The compressed body fits in 1 KB, the decoded one does not
+	scraper := NewScraperWithConfig(&config.RetryPolicy{MaxAttempts: 3, BackoffMultiplier: 1, TimeoutSec: 5}, 1)
+
+	_, _, _, err := scraper.ScrapeWithMetrics(server.URL)
+	if !errors.Is(err, ErrResponseTooLarge) {
+		t.Errorf("expected %v, got %v", ErrResponseTooLarge, err)
+	}
+}
@@ -0,0 +1,217 @@
+package crawler
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Snapshot store errors.
+var (
+	ErrSnapshotNotFound  = errors.New("snapshot not found")
+	ErrSnapshotAmbiguous = errors.New("snapshot hash prefix is ambiguous")
+)
+
+// snapshotIndexFile is the append-only log of every stored fetch.
+const snapshotIndexFile = "index.jsonl"
+
+// SnapshotStore keeps every fetched source body in a content-addressed layout:
+// objects/{hash[:2]}/{hash}.md, plus an index.jsonl line per fetch.
+type SnapshotStore struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// SnapshotRecord describes one fetch of a source body.
+type SnapshotRecord struct {
+	FetchedAt time.Time `json:"fetchedAt"`
+	Hash      string    `json:"hash"`
+	Source    string    `json:"source"`
+	FireID    string    `json:"fireId"`
+	Language  string    `json:"language"`
+	Size      int       `json:"size"`
+}
+
+// SnapshotFilter selects index records; empty fields match everything.
+type SnapshotFilter struct {
+	FireID   string
+	Language string
+	Source   string
+}
+
+// NewSnapshotStore creates a store rooted at dir, creating the directory if needed.
+func NewSnapshotStore(dir string) (*SnapshotStore, error) {
+	if err := os.MkdirAll(filepath.Join(dir, "objects"), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create snapshot directory %s: %w", dir, err)
+	}
+
+	return &SnapshotStore{dir: dir}, nil
+}
+
+// Dir returns the store directory.
+func (s *SnapshotStore) Dir() string {
+	return s.dir
+}
+
+// Put stores content (once per distinct body) and appends a record of this fetch to the index.
+func (s *SnapshotStore) Put(content, source, fireID, language string) (*SnapshotRecord, error) {
+	sum := sha256.Sum256([]byte(content))
+	record := &SnapshotRecord{
+		FetchedAt: time.Now().UTC(),
+		Hash:      hex.EncodeToString(sum[:]),
+		Source:    source,
+		FireID:    fireID,
+		Language:  language,
+		Size:      len(content),
+	}
+
+	line, err := json.Marshal(record)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal snapshot record: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.writeObject(record.Hash, content); err != nil {
+		return nil, err
+	}
+
+	index, err := os.OpenFile(filepath.Join(s.dir, snapshotIndexFile), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open snapshot index: %w", err)
+	}
+
+	_, err = index.Write(append(line, '\n'))
+	if closeErr := index.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to append snapshot index: %w", err)
+	}
+
+	return record, nil
+}
+
+// writeObject stores content under its hash unless it is already present.
+func (s *SnapshotStore) writeObject(hash, content string) error {
+	path := s.objectPath(hash)
+	if _, err := os.Stat(path); err == nil {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create snapshot object directory: %w", err)
+	}
+
+	if err := WriteFileAtomic(path, []byte(content)); err != nil {
+		return fmt.Errorf("failed to store snapshot object: %w", err)
+	}
+
+	return nil
+}
+
+// List returns index records matching filter, oldest first.
+func (s *SnapshotStore) List(filter SnapshotFilter) ([]SnapshotRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, err := os.Open(filepath.Join(s.dir, snapshotIndexFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to open snapshot index: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	var records []SnapshotRecord
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var record SnapshotRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			// Skip a torn trailing line rather than hiding the rest of the history
+			continue
+		}
+
+		if filter.matches(&record) {
+			records = append(records, record)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read snapshot index: %w", err)
+	}
+
+	return records, nil
+}
+
+// Get returns the stored body for a full hash or a unique hash prefix, with the resolved hash.
+func (s *SnapshotStore) Get(hashPrefix string) (string, string, error) {
+	hash, err := s.resolve(strings.ToLower(hashPrefix))
+	if err != nil {
+		return "", "", err
+	}
+
+	data, err := os.ReadFile(s.objectPath(hash))
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read snapshot %s: %w", hash, err)
+	}
+
+	return string(data), hash, nil
+}
+
+// resolve expands a hash prefix to the single matching object hash.
+func (s *SnapshotStore) resolve(prefix string) (string, error) {
+	if len(prefix) < 2 {
+		return "", fmt.Errorf("%w: %q", ErrSnapshotNotFound, prefix)
+	}
+
+	entries, err := os.ReadDir(filepath.Join(s.dir, "objects", prefix[:2]))
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", ErrSnapshotNotFound, prefix)
+	}
+
+	var match string
+
+	for _, entry := range entries {
+		hash := strings.TrimSuffix(entry.Name(), ".md")
+		if hash == entry.Name() || !strings.HasPrefix(hash, prefix) {
+			continue
+		}
+
+		if match != "" {
+			return "", fmt.Errorf("%w: %s", ErrSnapshotAmbiguous, prefix)
+		}
+
+		match = hash
+	}
+
+	if match == "" {
+		return "", fmt.Errorf("%w: %s", ErrSnapshotNotFound, prefix)
+	}
+
+	return match, nil
+}
+
+// objectPath maps a content hash to its object file.
+func (s *SnapshotStore) objectPath(hash string) string {
+	return filepath.Join(s.dir, "objects", hash[:2], hash+".md")
+}
+
+func (f SnapshotFilter) matches(r *SnapshotRecord) bool {
+	return (f.FireID == "" || strings.EqualFold(f.FireID, r.FireID)) &&
+		(f.Language == "" || strings.EqualFold(f.Language, r.Language)) &&
+		(f.Source == "" || f.Source == r.Source)
+}
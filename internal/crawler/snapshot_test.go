@@ -0,0 +1,49 @@
+package crawler
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSnapshotStore_PutListGet(t *testing.T) {
+	store, err := NewSnapshotStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewSnapshotStore failed: %v", err)
+	}
+
+	first, err := store.Put("# v1", "https://example.com/a.md", "FIRE001", "zh-hk")
+	if err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	// Same body again is one object but a second index record
+	if _, err := store.Put("# v1", "https://example.com/a.md", "FIRE001", "zh-hk"); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	if _, err := store.Put("# other", "https://example.com/b.md", "FIRE002", "en-us"); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	records, err := store.List(SnapshotFilter{FireID: "FIRE001"})
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+
+	if len(records) != 2 {
+		t.Fatalf("expected 2 records for FIRE001, got %d", len(records))
+	}
+
+	body, hash, err := store.Get(first.Hash[:8])
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+
+	if body != "# v1" || hash != first.Hash {
+		t.Errorf("Get = (%q, %s), want (%q, %s)", body, hash, "# v1", first.Hash)
+	}
+
+	if _, _, err := store.Get("ffffffffff"); !errors.Is(err, ErrSnapshotNotFound) {
+		t.Errorf("expected ErrSnapshotNotFound, got %v", err)
+	}
+}
@@ -1,6 +1,7 @@
 package crawler
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"time"
@@ -19,6 +20,7 @@ var (
 type URLManager struct {
 	retryPolicy      *config.RetryPolicy
 	attemptLog       map[string][]AttempResult
+	attempts         []AttempResult
 	sourceAttempts   map[string]int
 	sources          []config.SourceConfig
 	currentSourceIdx int
@@ -29,13 +31,13 @@ type URLManager struct {
 
 // AttempResult records the result of a URL fetch attempt.
 type AttempResult struct {
-	Timestamp  time.Time
-	URL        string
-	Error      string
-	Attempt    int
-	Duration   time.Duration
-	StatusCode int
-	Success    bool
+	Timestamp  time.Time     `json:"timestamp"`
+	URL        string        `json:"url"`
+	Error      string        `json:"error,omitempty"`
+	Attempt    int           `json:"attempt"`
+	Duration   time.Duration `json:"durationNs"`
+	StatusCode int           `json:"statusCode,omitempty"`
+	Success    bool          `json:"success"`
 }
 
 // SourceInfo holds information about a source's current state.
@@ -136,6 +138,15 @@ func (um *URLManager) NextURL() (string, string, string, string, int, error) {
 	return url, source.Name, source.FireID, source.Language, attemptNum, nil
 }
 
+// NextURLContext is NextURL that stops handing out URLs once ctx is done.
+func (um *URLManager) NextURLContext(ctx context.Context) (string, string, string, string, int, error) {
+	if err := ctx.Err(); err != nil {
+		return "", "", "", "", 0, fmt.Errorf("url manager stopped: %w", err)
+	}
+
+	return um.NextURL()
+}
+
 // moveToNextSource advances to the next source and resets state.
 func (um *URLManager) moveToNextSource() (string, string, string, string, int, error) {
 	um.currentSourceIdx++
@@ -176,7 +187,7 @@ func (um *URLManager) RecordAttempt(url string, success bool, err error, statusC
 		errMsg = err.Error()
 	}
 
-	um.attemptLog[url] = append(um.attemptLog[url], AttempResult{
+	result := AttempResult{
 		URL:        url,
 		Attempt:    len(um.attemptLog[url]) + 1,
 		Success:    success,
@@ -184,7 +195,10 @@ func (um *URLManager) RecordAttempt(url string, success bool, err error, statusC
 		Timestamp:  time.Now(),
 		Duration:   duration,
 		StatusCode: statusCode,
-	})
+	}
+
+	um.attemptLog[url] = append(um.attemptLog[url], result)
+	um.attempts = append(um.attempts, result)
 }
 
 // GetRetryDelay returns the delay before next attempt.
@@ -221,6 +235,11 @@ func (um *URLManager) GetAttemptLog(url string) []AttempResult {
 	return um.attemptLog[url]
 }
 
+// GetAllAttempts returns every recorded attempt across all URLs, in the order they were made.
+func (um *URLManager) GetAllAttempts() []AttempResult {
+	return append([]AttempResult(nil), um.attempts...)
+}
+
 // GetAttemptStats returns statistics about fetch attempts.
 func (um *URLManager) GetAttemptStats() AttempStats {
 	stats := AttempStats{
@@ -326,4 +345,5 @@ func (um *URLManager) Reset() {
 	um.isFallbackMode = false
 	um.sourceAttempts = make(map[string]int)
 	um.attemptLog = make(map[string][]AttempResult)
+	um.attempts = nil
 }
@@ -0,0 +1,114 @@
+package crawler
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"tpwfc/internal/config"
+	"tpwfc/pkg/metadata"
+)
+
+// Document verification outcomes.
+const (
+	VerifyStatusVerified     = "verified"
+	VerifyStatusUnsigned     = "unsigned"
+	VerifyStatusMismatch     = "hash_mismatch"
+	VerifyStatusNotValidated = "not_validated"
+)
+
+// Verification errors.
+var (
+	ErrNotValidated = errors.New("document was signed without passing validation")
+	ErrUnverified   = errors.New("document failed metadata verification")
+)
+
+// Verification is the result of checking a document against its metadata block.
+type Verification struct {
+	Err       error  `json:"-"`
+	Status    string `json:"status"`
+	Hash      string `json:"hash,omitempty"`
+	Reason    string `json:"reason,omitempty"`
+	Validated bool   `json:"validated"`
+}
+
+// OK reports whether the document passed verification.
+func (v *Verification) OK() bool {
+	return v.Status == VerifyStatusVerified
+}
+
+// VerifyDocument checks the document HASH and its VALIDATION flag. A document signed with
+// VALIDATION: FALSE fails verification unless allowUnvalidated is set.
+func VerifyDocument(content string, allowUnvalidated bool) *Verification {
+	meta, _ := metadata.Extract(content)
+
+	result := &Verification{Status: VerifyStatusVerified}
+	if meta != nil {
+		result.Hash = meta.Hash
+		result.Validated = meta.Validation
+	}
+
+	if _, err := metadata.Verify(content); err != nil {
+		result.Err = err
+		result.Reason = err.Error()
+
+		result.Status = VerifyStatusMismatch
+		if errors.Is(err, metadata.ErrNoMetadataBlock) || errors.Is(err, metadata.ErrNoHashFound) {
+			result.Status = VerifyStatusUnsigned
+		}
+
+		return result
+	}
+
+	if !allowUnvalidated && !result.Validated {
+		result.Status = VerifyStatusNotValidated
+		result.Err = ErrNotValidated
+		result.Reason = ErrNotValidated.Error()
+	}
+
+	return result
+}
+
+// Quarantine copies a rejected document to {dir}/{fireID}/{language}/{timestamp}.md
+// and returns the path written.
+func Quarantine(dir, fireID, language, content string) (string, error) {
+	targetDir := filepath.Join(dir, fireID, language)
+	if err := os.MkdirAll(targetDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create quarantine directory: %w", err)
+	}
+
+	path := filepath.Join(targetDir, time.Now().UTC().Format("20060102T150405.000Z")+".md")
+	if err := WriteFileAtomic(path, []byte(content)); err != nil {
+		return "", fmt.Errorf("failed to write quarantined document: %w", err)
+	}
+
+	return path, nil
+}
+
+// ApplyVerifyMode decides what happens to a document that failed verification. In warn mode
+// the document is let through; refuse rejects it, and quarantine rejects it after copying it
+// to quarantineDir. It returns the quarantine path, if any, and an error wrapping
+// ErrUnverified when the document must not be used.
+func ApplyVerifyMode(v *Verification, content, fireID, language, mode, quarantineDir string) (string, error) {
+	if v.OK() {
+		return "", nil
+	}
+
+	refused := fmt.Errorf("%w: %s: %w", ErrUnverified, v.Status, v.Err)
+
+	switch mode {
+	case config.VerifyRefuse:
+		return "", refused
+	case config.VerifyQuarantine:
+		path, err := Quarantine(quarantineDir, fireID, language, content)
+		if err != nil {
+			return "", errors.Join(refused, err)
+		}
+
+		return path, refused
+	}
+
+	return "", nil
+}
@@ -0,0 +1,121 @@
+package crawler
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"tpwfc/internal/config"
+	"tpwfc/pkg/metadata"
+)
+
+func TestVerifyDocument(t *testing.T) {
+	content := "# Timeline\n\nSome events"
+	signed := metadata.Sign(content, true, nil)
+	unvalidated := metadata.Sign(content, false, nil)
+
+	tests := []struct {
+		name             string
+		content          string
+		allowUnvalidated bool
+		wantStatus       string
+	}{
+		{"signed", signed, false, VerifyStatusVerified},
+		{"unsigned", content, false, VerifyStatusUnsigned},
+		{"tampered", signed + "\nextra", false, VerifyStatusMismatch},
+		{"not validated", unvalidated, false, VerifyStatusNotValidated},
+		{"not validated allowed", unvalidated, true, VerifyStatusVerified},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := VerifyDocument(tt.content, tt.allowUnvalidated)
+			if got.Status != tt.wantStatus {
+				t.Errorf("VerifyDocument() status = %s, want %s (reason: %s)", got.Status, tt.wantStatus, got.Reason)
+			}
+
+			if got.OK() != (tt.wantStatus == VerifyStatusVerified) {
+				t.Errorf("OK() = %v for status %s", got.OK(), got.Status)
+			}
+		})
+	}
+}
+
+func TestQuarantine(t *testing.T) {
+	path, err := Quarantine(t.TempDir(), "FIRE001", "zh-hk", "# Bad")
+	if err != nil {
+		t.Fatalf("Quarantine failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != "# Bad" {
+		t.Errorf("quarantined content = %q, %v", data, err)
+	}
+}
+
+func TestApplyVerifyMode(t *testing.T) {
+	unsigned := VerifyDocument("# Timeline", false)
+
+	tests := []struct {
+		mode    string
+		wantErr bool
+	}{
+		{config.VerifyWarn, false},
+		{config.VerifyRefuse, true},
+		{config.VerifyQuarantine, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			dir := t.TempDir()
+
+			path, err := ApplyVerifyMode(unsigned, "# Timeline", "FIRE001", "en", tt.mode, dir)
+			if errors.Is(err, ErrUnverified) != tt.wantErr {
+				t.Errorf("ApplyVerifyMode() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if (path != "") != (tt.mode == config.VerifyQuarantine) {
+				t.Errorf("ApplyVerifyMode() path = %q in %s mode", path, tt.mode)
+			}
+		})
+	}
+}
+
+func TestScrape_HashMismatchReachesQuarantine(t *testing.T) {
+	tampered := strings.Replace(metadata.Sign("# Timeline\n\nAlarm raised", true, nil), "Alarm", "Fire", 1)
+
+	hits := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		hits++
+		_, _ = w.Write([]byte(tampered))
+	}))
+	defer server.Close()
+
+	scraper := NewScraperWithConfig(&config.RetryPolicy{MaxAttempts: 3, TimeoutSec: 5}, 64)
+
+	// An edited document is complete: it is fetched once, not retried as truncated
+	body, _, _, err := scraper.ScrapeWithMetrics(server.URL)
+	if err != nil || body != tampered || hits != 1 {
+		t.Fatalf("ScrapeWithMetrics() = %q, %v after %d requests, want the document on the first", body, err, hits)
+	}
+
+	verification := VerifyDocument(body, false)
+	if verification.Status != VerifyStatusMismatch {
+		t.Fatalf("VerifyDocument() status = %s, want %s", verification.Status, VerifyStatusMismatch)
+	}
+
+	dir := t.TempDir()
+
+	path, err := ApplyVerifyMode(verification, body, "FIRE001", "zh-hk", config.VerifyQuarantine, dir)
+	if !errors.Is(err, ErrUnverified) || !errors.Is(err, metadata.ErrHashMismatch) {
+		t.Fatalf("ApplyVerifyMode() error = %v, want ErrUnverified wrapping the hash mismatch", err)
+	}
+
+	if rel, _ := filepath.Rel(dir, path); !strings.HasPrefix(rel, filepath.Join("FIRE001", "zh-hk")) {
+		t.Errorf("quarantined to %s, want under %s", path, dir)
+	}
+}
@@ -0,0 +1,126 @@
+package crawler
+
+import (
+	"context"
+	"os"
+	"slices"
+	"time"
+)
+
+// Default watch timings.
+const (
+	DefaultWatchInterval = 500 * time.Millisecond
+	DefaultWatchDebounce = 300 * time.Millisecond
+)
+
+// fileStamp is what the watcher remembers about a file between polls.
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+	exists  bool
+}
+
+// FileWatcher polls a fixed set of files for changes. Polling needs no platform
+// support and also sees editors that save by renaming a temporary file over the original.
+type FileWatcher struct {
+	stamps map[string]fileStamp
+	paths  []string
+	// Interval is the time between polls.
+	Interval time.Duration
+	// Debounce is how long the files must stay unchanged before a change is reported,
+	// so an editor writing a file in several steps triggers one run.
+	Debounce time.Duration
+}
+
+// NewFileWatcher returns a watcher for paths, taking their current state as the baseline.
+func NewFileWatcher(paths []string, interval, debounce time.Duration) *FileWatcher {
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+
+	if debounce < 0 {
+		debounce = 0
+	}
+
+	w := &FileWatcher{
+		stamps:   make(map[string]fileStamp, len(paths)),
+		paths:    slices.Clone(paths),
+		Interval: interval,
+		Debounce: debounce,
+	}
+
+	for _, path := range w.paths {
+		w.stamps[path] = stat(path)
+	}
+
+	return w
+}
+
+// Paths returns the watched paths.
+func (w *FileWatcher) Paths() []string {
+	return slices.Clone(w.paths)
+}
+
+// Changed polls every file once and returns those created, modified or deleted
+// since the previous poll, in watch order.
+func (w *FileWatcher) Changed() []string {
+	var changed []string
+
+	for _, path := range w.paths {
+		current := stat(path)
+		if current != w.stamps[path] {
+			w.stamps[path] = current
+			changed = append(changed, path)
+		}
+	}
+
+	return changed
+}
+
+// Watch polls until ctx is done and calls onChange with the files that changed once
+// they have been quiet for the debounce period. onChange runs on the watching goroutine,
+// so changes made while it runs are reported by the next call.
+func (w *FileWatcher) Watch(ctx context.Context, onChange func(changed []string)) error {
+	ticker := time.NewTicker(w.Interval)
+	defer ticker.Stop()
+
+	pending := make(map[string]bool)
+
+	var lastChange time.Time
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case now := <-ticker.C:
+			for _, path := range w.Changed() {
+				pending[path] = true
+				lastChange = now
+			}
+
+			if len(pending) == 0 || now.Sub(lastChange) < w.Debounce {
+				continue
+			}
+
+			changed := make([]string, 0, len(pending))
+			for _, path := range w.paths {
+				if pending[path] {
+					changed = append(changed, path)
+				}
+			}
+
+			clear(pending)
+			onChange(changed)
+		}
+	}
+}
+
+// stat returns the stamp of path; a missing or unreadable file has the zero stamp.
+func stat(path string) fileStamp {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileStamp{}
+	}
+
+	return fileStamp{modTime: info.ModTime(), size: info.Size(), exists: true}
+}
@@ -0,0 +1,100 @@
+package crawler
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestFileWatcher_Changed(t *testing.T) {
+	dir := t.TempDir()
+	existing := filepath.Join(dir, "timeline.md")
+	missing := filepath.Join(dir, "later.md")
+
+	if err := os.WriteFile(existing, []byte("v1"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	w := NewFileWatcher([]string{existing, missing}, time.Millisecond, 0)
+
+	if changed := w.Changed(); len(changed) != 0 {
+		t.Fatalf("Changed() = %v before any edit, want none", changed)
+	}
+
+	if err := os.WriteFile(existing, []byte("version 2"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(missing, []byte("new"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if changed := w.Changed(); !slices.Equal(changed, []string{existing, missing}) {
+		t.Errorf("Changed() = %v, want both files", changed)
+	}
+
+	if changed := w.Changed(); len(changed) != 0 {
+		t.Errorf("Changed() = %v on second poll, want none", changed)
+	}
+
+	if err := os.Remove(existing); err != nil {
+		t.Fatal(err)
+	}
+
+	if changed := w.Changed(); !slices.Equal(changed, []string{existing}) {
+		t.Errorf("Changed() = %v after delete, want %s", changed, existing)
+	}
+}
+
+func TestFileWatcher_WatchDebounces(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "timeline.md")
+	if err := os.WriteFile(path, []byte("v1"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	w := NewFileWatcher([]string{path}, 5*time.Millisecond, 50*time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	calls := make(chan []string, 10)
+	done := make(chan error, 1)
+
+	go func() {
+		done <- w.Watch(ctx, func(changed []string) { calls <- changed })
+	}()
+
+	// A burst of writes, each well inside the debounce period
+	for i := range 5 {
+		if err := os.WriteFile(path, []byte("version "+string(rune('a'+i))+" with more text"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	select {
+	case changed := <-calls:
+		if !slices.Equal(changed, []string{path}) {
+			t.Errorf("onChange(%v), want [%s]", changed, path)
+		}
+	case <-ctx.Done():
+		t.Fatal("onChange was not called")
+	}
+
+	// Nothing else changed, so no second call
+	select {
+	case changed := <-calls:
+		t.Errorf("unexpected second onChange(%v)", changed)
+	case <-time.After(150 * time.Millisecond):
+	}
+
+	cancel()
+
+	if err := <-done; err == nil {
+		t.Error("Watch returned nil after cancellation, want the context error")
+	}
+}
@@ -4,6 +4,7 @@ package formatter
 import (
 	"strings"
 
+	"tpwfc/internal/mdtable"
 	"tpwfc/pkg/metadata"
 
 	"github.com/mattn/go-runewidth"
@@ -68,23 +69,20 @@ func processTable(rows []string) []string {
 	// 1. Parse all cells
 	var table [][]string
 
-	for _, row := range rows {
-		// Remove leading/trailing pipes for splitting, but keep them in mind for reconstruction
-		// Standard markdown table: | cell1 | cell2 |
-		parts := strings.Split(row, "|")
+	separatorRowIdx := -1
 
-		// The split will result in empty strings at start/end if the line starts/ends with pipe
-		if len(parts) > 0 && strings.TrimSpace(parts[0]) == "" {
-			parts = parts[1:]
-		}
+	for i, line := range rows {
+		row := mdtable.ParseRow(line)
 
-		if len(parts) > 0 && strings.TrimSpace(parts[len(parts)-1]) == "" {
-			parts = parts[:len(parts)-1]
+		// Identify separator row (usually 2nd row, index 1)
+		if i == 1 && row.IsSeparator() {
+			separatorRowIdx = 1
 		}
 
-		var cells []string
-		for _, p := range parts {
-			cells = append(cells, strings.TrimSpace(p))
+		// Keep the cells' source text so escaped pipes stay escaped
+		cells := make([]string, 0, row.Len())
+		for _, cell := range row.Cells {
+			cells = append(cells, strings.TrimSpace(cell.Raw))
 		}
 
 		table = append(table, cells)
@@ -103,28 +101,6 @@ func processTable(rows []string) []string {
 		}
 	}
 
-	// Identify separator row (usually 2nd row, index 1)
-	separatorRowIdx := -1
-
-	if len(table) > 1 {
-		isSep := true
-		for _, cell := range table[1] {
-			trim := strings.TrimSpace(cell)
-			trim = strings.ReplaceAll(trim, "-", "")
-			trim = strings.ReplaceAll(trim, ":", "") // Handle alignment :--- or ---:
-			trim = strings.ReplaceAll(trim, " ", "")
-
-			if trim != "" {
-				isSep = false
-				break
-			}
-		}
-
-		if isSep {
-			separatorRowIdx = 1
-		}
-	}
-
 	// 3. Calculate max widths (using display width)
 	colWidths := make([]int, colCount)
 
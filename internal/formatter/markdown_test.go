@@ -95,6 +95,21 @@ Text after table.
 | ---------- | ------------------ |
 | 2025-01-01 | 消防處：增至83死。 |
 | 2025-01-02 | Short text         |
+`,
+		},
+		{
+			name: "Escaped pipes and code spans stay in their cell",
+			input: `
+| Date | Event |
+| --- | --- |
+| 2025-01-01 | a \| b |
+| 2025-01-02 | ` + "`x|y`" + ` |
+`,
+			expected: `
+| Date       | Event  |
+| ---------- | ------ |
+| 2025-01-01 | a \| b |
+| 2025-01-02 | ` + "`x|y`" + `  |
 `,
 		},
 	}
@@ -0,0 +1,66 @@
+package mdtable
+
+import "strings"
+
+// Normalized column names of timeline tables.
+const (
+	ColDate       = "DATE"
+	ColTime       = "TIME"
+	ColEvent      = "EVENT"
+	ColCategory   = "CATEGORY"
+	ColCasualties = "CASUALTIES"
+	ColSource     = "SOURCE"
+	ColVideo      = "VIDEO"
+	ColPhoto      = "PHOTO"
+	ColEnd        = "END"
+)
+
+// Normalized column names of investigation and response tables.
+const (
+	ColFinding          = "FINDING"
+	ColStatus           = "STATUS"
+	ColOrganization     = "ORGANIZATION"
+	ColOrganizationType = "ORGANIZATION_TYPE"
+	ColResponseType     = "RESPONSE_TYPE"
+	ColResponse         = "RESPONSE"
+)
+
+// NormalizeHeader maps an English or Chinese header cell to its column name. Unknown headers
+// are returned upper-cased.
+func NormalizeHeader(header string) string {
+	h := strings.ToUpper(strings.TrimSpace(header))
+	switch h {
+	case "DATE", "日期":
+		return ColDate
+	case "TIME", "時間", "时间":
+		return ColTime
+	case "EVENT", "事件", "DESCRIPTION", "描述":
+		return ColEvent
+	case "CATEGORY", "類別", "类别":
+		return ColCategory
+	case "CASUALTIES", "死傷狀況", "死伤状况":
+		return ColCasualties
+	case "SOURCE", "SOURCES", "來源", "来源":
+		return ColSource
+	case "VIDEO", "影片", "视频":
+		return ColVideo
+	case "PHOTO", "PHOTOS", "圖片", "图片", "PHOTO/IMAGE":
+		return ColPhoto
+	case "END", "結束", "结束":
+		return ColEnd
+	case "FINDING", "調查結果", "调查结果", "發現", "发现":
+		return ColFinding
+	case "STATUS", "狀態", "状态":
+		return ColStatus
+	case "ORGANIZATION", "機構", "机构", "組織", "组织":
+		return ColOrganization
+	case "ORGANIZATION_TYPE", "機構類型", "机构类型", "組織類型", "组织类型":
+		return ColOrganizationType
+	case "RESPONSE_TYPE", "回應類型", "回应类型":
+		return ColResponseType
+	case "RESPONSE", "回應", "回应", "回應內容", "回应内容":
+		return ColResponse
+	default:
+		return h
+	}
+}
@@ -0,0 +1,268 @@
+// Package mdtable tokenizes GitHub-flavoured markdown table rows.
+//
+// Rows are split on unescaped pipes. A pipe written as \| belongs to its cell, and so does a
+// pipe inside an inline code span or an inline link, so a description such as
+// "[報告 | 2025](https://example.com)" or "`a|b`" stays in one cell.
+package mdtable
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrColumnCount is returned by Row.CheckWidth for a row whose cell count differs from its header's.
+var ErrColumnCount = errors.New("column count mismatch")
+
+// Cell is one cell of a table row.
+type Cell struct {
+	// Raw is the source text between the cell's pipes, untrimmed and with escapes intact.
+	Raw string
+	// Text is Raw trimmed, with escaped pipes (\|) unescaped.
+	Text string
+	// Column is the 1-based character column of the cell's content in the source line, or of
+	// the character after its opening pipe when the cell is empty.
+	Column int
+}
+
+// Row is one tokenized table row.
+type Row struct {
+	// Line is the source line the row was read from.
+	Line  string
+	Cells []Cell
+}
+
+// ParseRow splits a table row into cells. The leading and trailing pipes are optional, as in GFM.
+func ParseRow(line string) Row {
+	row := Row{Line: line}
+	pipes := delimiters(line)
+
+	bounds := make([][2]int, 0, len(pipes)+1)
+	start := 0
+
+	for _, pipe := range pipes {
+		bounds = append(bounds, [2]int{start, pipe})
+		start = pipe + 1
+	}
+
+	bounds = append(bounds, [2]int{start, len(line)})
+
+	// Drop the empty segments outside a leading and a trailing pipe.
+	if len(pipes) > 0 && isBlank(line[:pipes[0]]) {
+		bounds = bounds[1:]
+	}
+
+	if len(pipes) > 0 && len(bounds) > 0 && isBlank(line[pipes[len(pipes)-1]+1:]) {
+		bounds = bounds[:len(bounds)-1]
+	}
+
+	row.Cells = make([]Cell, 0, len(bounds))
+
+	for _, b := range bounds {
+		raw := line[b[0]:b[1]]
+		offset := b[0]
+
+		if content := strings.TrimLeft(raw, " \t"); content != "" {
+			offset += len(raw) - len(content)
+		}
+
+		row.Cells = append(row.Cells, Cell{
+			Raw:    raw,
+			Text:   strings.ReplaceAll(strings.TrimSpace(raw), `\|`, "|"),
+			Column: utf8.RuneCountInString(line[:offset]) + 1,
+		})
+	}
+
+	return row
+}
+
+// Len returns the number of cells.
+func (r Row) Len() int {
+	return len(r.Cells)
+}
+
+// Texts returns the Text of every cell.
+func (r Row) Texts() []string {
+	texts := make([]string, len(r.Cells))
+	for i, cell := range r.Cells {
+		texts[i] = cell.Text
+	}
+
+	return texts
+}
+
+// Text returns the Text of cell i, or "" when the row has no such cell.
+func (r Row) Text(i int) string {
+	if i < 0 || i >= len(r.Cells) {
+		return ""
+	}
+
+	return r.Cells[i].Text
+}
+
+// Column returns the Column of cell i, or 0 when the row has no such cell.
+func (r Row) Column(i int) int {
+	if i < 0 || i >= len(r.Cells) {
+		return 0
+	}
+
+	return r.Cells[i].Column
+}
+
+// Start returns the 1-based character column of the row's first non-blank character.
+func (r Row) Start() int {
+	content := strings.TrimLeft(r.Line, " \t")
+
+	return utf8.RuneCountInString(r.Line[:len(r.Line)-len(content)]) + 1
+}
+
+// IsSeparator reports whether the row is a header separator such as "| --- | :---: |".
+func (r Row) IsSeparator() bool {
+	dashes := false
+
+	for _, cell := range r.Cells {
+		if strings.Trim(cell.Text, "-:") != "" {
+			return false
+		}
+
+		dashes = dashes || strings.Contains(cell.Text, "-")
+	}
+
+	return dashes
+}
+
+// Header maps the NormalizeHeader name of each cell of a header row to the cell's index.
+func (r Row) Header() map[string]int {
+	columns := make(map[string]int, len(r.Cells))
+	for i, cell := range r.Cells {
+		columns[NormalizeHeader(cell.Text)] = i
+	}
+
+	return columns
+}
+
+// CheckWidth returns an ErrColumnCount error when the row does not have width cells.
+func (r Row) CheckWidth(width int) error {
+	if len(r.Cells) != width {
+		return fmt.Errorf("%w: %d cells, the header has %d", ErrColumnCount, len(r.Cells), width)
+	}
+
+	return nil
+}
+
+// delimiters returns the byte offsets of the pipes that separate the cells of line.
+func delimiters(line string) []int {
+	var pipes []int
+
+	for i := 0; i < len(line); {
+		switch line[i] {
+		case '\\':
+			i += 2
+		case '`':
+			n := runLength(line, i, '`')
+			if end := closingRun(line, i+n, n); end >= 0 {
+				i = end + n
+			} else {
+				i += n
+			}
+		case '[':
+			if end := linkEnd(line, i); end >= 0 {
+				i = end
+			} else {
+				i++
+			}
+		case '|':
+			pipes = append(pipes, i)
+			i++
+		default:
+			i++
+		}
+	}
+
+	return pipes
+}
+
+// runLength returns the length of the run of c starting at line[i].
+func runLength(line string, i int, c byte) int {
+	n := 0
+	for i+n < len(line) && line[i+n] == c {
+		n++
+	}
+
+	return n
+}
+
+// closingRun returns the offset of the first run of exactly n backticks at or after from, which
+// closes a code span opened by n backticks, or -1 when the span is never closed.
+func closingRun(line string, from, n int) int {
+	for from < len(line) {
+		idx := strings.IndexByte(line[from:], '`')
+		if idx < 0 {
+			return -1
+		}
+
+		start := from + idx
+		m := runLength(line, start, '`')
+
+		if m == n {
+			return start
+		}
+
+		from = start + m
+	}
+
+	return -1
+}
+
+// linkEnd returns the offset just past the inline link "[text](destination "title")" that
+// starts at line[i], or -1 when no inline link starts there.
+func linkEnd(line string, i int) int {
+	depth := 0
+	j := i
+
+	for ; j < len(line); j++ {
+		switch line[j] {
+		case '\\':
+			j++
+		case '[':
+			depth++
+		case ']':
+			depth--
+		}
+
+		if depth == 0 {
+			break
+		}
+	}
+
+	if j+1 >= len(line) || line[j+1] != '(' {
+		return -1
+	}
+
+	depth = 0
+	inTitle := false
+
+	for k := j + 1; k < len(line); k++ {
+		switch c := line[k]; {
+		case c == '\\':
+			k++
+		case c == '"':
+			inTitle = !inTitle
+		case inTitle:
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+			if depth == 0 {
+				return k + 1
+			}
+		}
+	}
+
+	return -1
+}
+
+func isBlank(s string) bool {
+	return strings.TrimSpace(s) == ""
+}
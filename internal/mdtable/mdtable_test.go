@@ -0,0 +1,106 @@
+package mdtable
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseRow(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want []string
+	}{
+		{"plain", "| a | b | c |", []string{"a", "b", "c"}},
+		{"no outer pipes", "a | b", []string{"a", "b"}},
+		{"empty cells", "| | b |  |", []string{"", "b", ""}},
+		{"escaped pipe", `| 2025-11-26 | 火勢 \| 四級 | x |`, []string{"2025-11-26", "火勢 | 四級", "x"}},
+		{"code span", "| `a|b` | c |", []string{"`a|b`", "c"}},
+		{"double backtick code span", "| ``a`|`b`` | c |", []string{"``a`|`b``", "c"}},
+		{"unclosed code span", "| `a | b |", []string{"`a", "b"}},
+		{"link text", "| [報告 | 2025](https://example.com) | c |", []string{"[報告 | 2025](https://example.com)", "c"}},
+		{"link title", `| [a](https://example.com "x | y") | c |`, []string{`[a](https://example.com "x | y")`, "c"}},
+		{"link with parentheses", "| [a](https://example.com/(x|y)) | c |", []string{"[a](https://example.com/(x|y))", "c"}},
+		{"brackets without link", "| [a | b] | c |", []string{"[a", "b]", "c"}},
+		{"trailing escaped pipe", `| a | b\|`, []string{"a", "b|"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseRow(tt.line).Texts(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseRow(%q) = %q, want %q", tt.line, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRow_Raw(t *testing.T) {
+	row := ParseRow(`| a \| b |  c |`)
+
+	if row.Cells[0].Raw != ` a \| b ` || row.Cells[1].Raw != "  c " {
+		t.Errorf("Raw = %q, %q", row.Cells[0].Raw, row.Cells[1].Raw)
+	}
+}
+
+func TestRow_Column(t *testing.T) {
+	row := ParseRow("  | 日期 |  14:00 | | x")
+
+	tests := []struct {
+		i    int
+		want int
+	}{
+		{0, 5},
+		{1, 11},
+		{2, 18},
+		{3, 21},
+		{4, 0},
+	}
+
+	for _, tt := range tests {
+		if got := row.Column(tt.i); got != tt.want {
+			t.Errorf("Column(%d) = %d, want %d", tt.i, got, tt.want)
+		}
+	}
+
+	if row.Start() != 3 {
+		t.Errorf("Start() = %d, want 3", row.Start())
+	}
+}
+
+func TestRow_IsSeparator(t *testing.T) {
+	tests := map[string]bool{
+		"| --- | :---: | ---: |": true,
+		"|------|------|":        true,
+		"| --- | text |":         false,
+		"| 2025-11-26 | --- |":   false,
+		"| |":                    false,
+	}
+
+	for line, want := range tests {
+		if got := ParseRow(line).IsSeparator(); got != want {
+			t.Errorf("IsSeparator(%q) = %v, want %v", line, got, want)
+		}
+	}
+}
+
+func TestRow_Header(t *testing.T) {
+	got := ParseRow("| 日期 | TIME | Description | 來源 |").Header()
+	want := map[string]int{ColDate: 0, ColTime: 1, ColEvent: 2, ColSource: 3}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Header() = %v, want %v", got, want)
+	}
+}
+
+func TestRow_CheckWidth(t *testing.T) {
+	row := ParseRow(`| a | b \| c |`)
+
+	if err := row.CheckWidth(2); err != nil {
+		t.Errorf("CheckWidth(2) = %v", err)
+	}
+
+	if err := row.CheckWidth(3); !errors.Is(err, ErrColumnCount) {
+		t.Errorf("CheckWidth(3) = %v, want ErrColumnCount", err)
+	}
+}
@@ -0,0 +1,33 @@
+package models
+
+import "tpwfc/pkg/metadata"
+
+// InvestigationDocument represents a parsed FIRE_INVESTIGATION document.
+type InvestigationDocument struct {
+	Metadata *metadata.Metadata     `json:"metadata"`
+	Info     InvestigationInfo      `json:"info"`
+	Summary  string                 `json:"summary"`
+	Findings []InvestigationFinding `json:"findings"`
+	Sources  []Source               `json:"sources"`
+	Notes    []string               `json:"notes"`
+}
+
+// InvestigationInfo holds the INVESTIGATION_INFO section.
+type InvestigationInfo struct {
+	IncidentID   string `json:"incidentId"`
+	Title        string `json:"title"`
+	Investigator string `json:"investigator"`
+	Status       string `json:"status"`
+	StartDate    string `json:"startDate"`
+	ReportDate   string `json:"reportDate"`
+}
+
+// InvestigationFinding represents a single row of the FINDINGS table.
+type InvestigationFinding struct {
+	ID       string        `json:"id"`
+	Date     string        `json:"date"`
+	Category string        `json:"category"`
+	Finding  string        `json:"finding"`
+	Status   string        `json:"status"`
+	Sources  []EventSource `json:"sources"`
+}
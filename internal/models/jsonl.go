@@ -0,0 +1,21 @@
+package models
+
+import "encoding/json"
+
+// JSONL record types. A timeline file starts with one RecordIncident line followed by a
+// RecordEvent line per event. A detailed timeline file starts with its RecordIncident line,
+// then each RecordPhase is followed by its RecordPhaseEvent lines, then RecordTracking lines.
+const (
+	RecordIncident   = "incident"
+	RecordEvent      = "event"
+	RecordPhase      = "phase"
+	RecordPhaseEvent = "phaseEvent"
+	RecordTracking   = "tracking"
+)
+
+// JSONLRecord is one line of JSONL output.
+type JSONLRecord struct {
+	Type    string          `json:"type"`
+	PhaseID string          `json:"phaseId,omitempty"`
+	Data    json.RawMessage `json:"data"`
+}
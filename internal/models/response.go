@@ -0,0 +1,29 @@
+package models
+
+import "tpwfc/pkg/metadata"
+
+// ResponsesDocument represents a parsed FIRE_RESPONSES document.
+type ResponsesDocument struct {
+	Metadata  *metadata.Metadata `json:"metadata"`
+	Info      ResponsesInfo      `json:"info"`
+	Responses []FireResponse     `json:"responses"`
+	Sources   []Source           `json:"sources"`
+	Notes     []string           `json:"notes"`
+}
+
+// ResponsesInfo holds the RESPONSES_INFO section.
+type ResponsesInfo struct {
+	IncidentID string `json:"incidentId"`
+	Title      string `json:"title"`
+}
+
+// FireResponse represents a government or organisational response, one row of the RESPONSES table.
+type FireResponse struct {
+	ID               string        `json:"id"`
+	Date             string        `json:"date"`
+	Organization     string        `json:"organization"`
+	OrganizationType string        `json:"organizationType"`
+	ResponseType     string        `json:"responseType"`
+	Description      string        `json:"description"`
+	Sources          []EventSource `json:"sources"`
+}
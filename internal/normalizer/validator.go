@@ -9,7 +9,7 @@ import (
 
 // Validation errors.
 var (
-	ErrInvalidDataType      = errors.New("invalid data type: expected *models.TimelineDocument")
+	ErrInvalidDataType      = errors.New("invalid data type: expected a *models.TimelineDocument, *models.DetailedTimelineDocument, *models.InvestigationDocument or *models.ResponsesDocument")
 	ErrMissingIncidentID    = errors.New("missing incident ID in basic info")
 	ErrMissingIncidentName  = errors.New("missing incident name in basic info")
 	ErrNoEvents             = errors.New("timeline document contains no events")
@@ -18,6 +18,15 @@ var (
 	ErrEventMissingDateTime = errors.New("event missing datetime")
 	ErrNoSources            = errors.New("timeline document contains no sources")
 	ErrEventMissingID       = errors.New("event missing ID")
+	ErrNoPhases             = errors.New("detailed timeline document contains no phases or long-term tracking events")
+	ErrMissingTitle         = errors.New("missing title")
+	ErrNoFindings           = errors.New("investigation document contains no findings")
+	ErrFindingMissingID     = errors.New("finding missing ID")
+	ErrFindingMissingDate   = errors.New("finding missing date")
+	ErrNoResponses          = errors.New("responses document contains no responses")
+	ErrResponseMissingID    = errors.New("response missing ID")
+	ErrResponseMissingDate  = errors.New("response missing date")
+	ErrResponseMissingOrg   = errors.New("response missing organization")
 )
 
 // Validator handles data validation.
@@ -30,13 +39,24 @@ func NewValidator() *Validator {
 	return &Validator{}
 }
 
-// Validate checks if data meets requirements.
+// Validate checks if data meets requirements. data is a parsed timeline, detailed timeline,
+// investigation or responses document.
 func (v *Validator) Validate(data interface{}) error {
-	doc, ok := data.(*models.TimelineDocument)
-	if !ok {
+	switch doc := data.(type) {
+	case *models.TimelineDocument:
+		return v.validateTimeline(doc)
+	case *models.DetailedTimelineDocument:
+		return v.validateDetailedTimeline(doc)
+	case *models.InvestigationDocument:
+		return v.validateInvestigation(doc)
+	case *models.ResponsesDocument:
+		return v.validateResponses(doc)
+	default:
 		return ErrInvalidDataType
 	}
+}
 
+func (v *Validator) validateTimeline(doc *models.TimelineDocument) error {
 	if doc.BasicInfo.IncidentID == "" {
 		return ErrMissingIncidentID
 	}
@@ -76,3 +96,63 @@ func (v *Validator) Validate(data interface{}) error {
 
 	return nil
 }
+
+func (v *Validator) validateDetailedTimeline(doc *models.DetailedTimelineDocument) error {
+	if len(doc.Phases) == 0 && len(doc.LongTermTracking) == 0 {
+		return ErrNoPhases
+	}
+
+	return nil
+}
+
+func (v *Validator) validateInvestigation(doc *models.InvestigationDocument) error {
+	if doc.Info.IncidentID == "" {
+		return ErrMissingIncidentID
+	}
+
+	if doc.Info.Title == "" {
+		return ErrMissingTitle
+	}
+
+	if len(doc.Findings) == 0 {
+		return ErrNoFindings
+	}
+
+	for i, finding := range doc.Findings {
+		if finding.ID == "" {
+			return fmt.Errorf("%w at index %d", ErrFindingMissingID, i)
+		}
+
+		if finding.Date == "" {
+			return fmt.Errorf("%w at index %d", ErrFindingMissingDate, i)
+		}
+	}
+
+	return nil
+}
+
+func (v *Validator) validateResponses(doc *models.ResponsesDocument) error {
+	if doc.Info.IncidentID == "" {
+		return ErrMissingIncidentID
+	}
+
+	if len(doc.Responses) == 0 {
+		return ErrNoResponses
+	}
+
+	for i, response := range doc.Responses {
+		if response.ID == "" {
+			return fmt.Errorf("%w at index %d", ErrResponseMissingID, i)
+		}
+
+		if response.Date == "" {
+			return fmt.Errorf("%w at index %d", ErrResponseMissingDate, i)
+		}
+
+		if response.Organization == "" {
+			return fmt.Errorf("%w at index %d", ErrResponseMissingOrg, i)
+		}
+	}
+
+	return nil
+}
@@ -1,6 +1,7 @@
 package normalizer
 
 import (
+	"errors"
 	"strings"
 	"testing"
 
@@ -95,6 +96,31 @@ func TestValidator_Validate_Errors(t *testing.T) {
 			},
 			wantErr: "contains no sources",
 		},
+		{
+			name:    "Investigation without title",
+			data:    &models.InvestigationDocument{Info: models.InvestigationInfo{IncidentID: "id"}},
+			wantErr: "missing title",
+		},
+		{
+			name: "Investigation without findings",
+			data: &models.InvestigationDocument{
+				Info: models.InvestigationInfo{IncidentID: "id", Title: "Inquiry"},
+			},
+			wantErr: "contains no findings",
+		},
+		{
+			name:    "Responses without incident ID",
+			data:    &models.ResponsesDocument{},
+			wantErr: "missing incident ID",
+		},
+		{
+			name: "Response without organization",
+			data: &models.ResponsesDocument{
+				Info:      models.ResponsesInfo{IncidentID: "id"},
+				Responses: []models.FireResponse{{ID: "r-1", Date: "2025-01-02"}},
+			},
+			wantErr: "response missing organization at index 0",
+		},
 	}
 
 	for _, tt := range tests {
@@ -108,3 +134,36 @@ func TestValidator_Validate_Errors(t *testing.T) {
 		})
 	}
 }
+
+func TestValidator_Validate_InvestigationAndResponses(t *testing.T) {
+	v := NewValidator()
+
+	investigation := &models.InvestigationDocument{
+		Info:     models.InvestigationInfo{IncidentID: "id", Title: "Inquiry"},
+		Findings: []models.InvestigationFinding{{ID: "f-1", Date: "2025-01-03", Finding: "Netting"}},
+	}
+	if err := v.Validate(investigation); err != nil {
+		t.Errorf("Validate(investigation) = %v", err)
+	}
+
+	responses := &models.ResponsesDocument{
+		Info:      models.ResponsesInfo{IncidentID: "id"},
+		Responses: []models.FireResponse{{ID: "r-1", Date: "2025-01-02", Organization: "Government"}},
+	}
+	if err := v.Validate(responses); err != nil {
+		t.Errorf("Validate(responses) = %v", err)
+	}
+}
+
+func TestValidator_Validate_DetailedTimeline(t *testing.T) {
+	v := NewValidator()
+
+	if err := v.Validate(&models.DetailedTimelineDocument{}); !errors.Is(err, ErrNoPhases) {
+		t.Errorf("Validate(empty) = %v, want ErrNoPhases", err)
+	}
+
+	doc := &models.DetailedTimelineDocument{LongTermTracking: []models.LongTermTrackingEvent{{Event: "Review"}}}
+	if err := v.Validate(doc); err != nil {
+		t.Errorf("Validate(tracking only) = %v", err)
+	}
+}
@@ -3,6 +3,7 @@ package payload
 
 import (
 	"bytes"
+	"context"
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/hex"
@@ -12,10 +13,13 @@ import (
 	"io"
 	"net/http"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
+	"tpwfc/internal/config"
 	"tpwfc/internal/logger"
+	"tpwfc/pkg/utils"
 )
 
 // GraphQL errors.
@@ -29,6 +33,7 @@ var (
 // Client defines the interface for GraphQL communication.
 type Client interface {
 	Execute(query string, variables map[string]interface{}) (*GraphQLResponse, error)
+	ExecuteContext(ctx context.Context, query string, variables map[string]interface{}) (*GraphQLResponse, error)
 	Login(email, password string) error
 }
 
@@ -42,6 +47,7 @@ type GraphQLClient struct {
 	apiKey        string
 	authToken     string
 	signingSecret string
+	retryPolicy   *config.RetryPolicy
 	mu            sync.RWMutex
 	logger        *logger.Logger
 }
@@ -74,7 +80,9 @@ func NewGraphQLClient(endpoint, apiKey string, log *logger.Logger) *GraphQLClien
 		endpoint: endpoint,
 		apiKey:   apiKey,
 		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
+			// Uploads send many small mutations; share the pooled keep-alive connections
+			Transport: utils.NewDecodingTransport(utils.SharedTransport(), 0),
+			Timeout:   30 * time.Second,
 		},
 		logger: log,
 	}
@@ -87,6 +95,15 @@ func (c *GraphQLClient) SetSigningSecret(secret string) {
 	c.signingSecret = secret
 }
 
+// SetRetryPolicy enables retries of requests rejected with a temporary status (429, 503, ...).
+// Transport errors are not retried, since a mutation may already have been applied; for the
+// same reason mutations are only retried on 429 and 503, never on timeouts (408, 504).
+func (c *GraphQLClient) SetRetryPolicy(policy *config.RetryPolicy) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.retryPolicy = policy
+}
+
 // generateSignature creates HMAC-SHA256 signature.
 // Signs only the nonce to avoid JSON serialization differences between Go and TS.
 func generateSignature(nonce, secret string) string {
@@ -97,6 +114,11 @@ func generateSignature(nonce, secret string) string {
 
 // Execute sends a GraphQL request and returns the response.
 func (c *GraphQLClient) Execute(query string, variables map[string]interface{}) (*GraphQLResponse, error) {
+	return c.ExecuteContext(context.Background(), query, variables)
+}
+
+// ExecuteContext sends a GraphQL request bound to ctx and returns the response.
+func (c *GraphQLClient) ExecuteContext(ctx context.Context, query string, variables map[string]interface{}) (*GraphQLResponse, error) {
 	if c.logger != nil {
 		c.logger.Debug(fmt.Sprintf("Executing GraphQL query: %s...", query[:min(len(query), 50)]))
 	}
@@ -111,9 +133,82 @@ func (c *GraphQLClient) Execute(query string, variables map[string]interface{})
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	req, err := http.NewRequest(http.MethodPost, c.endpoint, bytes.NewBuffer(jsonBody))
+	c.mu.RLock()
+	retryPolicy := c.retryPolicy
+	c.mu.RUnlock()
+
+	retryable := utils.IsRetryableStatus
+	if isMutation(query) {
+		retryable = isRejectedStatus
+	}
+
+	maxAttempts := 1
+	if retryPolicy != nil {
+		maxAttempts = max(retryPolicy.MaxAttempts, 1)
+	}
+
+	var (
+		body       []byte
+		statusCode int
+		retryAfter time.Duration
+	)
+
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		if attempt > 1 {
+			if err := utils.SleepContext(ctx, retryPolicy.BackoffDelay(attempt-1, retryAfter)); err != nil {
+				return nil, fmt.Errorf("retry wait cancelled after status %d: %w", statusCode, err)
+			}
+		}
+
+		body, statusCode, retryAfter, err = c.do(ctx, jsonBody)
+		if err != nil {
+			return nil, err
+		}
+
+		if !retryable(statusCode) {
+			break
+		}
+
+		if c.logger != nil && attempt < maxAttempts {
+			c.logger.Warn(fmt.Sprintf("GraphQL request throttled with status %d (attempt %d/%d), retrying", statusCode, attempt, maxAttempts))
+		}
+	}
+
+	if statusCode != http.StatusOK {
+		if c.logger != nil {
+			c.logger.Error(fmt.Sprintf("GraphQL request failed with status %d: %s", statusCode, string(body)))
+		}
+		return nil, fmt.Errorf("%w: %d: %s", ErrUnexpectedStatusCode, statusCode, string(body))
+	}
+
+	var gqlResp GraphQLResponse
+	if err := json.Unmarshal(body, &gqlResp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	if len(gqlResp.Errors) > 0 {
+		return &gqlResp, fmt.Errorf("%w: %s", ErrGraphQLError, gqlResp.Errors[0].Message)
+	}
+
+	return &gqlResp, nil
+}
+
+// isMutation reports whether a GraphQL document is a mutation operation.
+func isMutation(query string) bool {
+	return strings.HasPrefix(strings.TrimSpace(query), "mutation")
+}
+
+// isRejectedStatus reports whether a status means the server turned the request away before
+// processing it, so a mutation can be resent without risking a duplicate write.
+func isRejectedStatus(statusCode int) bool {
+	return statusCode == http.StatusTooManyRequests || statusCode == http.StatusServiceUnavailable
+}
+
+// do sends one signed request and returns the body, status code and any Retry-After hint.
+func (c *GraphQLClient) do(ctx context.Context, jsonBody []byte) (body []byte, statusCode int, retryAfter time.Duration, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, bytes.NewReader(jsonBody))
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, 0, 0, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
@@ -132,7 +227,7 @@ func (c *GraphQLClient) Execute(query string, variables map[string]interface{})
 		req.Header.Set("Authorization", key)
 	}
 
-	// Add HMAC signature if signing secret is configured
+	// Add HMAC signature if signing secret is configured; a fresh nonce per attempt
 	if signingSecret != "" {
 		nonce := strconv.FormatInt(time.Now().UnixMilli(), 10)
 		signature := generateSignature(nonce, signingSecret)
@@ -142,7 +237,7 @@ func (c *GraphQLClient) Execute(query string, variables map[string]interface{})
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
+		return nil, 0, 0, fmt.Errorf("request failed: %w", err)
 	}
 	defer func() {
 		if closeErr := resp.Body.Close(); closeErr != nil && err == nil {
@@ -153,28 +248,14 @@ func (c *GraphQLClient) Execute(query string, variables map[string]interface{})
 	// Limit response size to 10MB
 	reader := io.LimitReader(resp.Body, 10*1024*1024)
 
-	body, err := io.ReadAll(reader)
+	body, err = io.ReadAll(reader)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
+		return nil, 0, 0, fmt.Errorf("failed to read response: %w", err)
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		if c.logger != nil {
-			c.logger.Error(fmt.Sprintf("GraphQL request failed with status %d: %s", resp.StatusCode, string(body)))
-		}
-		return nil, fmt.Errorf("%w: %d: %s", ErrUnexpectedStatusCode, resp.StatusCode, string(body))
-	}
+	retryAfter, _ = utils.ParseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
 
-	var gqlResp GraphQLResponse
-	if err := json.Unmarshal(body, &gqlResp); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
-	}
-
-	if len(gqlResp.Errors) > 0 {
-		return &gqlResp, fmt.Errorf("%w: %s", ErrGraphQLError, gqlResp.Errors[0].Message)
-	}
-
-	return &gqlResp, nil
+	return body, resp.StatusCode, retryAfter, nil
 }
 
 // UnmarshalGraphQLData unmarshals the response data into the target struct.
@@ -422,3 +503,105 @@ query FindLongTermTracking($trackingId: String!) {
   }
 }
 `
+
+// Fire Investigation mutations and queries
+
+// CreateFireInvestigationMutation creates a new fire investigation.
+const CreateFireInvestigationMutation = `
+mutation CreateFireInvestigation($data: mutationFireInvestigationInput!, $locale: LocaleInputType) {
+  createFireInvestigation(data: $data, locale: $locale) {
+    id
+    investigationId
+  }
+}
+`
+
+// UpdateFireInvestigationMutation updates an existing fire investigation.
+const UpdateFireInvestigationMutation = `
+mutation UpdateFireInvestigation($id: Int!, $data: mutationFireInvestigationUpdateInput!, $locale: LocaleInputType) {
+  updateFireInvestigation(id: $id, data: $data, locale: $locale) {
+    id
+    investigationId
+  }
+}
+`
+
+// FindFireInvestigationQuery finds a fire investigation by investigation ID.
+const FindFireInvestigationQuery = `
+query FindFireInvestigation($investigationId: String!) {
+  FireInvestigations(where: { investigationId: { equals: $investigationId } }, limit: 1) {
+    docs {
+      id
+      investigationId
+    }
+  }
+}
+`
+
+// Investigation Finding mutations and queries
+
+// CreateInvestigationFindingMutation creates a new investigation finding.
+const CreateInvestigationFindingMutation = `
+mutation CreateInvestigationFinding($data: mutationInvestigationFindingInput!, $locale: LocaleInputType) {
+  createInvestigationFinding(data: $data, locale: $locale) {
+    id
+    findingId
+  }
+}
+`
+
+// UpdateInvestigationFindingMutation updates an existing investigation finding.
+const UpdateInvestigationFindingMutation = `
+mutation UpdateInvestigationFinding($id: Int!, $data: mutationInvestigationFindingUpdateInput!, $locale: LocaleInputType) {
+  updateInvestigationFinding(id: $id, data: $data, locale: $locale) {
+    id
+    findingId
+  }
+}
+`
+
+// FindInvestigationFindingQuery finds an investigation finding by finding ID.
+const FindInvestigationFindingQuery = `
+query FindInvestigationFinding($findingId: String!) {
+  InvestigationFindings(where: { findingId: { equals: $findingId } }, limit: 1) {
+    docs {
+      id
+      findingId
+    }
+  }
+}
+`
+
+// Fire Response mutations and queries
+
+// CreateFireResponseMutation creates a new fire response.
+const CreateFireResponseMutation = `
+mutation CreateFireResponse($data: mutationFireResponseInput!, $locale: LocaleInputType) {
+  createFireResponse(data: $data, locale: $locale) {
+    id
+    responseId
+  }
+}
+`
+
+// UpdateFireResponseMutation updates an existing fire response.
+const UpdateFireResponseMutation = `
+mutation UpdateFireResponse($id: Int!, $data: mutationFireResponseUpdateInput!, $locale: LocaleInputType) {
+  updateFireResponse(id: $id, data: $data, locale: $locale) {
+    id
+    responseId
+  }
+}
+`
+
+// FindFireResponseQuery finds a fire response by response ID.
+const FindFireResponseQuery = `
+query FindFireResponse($responseId: String!) {
+  FireResponses(where: { responseId: { equals: $responseId } }, limit: 1) {
+    docs {
+      id
+      responseId
+    }
+  }
+}
+`
@@ -0,0 +1,79 @@
+package payload
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"tpwfc/internal/config"
+)
+
+func TestGraphQLClient_RetriesThrottledRequests(t *testing.T) {
+	requests := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests++
+
+		if requests == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"data": {"ok": true}}`))
+	}))
+	defer server.Close()
+
+	client := NewGraphQLClient(server.URL, "", nil)
+	client.SetRetryPolicy(&config.RetryPolicy{MaxAttempts: 3, BackoffMultiplier: 1, Jitter: config.JitterFull})
+
+	if _, err := client.Execute("{ ok }", nil); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	if requests != 2 {
+		t.Errorf("Expected 2 requests, got %d", requests)
+	}
+}
+
+func TestGraphQLClient_NoRetryWithoutPolicy(t *testing.T) {
+	requests := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := NewGraphQLClient(server.URL, "", nil)
+
+	if _, err := client.Execute("{ ok }", nil); err == nil {
+		t.Fatal("Expected error for 503 response")
+	}
+
+	if requests != 1 {
+		t.Errorf("Expected 1 request, got %d", requests)
+	}
+}
+
+func TestGraphQLClient_NoRetryOfTimedOutMutation(t *testing.T) {
+	requests := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusGatewayTimeout)
+	}))
+	defer server.Close()
+
+	client := NewGraphQLClient(server.URL, "", nil)
+	client.SetRetryPolicy(&config.RetryPolicy{MaxAttempts: 3, BackoffMultiplier: 1, Jitter: config.JitterFull})
+
+	if _, err := client.Execute(CreateFireEventMutation, map[string]interface{}{"data": map[string]interface{}{}}); err == nil {
+		t.Fatal("Expected error for 504 response")
+	}
+
+	if requests != 1 {
+		t.Errorf("Expected 1 request for a create that timed out, got %d", requests)
+	}
+}
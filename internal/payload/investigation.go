@@ -0,0 +1,226 @@
+package payload
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"tpwfc/internal/models"
+)
+
+// ErrInfoIncidentIDRequired is returned when an investigation or responses document has no incident ID.
+var ErrInfoIncidentIDRequired = errors.New("info.incidentId is required")
+
+// UploadInvestigationResult contains the results of an investigation upload.
+type UploadInvestigationResult struct {
+	Errors               []error
+	InvestigationID      int
+	InvestigationCreated bool
+	FindingsCreated      int
+	FindingsUpdated      int
+}
+
+// UploadInvestigation uploads an investigation and its findings to Payload CMS.
+func (u *Uploader) UploadInvestigation(doc *models.InvestigationDocument, incidentID int, language string) (*UploadInvestigationResult, error) {
+	return u.UploadInvestigationContext(context.Background(), doc, incidentID, language)
+}
+
+// UploadInvestigationContext is UploadInvestigation bound to ctx.
+// The investigation is upserted first, then its findings concurrently.
+func (u *Uploader) UploadInvestigationContext(ctx context.Context, doc *models.InvestigationDocument, incidentID int, language string) (*UploadInvestigationResult, error) {
+	if doc.Info.IncidentID == "" {
+		return nil, ErrInfoIncidentIDRequired
+	}
+
+	result := &UploadInvestigationResult{}
+	locale := u.mapLocale(language)
+
+	investigationID, created, err := u.uploadInvestigation(ctx, doc, incidentID, locale)
+	if err != nil {
+		return result, fmt.Errorf("failed to upload investigation: %w", err)
+	}
+
+	result.InvestigationID = investigationID
+	result.InvestigationCreated = created
+
+	uploadConcurrent(ctx, u, doc.Findings, func(finding models.InvestigationFinding) (bool, error) {
+		return u.uploadEntity(
+			ctx,
+			FindInvestigationFindingQuery,
+			CreateInvestigationFindingMutation,
+			UpdateInvestigationFindingMutation,
+			"findingId",
+			"InvestigationFindings",
+			finding.ID,
+			u.mapToFinding(finding, investigationID),
+			locale,
+		)
+	}, func(created bool) {
+		if created {
+			result.FindingsCreated++
+		} else {
+			result.FindingsUpdated++
+		}
+	}, "Failed to upload finding", &result.Errors)
+
+	if err := ctx.Err(); err != nil {
+		return result, fmt.Errorf("%w: %w", ErrUploadInterrupted, err)
+	}
+
+	return result, nil
+}
+
+// uploadInvestigation upserts the investigation record, returns its ID and whether it was created.
+func (u *Uploader) uploadInvestigation(ctx context.Context, doc *models.InvestigationDocument, incidentID int, locale string) (int, bool, error) {
+	existingID, err := u.findEntityID(ctx, FindFireInvestigationQuery, "investigationId", doc.Info.IncidentID, "FireInvestigations")
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to find existing investigation: %w", err)
+	}
+
+	variables := map[string]interface{}{
+		"data":   u.mapToInvestigation(doc, incidentID),
+		"locale": locale,
+	}
+
+	if existingID > 0 {
+		variables["id"] = existingID
+		_, err = u.client.ExecuteContext(ctx, UpdateFireInvestigationMutation, variables)
+		return existingID, false, err
+	}
+
+	resp, err := u.client.ExecuteContext(ctx, CreateFireInvestigationMutation, variables)
+	if err != nil {
+		return 0, false, err
+	}
+
+	var createResult struct {
+		CreateFireInvestigation struct {
+			ID int `json:"id"`
+		} `json:"createFireInvestigation"`
+	}
+
+	if err := json.Unmarshal(resp.Data, &createResult); err != nil {
+		return 0, false, fmt.Errorf("failed to parse create response: %w", err)
+	}
+
+	return createResult.CreateFireInvestigation.ID, true, nil
+}
+
+// UploadResponsesResult contains the results of a responses upload.
+type UploadResponsesResult struct {
+	Errors           []error
+	ResponsesCreated int
+	ResponsesUpdated int
+}
+
+// UploadResponses uploads government and organisational responses to Payload CMS.
+func (u *Uploader) UploadResponses(doc *models.ResponsesDocument, incidentID int, language string) (*UploadResponsesResult, error) {
+	return u.UploadResponsesContext(context.Background(), doc, incidentID, language)
+}
+
+// UploadResponsesContext is UploadResponses bound to ctx.
+// Once ctx is done no further responses are started.
+func (u *Uploader) UploadResponsesContext(ctx context.Context, doc *models.ResponsesDocument, incidentID int, language string) (*UploadResponsesResult, error) {
+	if doc.Info.IncidentID == "" {
+		return nil, ErrInfoIncidentIDRequired
+	}
+
+	result := &UploadResponsesResult{}
+	locale := u.mapLocale(language)
+
+	uploadConcurrent(ctx, u, doc.Responses, func(response models.FireResponse) (bool, error) {
+		return u.uploadEntity(
+			ctx,
+			FindFireResponseQuery,
+			CreateFireResponseMutation,
+			UpdateFireResponseMutation,
+			"responseId",
+			"FireResponses",
+			response.ID,
+			u.mapToResponse(response, incidentID),
+			locale,
+		)
+	}, func(created bool) {
+		if created {
+			result.ResponsesCreated++
+		} else {
+			result.ResponsesUpdated++
+		}
+	}, "Failed to upload response", &result.Errors)
+
+	if err := ctx.Err(); err != nil {
+		return result, fmt.Errorf("%w: %w", ErrUploadInterrupted, err)
+	}
+
+	return result, nil
+}
+
+func (u *Uploader) mapToInvestigation(doc *models.InvestigationDocument, incidentID int) FireInvestigation {
+	investigation := FireInvestigation{
+		InvestigationID: doc.Info.IncidentID,
+		FireIncident:    incidentID,
+		Title:           strPtr(doc.Info.Title),
+		Investigator:    strPtr(doc.Info.Investigator),
+		Status:          strPtr(doc.Info.Status),
+		StartDate:       strPtr(doc.Info.StartDate),
+		ReportDate:      strPtr(doc.Info.ReportDate),
+		Summary:         strPtr(doc.Summary),
+	}
+
+	for _, s := range doc.Sources {
+		investigation.Sources = append(investigation.Sources, Source{
+			Name:  strPtr(s.Name),
+			Title: strPtr(s.Title),
+			URL:   strPtr(s.URL),
+		})
+	}
+
+	for _, n := range doc.Notes {
+		investigation.Notes = append(investigation.Notes, Note{Content: strPtr(n)})
+	}
+
+	return investigation
+}
+
+func (u *Uploader) mapToFinding(finding models.InvestigationFinding, investigationID int) InvestigationFinding {
+	return InvestigationFinding{
+		FindingID:     finding.ID,
+		Investigation: investigationID,
+		Date:          finding.Date,
+		Category:      finding.Category,
+		Finding:       finding.Finding,
+		Status:        strPtr(finding.Status),
+		Sources:       mapEventSources(finding.Sources),
+	}
+}
+
+func (u *Uploader) mapToResponse(response models.FireResponse, incidentID int) FireResponse {
+	return FireResponse{
+		ResponseID:       response.ID,
+		FireIncident:     incidentID,
+		Date:             response.Date,
+		Organization:     response.Organization,
+		OrganizationType: strPtr(response.OrganizationType),
+		ResponseType:     strPtr(response.ResponseType),
+		Description:      response.Description,
+		Sources:          mapEventSources(response.Sources),
+	}
+}
+
+// mapEventSources maps row-level sources, or returns nil when there are none.
+func mapEventSources(sources []models.EventSource) []Source {
+	if len(sources) == 0 {
+		return nil
+	}
+
+	mapped := make([]Source, len(sources))
+	for i, s := range sources {
+		mapped[i] = Source{
+			Name: strPtr(s.Name),
+			URL:  strPtr(s.URL),
+		}
+	}
+
+	return mapped
+}
@@ -0,0 +1,120 @@
+package payload
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"tpwfc/internal/logger"
+	"tpwfc/internal/models"
+)
+
+func TestUploader_UploadInvestigation(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		findings []InvestigationFinding
+	)
+
+	mockClient := &MockClient{
+		ExecuteFunc: func(query string, variables map[string]interface{}) (*GraphQLResponse, error) {
+			switch query {
+			case FindFireInvestigationQuery:
+				return &GraphQLResponse{Data: json.RawMessage(`{"FireInvestigations": {"docs": []}}`)}, nil
+			case CreateFireInvestigationMutation:
+				return &GraphQLResponse{Data: json.RawMessage(`{"createFireInvestigation": {"id": 7}}`)}, nil
+			case FindInvestigationFindingQuery:
+				if variables["findingId"] == "f-2" {
+					return &GraphQLResponse{Data: json.RawMessage(`{"InvestigationFindings": {"docs": [{"id": 3}]}}`)}, nil
+				}
+
+				return &GraphQLResponse{Data: json.RawMessage(`{"InvestigationFindings": {"docs": []}}`)}, nil
+			case CreateInvestigationFindingMutation, UpdateInvestigationFindingMutation:
+				finding, ok := variables["data"].(InvestigationFinding)
+				if !ok {
+					return nil, fmt.Errorf("%w: data is %T", ErrUnexpectedQuery, variables["data"])
+				}
+
+				mu.Lock()
+				findings = append(findings, finding)
+				mu.Unlock()
+
+				return &GraphQLResponse{Data: json.RawMessage(`{}`)}, nil
+			}
+
+			return nil, fmt.Errorf("%w: %s", ErrUnexpectedQuery, query)
+		},
+	}
+
+	uploader := NewUploaderWithClient(mockClient, logger.NewLogger("error"))
+
+	doc := &models.InvestigationDocument{
+		Info: models.InvestigationInfo{IncidentID: "TEST_FIRE", Title: "Inquiry"},
+		Findings: []models.InvestigationFinding{
+			{ID: "f-1", Date: "2025-01-03", Category: "CAUSE", Finding: "Netting"},
+			{ID: "f-2", Date: "2025-01-03", Category: "CAUSE", Finding: "Foam"},
+		},
+	}
+
+	result, err := uploader.UploadInvestigation(doc, 100, "en")
+	if err != nil {
+		t.Fatalf("UploadInvestigation failed: %v", err)
+	}
+
+	if result.InvestigationID != 7 || !result.InvestigationCreated {
+		t.Errorf("Expected created investigation 7, got %+v", result)
+	}
+
+	if result.FindingsCreated != 1 || result.FindingsUpdated != 1 || len(result.Errors) != 0 {
+		t.Errorf("Expected 1 finding created and 1 updated, got %+v", result)
+	}
+
+	for _, finding := range findings {
+		if finding.Investigation != 7 {
+			t.Errorf("Finding %s linked to investigation %d, want 7", finding.FindingID, finding.Investigation)
+		}
+	}
+}
+
+func TestUploader_UploadResponses(t *testing.T) {
+	mockClient := &MockClient{
+		ExecuteFunc: func(query string, variables map[string]interface{}) (*GraphQLResponse, error) {
+			switch query {
+			case FindFireResponseQuery:
+				return &GraphQLResponse{Data: json.RawMessage(`{"FireResponses": {"docs": []}}`)}, nil
+			case CreateFireResponseMutation:
+				response, ok := variables["data"].(FireResponse)
+				if !ok || response.FireIncident != 100 || response.ResponseType == nil || *response.ResponseType != "RELIEF" {
+					return nil, fmt.Errorf("%w: data %+v", ErrUnexpectedQuery, variables["data"])
+				}
+
+				return &GraphQLResponse{Data: json.RawMessage(`{}`)}, nil
+			}
+
+			return nil, fmt.Errorf("%w: %s", ErrUnexpectedQuery, query)
+		},
+	}
+
+	uploader := NewUploaderWithClient(mockClient, logger.NewLogger("error"))
+
+	doc := &models.ResponsesDocument{
+		Info: models.ResponsesInfo{IncidentID: "TEST_FIRE"},
+		Responses: []models.FireResponse{
+			{ID: "r-1", Date: "2025-01-02", Organization: "Government", ResponseType: "RELIEF", Description: "Fund"},
+		},
+	}
+
+	result, err := uploader.UploadResponses(doc, 100, "en")
+	if err != nil {
+		t.Fatalf("UploadResponses failed: %v", err)
+	}
+
+	if result.ResponsesCreated != 1 || len(result.Errors) != 0 {
+		t.Errorf("Expected 1 response created, got %+v", result)
+	}
+
+	if _, err := uploader.UploadResponses(&models.ResponsesDocument{}, 100, "en"); !errors.Is(err, ErrInfoIncidentIDRequired) {
+		t.Errorf("Expected ErrInfoIncidentIDRequired, got %v", err)
+	}
+}
@@ -0,0 +1,132 @@
+package payload
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+
+	"tpwfc/internal/models"
+)
+
+// ErrInvalidJSONL is returned when a JSONL stream does not follow the record layout.
+var ErrInvalidJSONL = errors.New("invalid JSONL timeline")
+
+// ReadTimelineJSONL reads the incident record at the start of a timeline JSONL stream and
+// returns it with an iterator over the event records that follow. Events are decoded as
+// the iterator advances, so a large file is never held in memory.
+func ReadTimelineJSONL(r io.Reader) (*models.Timeline, iter.Seq2[models.TimelineEvent, error], error) {
+	dec := json.NewDecoder(r)
+
+	header, err := decodeIncident(dec, &models.Timeline{})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	events := func(yield func(models.TimelineEvent, error) bool) {
+		for {
+			var rec models.JSONLRecord
+			if err := dec.Decode(&rec); err != nil {
+				if !errors.Is(err, io.EOF) {
+					yield(models.TimelineEvent{}, fmt.Errorf("failed to read JSONL record: %w", err))
+				}
+
+				return
+			}
+
+			if rec.Type != models.RecordEvent {
+				yield(models.TimelineEvent{}, fmt.Errorf("%w: unexpected %q record", ErrInvalidJSONL, rec.Type))
+
+				return
+			}
+
+			var event models.TimelineEvent
+			if err := json.Unmarshal(rec.Data, &event); err != nil {
+				yield(models.TimelineEvent{}, fmt.Errorf("failed to parse event record: %w", err))
+
+				return
+			}
+
+			if !yield(event, nil) {
+				return
+			}
+		}
+	}
+
+	return header, events, nil
+}
+
+// ReadDetailedTimelineJSONL reads a detailed timeline JSONL stream, attaching each phaseEvent
+// record to the phase with the same ID.
+func ReadDetailedTimelineJSONL(r io.Reader) (*DetailedTimelineData, error) {
+	dec := json.NewDecoder(r)
+
+	data, err := decodeIncident(dec, &DetailedTimelineData{})
+	if err != nil {
+		return nil, err
+	}
+
+	phaseIndex := make(map[string]int)
+
+	for {
+		var rec models.JSONLRecord
+		if err := dec.Decode(&rec); err != nil {
+			if errors.Is(err, io.EOF) {
+				return data, nil
+			}
+
+			return nil, fmt.Errorf("failed to read JSONL record: %w", err)
+		}
+
+		switch rec.Type {
+		case models.RecordPhase:
+			var phase models.DetailedTimelinePhase
+			if err := json.Unmarshal(rec.Data, &phase); err != nil {
+				return nil, fmt.Errorf("failed to parse phase record: %w", err)
+			}
+
+			phaseIndex[phase.ID] = len(data.Phases)
+			data.Phases = append(data.Phases, phase)
+		case models.RecordPhaseEvent:
+			idx, ok := phaseIndex[rec.PhaseID]
+			if !ok {
+				return nil, fmt.Errorf("%w: event for unknown phase %q", ErrInvalidJSONL, rec.PhaseID)
+			}
+
+			var event models.DetailedTimelineEvent
+			if err := json.Unmarshal(rec.Data, &event); err != nil {
+				return nil, fmt.Errorf("failed to parse phase event record: %w", err)
+			}
+
+			data.Phases[idx].Events = append(data.Phases[idx].Events, event)
+		case models.RecordTracking:
+			var tracking models.LongTermTrackingEvent
+			if err := json.Unmarshal(rec.Data, &tracking); err != nil {
+				return nil, fmt.Errorf("failed to parse tracking record: %w", err)
+			}
+
+			data.LongTermTracking = append(data.LongTermTracking, tracking)
+		default:
+			return nil, fmt.Errorf("%w: unexpected %q record", ErrInvalidJSONL, rec.Type)
+		}
+	}
+}
+
+// decodeIncident reads the first record, which must be the incident header, into header.
+func decodeIncident[T any](dec *json.Decoder, header *T) (*T, error) {
+	var rec models.JSONLRecord
+	if err := dec.Decode(&rec); err != nil {
+		return nil, fmt.Errorf("failed to read JSONL header: %w", err)
+	}
+
+	if rec.Type != models.RecordIncident {
+		return nil, fmt.Errorf("%w: first record is %q, want %q", ErrInvalidJSONL, rec.Type, models.RecordIncident)
+	}
+
+	if err := json.Unmarshal(rec.Data, header); err != nil {
+		return nil, fmt.Errorf("failed to parse incident record: %w", err)
+	}
+
+	return header, nil
+}
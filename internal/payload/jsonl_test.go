@@ -0,0 +1,59 @@
+package payload
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestReadTimelineJSONL_RequiresIncidentFirst(t *testing.T) {
+	input := `{"type":"event","data":{"id":"ev1"}}` + "\n"
+
+	if _, _, err := ReadTimelineJSONL(strings.NewReader(input)); !errors.Is(err, ErrInvalidJSONL) {
+		t.Errorf("Expected ErrInvalidJSONL, got %v", err)
+	}
+}
+
+func TestReadTimelineJSONL_UnexpectedRecord(t *testing.T) {
+	input := `{"type":"incident","data":{"basicInfo":{"incidentId":"FIRE001"}}}
+{"type":"event","data":{"id":"ev1"}}
+{"type":"phase","data":{"id":"p1"}}
+`
+
+	header, events, err := ReadTimelineJSONL(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ReadTimelineJSONL failed: %v", err)
+	}
+
+	if header.BasicInfo.IncidentID != "FIRE001" {
+		t.Errorf("Expected incident FIRE001, got %q", header.BasicInfo.IncidentID)
+	}
+
+	count := 0
+
+	var readErr error
+
+	for _, err := range events {
+		if err != nil {
+			readErr = err
+
+			break
+		}
+
+		count++
+	}
+
+	if count != 1 || !errors.Is(readErr, ErrInvalidJSONL) {
+		t.Errorf("Expected 1 event then ErrInvalidJSONL, got %d events and %v", count, readErr)
+	}
+}
+
+func TestReadDetailedTimelineJSONL_UnknownPhase(t *testing.T) {
+	input := `{"type":"incident","data":{}}
+{"type":"phaseEvent","phaseId":"missing","data":{"id":"e1"}}
+`
+
+	if _, err := ReadDetailedTimelineJSONL(strings.NewReader(input)); !errors.Is(err, ErrInvalidJSONL) {
+		t.Errorf("Expected ErrInvalidJSONL, got %v", err)
+	}
+}
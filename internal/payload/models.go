@@ -150,3 +150,43 @@ type LongTermTracking struct {
 	ID           int     `json:"id,omitempty"`
 	FireIncident int     `json:"fireIncident"`
 }
+
+// FireInvestigation represents the FireInvestigation collection.
+type FireInvestigation struct {
+	Title           *string  `json:"title,omitempty"`
+	Investigator    *string  `json:"investigator,omitempty"`
+	Status          *string  `json:"status,omitempty"`
+	StartDate       *string  `json:"startDate,omitempty"`
+	ReportDate      *string  `json:"reportDate,omitempty"`
+	Summary         *string  `json:"summary,omitempty"`
+	InvestigationID string   `json:"investigationId"`
+	Sources         []Source `json:"sources,omitempty"`
+	Notes           []Note   `json:"notes,omitempty"`
+	ID              int      `json:"id,omitempty"`
+	FireIncident    int      `json:"fireIncident"`
+}
+
+// InvestigationFinding represents the InvestigationFinding collection.
+type InvestigationFinding struct {
+	Status        *string  `json:"status,omitempty"`
+	FindingID     string   `json:"findingId"`
+	Date          string   `json:"date"`
+	Category      string   `json:"category"`
+	Finding       string   `json:"finding"`
+	Sources       []Source `json:"sources,omitempty"`
+	ID            int      `json:"id,omitempty"`
+	Investigation int      `json:"investigation"`
+}
+
+// FireResponse represents the FireResponse collection.
+type FireResponse struct {
+	OrganizationType *string  `json:"organizationType,omitempty"`
+	ResponseType     *string  `json:"responseType,omitempty"`
+	ResponseID       string   `json:"responseId"`
+	Date             string   `json:"date"`
+	Organization     string   `json:"organization"`
+	Description      string   `json:"description"`
+	Sources          []Source `json:"sources,omitempty"`
+	ID               int      `json:"id,omitempty"`
+	FireIncident     int      `json:"fireIncident"`
+}
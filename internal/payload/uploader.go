@@ -1,12 +1,15 @@
 package payload
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"iter"
 	"os"
 	"sync"
 
+	"tpwfc/internal/config"
 	"tpwfc/internal/logger"
 	"tpwfc/internal/models"
 )
@@ -14,6 +17,9 @@ import (
 var (
 	// ErrIncidentIDRequired is returned when the incident ID is missing in the basic info.
 	ErrIncidentIDRequired = errors.New("basicInfo.incidentId is required")
+
+	// ErrUploadInterrupted is returned when an upload stops early because its context is done.
+	ErrUploadInterrupted = errors.New("upload interrupted")
 )
 
 // Language/locale constants.
@@ -49,6 +55,13 @@ func (u *Uploader) SetSigningSecret(secret string) {
 	}
 }
 
+// SetRetryPolicy sets the retry policy used for throttled GraphQL requests.
+func (u *Uploader) SetRetryPolicy(policy *config.RetryPolicy) {
+	if gqlClient, ok := u.client.(*GraphQLClient); ok {
+		gqlClient.SetRetryPolicy(policy)
+	}
+}
+
 // NewUploaderWithClient creates a new uploader with a custom client (useful for testing).
 func NewUploaderWithClient(client Client, log *logger.Logger) *Uploader {
 	return &Uploader{
@@ -90,10 +103,36 @@ func LoadTimelineJSON(filePath string) (*models.Timeline, error) {
 // Upload uploads timeline data to Payload CMS.
 // All incident metadata (fireID, fireName, map info) is read from data.BasicInfo.
 func (u *Uploader) Upload(data *models.Timeline, language string) (*UploadResult, error) {
+	return u.UploadContext(context.Background(), data, language)
+}
+
+// UploadContext is Upload bound to ctx. Once ctx is done no further events are started,
+// and the partial result is returned together with the context error.
+func (u *Uploader) UploadContext(ctx context.Context, data *models.Timeline, language string) (*UploadResult, error) {
+	events := func(yield func(models.TimelineEvent, error) bool) {
+		for _, event := range data.Events {
+			if !yield(event, nil) {
+				return
+			}
+		}
+	}
+
+	return u.uploadEvents(ctx, data, events, len(data.Events), language)
+}
+
+// UploadStreamContext uploads the incident in header, then each event as it is produced by events
+// (see ReadTimelineJSONL). header.Summary.TotalEvents is only used for progress logging.
+// A decode error stops the upload after in-flight events finish.
+func (u *Uploader) UploadStreamContext(ctx context.Context, header *models.Timeline, events iter.Seq2[models.TimelineEvent, error], language string) (*UploadResult, error) {
+	return u.uploadEvents(ctx, header, events, header.Summary.TotalEvents, language)
+}
+
+// uploadEvents creates or finds the incident and uploads events concurrently.
+func (u *Uploader) uploadEvents(ctx context.Context, data *models.Timeline, events iter.Seq2[models.TimelineEvent, error], total int, language string) (*UploadResult, error) {
 	result := &UploadResult{}
 
 	// Step 1: Create or find fire incident
-	incidentID, err := u.createOrFindIncident(data, language)
+	incidentID, err := u.createOrFindIncident(ctx, data, language)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create/find incident: %w", err)
 	}
@@ -102,22 +141,33 @@ func (u *Uploader) Upload(data *models.Timeline, language string) (*UploadResult
 	u.logger.Info(fmt.Sprintf("Fire incident ready: id=%d, fireId=%s", incidentID, data.BasicInfo.IncidentID))
 
 	// Step 2: Upload events concurrently
-	u.logger.Info(fmt.Sprintf("Starting upload of %d events...", len(data.Events)))
+	u.logger.Info(fmt.Sprintf("Starting upload of %d events...", total))
 
 	var (
-		wg  sync.WaitGroup
-		mu  sync.Mutex
-		sem = make(chan struct{}, maxConcurrentUploads)
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		sem     = make(chan struct{}, maxConcurrentUploads)
+		readErr error
 	)
 
-	for i, event := range data.Events {
+	for event, err := range events {
+		if err != nil {
+			readErr = err
+
+			break
+		}
+
+		// Wait for a free upload slot before reading further, and stop pulling events once cancelled
+		if !acquire(ctx, sem) {
+			break
+		}
+
 		wg.Add(1)
-		go func(evt models.TimelineEvent, index int) {
+		go func(evt models.TimelineEvent) {
 			defer wg.Done()
-			sem <- struct{}{}
 			defer func() { <-sem }()
 
-			created, err := u.uploadEvent(evt, incidentID, language)
+			created, err := u.uploadEvent(ctx, evt, incidentID, language)
 
 			mu.Lock()
 			defer mu.Unlock()
@@ -136,24 +186,33 @@ func (u *Uploader) Upload(data *models.Timeline, language string) (*UploadResult
 
 			// Progress logging (rough estimate due to concurrency)
 			totalProcessed := result.EventsCreated + result.EventsUpdated + len(result.Errors)
-			if totalProcessed%10 == 0 || totalProcessed == len(data.Events) {
-				u.logger.Info(fmt.Sprintf("Upload progress: %d/%d", totalProcessed, len(data.Events)))
+			if totalProcessed%10 == 0 || totalProcessed == total {
+				u.logger.Info(fmt.Sprintf("Upload progress: %d/%d", totalProcessed, total))
 			}
-		}(event, i)
+		}(event)
 	}
 
 	wg.Wait()
+
+	if readErr != nil {
+		return result, fmt.Errorf("failed to read events: %w", readErr)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return result, fmt.Errorf("%w: %w", ErrUploadInterrupted, err)
+	}
+
 	return result, nil
 }
 
 // createOrFindIncident creates a new incident or finds an existing one.
-func (u *Uploader) createOrFindIncident(data *models.Timeline, language string) (int, error) {
+func (u *Uploader) createOrFindIncident(ctx context.Context, data *models.Timeline, language string) (int, error) {
 	fireID := data.BasicInfo.IncidentID
 	if fireID == "" {
 		return 0, ErrIncidentIDRequired
 	}
 
-	existingID, err := u.findEntityID(FindFireIncidentQuery, "fireId", fireID, "FireIncidents")
+	existingID, err := u.findEntityID(ctx, FindFireIncidentQuery, "fireId", fireID, "FireIncidents")
 	if err != nil {
 		return 0, fmt.Errorf("failed to find existing incident: %w", err)
 	}
@@ -167,7 +226,7 @@ func (u *Uploader) createOrFindIncident(data *models.Timeline, language string)
 
 	if existingID > 0 {
 		variables["id"] = existingID
-		_, err = u.client.Execute(UpdateFireIncidentMutation, variables)
+		_, err = u.client.ExecuteContext(ctx, UpdateFireIncidentMutation, variables)
 		if err != nil {
 			return 0, fmt.Errorf("failed to update incident: %w", err)
 		}
@@ -175,7 +234,7 @@ func (u *Uploader) createOrFindIncident(data *models.Timeline, language string)
 	}
 
 	// Create new incident
-	resp, err := u.client.Execute(CreateFireIncidentMutation, variables)
+	resp, err := u.client.ExecuteContext(ctx, CreateFireIncidentMutation, variables)
 	if err != nil {
 		return 0, err
 	}
@@ -194,8 +253,8 @@ func (u *Uploader) createOrFindIncident(data *models.Timeline, language string)
 }
 
 // uploadEvent uploads a single event, returns true if created, false if updated.
-func (u *Uploader) uploadEvent(event models.TimelineEvent, incidentID int, language string) (bool, error) {
-	existingID, err := u.findEntityID(FindFireEventQuery, "eventId", event.ID, "FireEvents")
+func (u *Uploader) uploadEvent(ctx context.Context, event models.TimelineEvent, incidentID int, language string) (bool, error) {
+	existingID, err := u.findEntityID(ctx, FindFireEventQuery, "eventId", event.ID, "FireEvents")
 	if err != nil {
 		return false, fmt.Errorf("failed to find existing event: %w", err)
 	}
@@ -209,11 +268,11 @@ func (u *Uploader) uploadEvent(event models.TimelineEvent, incidentID int, langu
 
 	if existingID > 0 {
 		variables["id"] = existingID
-		_, err = u.client.Execute(UpdateFireEventMutation, variables)
+		_, err = u.client.ExecuteContext(ctx, UpdateFireEventMutation, variables)
 		return false, err
 	}
 
-	_, err = u.client.Execute(CreateFireEventMutation, variables)
+	_, err = u.client.ExecuteContext(ctx, CreateFireEventMutation, variables)
 	return true, err
 }
 
@@ -239,13 +298,23 @@ type UploadDetailedTimelineResult struct {
 
 // UploadDetailedTimeline uploads detailed timeline data to Payload CMS.
 func (u *Uploader) UploadDetailedTimeline(data *DetailedTimelineData, incidentID int, language string) (*UploadDetailedTimelineResult, error) {
+	return u.UploadDetailedTimelineContext(context.Background(), data, incidentID, language)
+}
+
+// UploadDetailedTimelineContext is UploadDetailedTimeline bound to ctx.
+// Once ctx is done no further phases or entries are started.
+func (u *Uploader) UploadDetailedTimelineContext(ctx context.Context, data *DetailedTimelineData, incidentID int, language string) (*UploadDetailedTimelineResult, error) {
 	result := &UploadDetailedTimelineResult{}
 	locale := u.mapLocale(language)
 
 	// Step 1: Upload phases and their events
 	// Phases are processed sequentially to ensure order/dependencies, but events within phases can be concurrent
 	for i, phase := range data.Phases {
-		phaseID, created, err := u.uploadPhase(phase, incidentID, locale)
+		if err := ctx.Err(); err != nil {
+			return result, fmt.Errorf("%w: %w", ErrUploadInterrupted, err)
+		}
+
+		phaseID, created, err := u.uploadPhase(ctx, phase, incidentID, locale)
 		if err != nil {
 			u.logger.Error(fmt.Sprintf("Failed to upload phase %s: %v", phase.ID, err))
 			result.Errors = append(result.Errors, err)
@@ -259,7 +328,7 @@ func (u *Uploader) UploadDetailedTimeline(data *DetailedTimelineData, incidentID
 		}
 
 		// Upload events for this phase concurrently
-		u.uploadPhaseEventsConcurrent(phase.Events, phaseID, locale, result)
+		u.uploadPhaseEventsConcurrent(ctx, phase.Events, phaseID, locale, result)
 
 		if (i+1)%5 == 0 || i == len(data.Phases)-1 {
 			u.logger.Info(fmt.Sprintf("Phase upload progress: %d/%d", i+1, len(data.Phases)))
@@ -267,11 +336,15 @@ func (u *Uploader) UploadDetailedTimeline(data *DetailedTimelineData, incidentID
 	}
 
 	// Step 2: Upload long-term tracking events concurrently
-	u.uploadTrackingConcurrent(data.LongTermTracking, incidentID, locale, result)
+	u.uploadTrackingConcurrent(ctx, data.LongTermTracking, incidentID, locale, result)
+
+	if err := ctx.Err(); err != nil {
+		return result, fmt.Errorf("%w: %w", ErrUploadInterrupted, err)
+	}
 
 	// Step 3: Upload category metrics
 	if len(data.CategoryMetrics) > 0 {
-		if err := u.updateIncidentMetrics(incidentID, data.CategoryMetrics, locale); err != nil {
+		if err := u.updateIncidentMetrics(ctx, incidentID, data.CategoryMetrics, locale); err != nil {
 			u.logger.Error(fmt.Sprintf("Failed to upload category metrics: %v", err))
 			result.Errors = append(result.Errors, err)
 		} else {
@@ -283,37 +356,53 @@ func (u *Uploader) UploadDetailedTimeline(data *DetailedTimelineData, incidentID
 	return result, nil
 }
 
-func (u *Uploader) uploadPhaseEventsConcurrent(events []models.DetailedTimelineEvent, phaseID int, locale string, result *UploadDetailedTimelineResult) {
-	uploadConcurrent(u, events, func(evt models.DetailedTimelineEvent) (bool, error) {
-		return u.uploadDetailedTimelineEvent(evt, phaseID, locale)
+func (u *Uploader) uploadPhaseEventsConcurrent(ctx context.Context, events []models.DetailedTimelineEvent, phaseID int, locale string, result *UploadDetailedTimelineResult) {
+	uploadConcurrent(ctx, u, events, func(evt models.DetailedTimelineEvent) (bool, error) {
+		return u.uploadDetailedTimelineEvent(ctx, evt, phaseID, locale)
 	}, func(created bool) {
 		if created {
 			result.EventsCreated++
 		} else {
 			result.EventsUpdated++
 		}
-	}, "Failed to upload detailed event", result)
+	}, "Failed to upload detailed event", &result.Errors)
 }
 
-func (u *Uploader) uploadTrackingConcurrent(trackingEvents []models.LongTermTrackingEvent, incidentID int, locale string, result *UploadDetailedTimelineResult) {
-	uploadConcurrent(u, trackingEvents, func(evt models.LongTermTrackingEvent) (bool, error) {
-		return u.uploadLongTermTracking(evt, incidentID, locale)
+func (u *Uploader) uploadTrackingConcurrent(ctx context.Context, trackingEvents []models.LongTermTrackingEvent, incidentID int, locale string, result *UploadDetailedTimelineResult) {
+	uploadConcurrent(ctx, u, trackingEvents, func(evt models.LongTermTrackingEvent) (bool, error) {
+		return u.uploadLongTermTracking(ctx, evt, incidentID, locale)
 	}, func(created bool) {
 		if created {
 			result.TrackingCreated++
 		} else {
 			result.TrackingUpdated++
 		}
-	}, "Failed to upload tracking", result)
+	}, "Failed to upload tracking", &result.Errors)
+}
+
+// acquire takes a slot from sem, waiting while all are in use. It reports false, without taking
+// a slot, once ctx is done.
+func acquire(ctx context.Context, sem chan struct{}) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	select {
+	case sem <- struct{}{}:
+		return true
+	case <-ctx.Done():
+		return false
+	}
 }
 
 func uploadConcurrent[T any](
+	ctx context.Context,
 	u *Uploader,
 	items []T,
 	uploadFunc func(T) (bool, error),
 	onSuccess func(bool),
 	logPrefix string,
-	result *UploadDetailedTimelineResult,
+	errs *[]error,
 ) {
 	var (
 		wg  sync.WaitGroup
@@ -322,10 +411,13 @@ func uploadConcurrent[T any](
 	)
 
 	for _, item := range items {
+		if !acquire(ctx, sem) {
+			break
+		}
+
 		wg.Add(1)
 		go func(val T) {
 			defer wg.Done()
-			sem <- struct{}{}
 			defer func() { <-sem }()
 
 			created, err := uploadFunc(val)
@@ -335,7 +427,7 @@ func uploadConcurrent[T any](
 
 			if err != nil {
 				u.logger.Error(fmt.Sprintf("%s: %v", logPrefix, err))
-				result.Errors = append(result.Errors, err)
+				*errs = append(*errs, err)
 				return
 			}
 			onSuccess(created)
@@ -345,9 +437,9 @@ func uploadConcurrent[T any](
 }
 
 // updateIncidentMetrics updates the fire incident with category metrics.
-func (u *Uploader) updateIncidentMetrics(incidentID int, metrics []models.CategoryMetric, locale string) error {
+func (u *Uploader) updateIncidentMetrics(ctx context.Context, incidentID int, metrics []models.CategoryMetric, locale string) error {
 	// First, fetch the existing incident to get the map data
-	resp, err := u.client.Execute(GetFireIncidentByIDQuery, map[string]interface{}{
+	resp, err := u.client.ExecuteContext(ctx, GetFireIncidentByIDQuery, map[string]interface{}{
 		"id": incidentID,
 	})
 	if err != nil {
@@ -394,13 +486,13 @@ func (u *Uploader) updateIncidentMetrics(incidentID int, metrics []models.Catego
 		"locale": locale,
 	}
 
-	_, err = u.client.Execute(UpdateFireIncidentMutation, variables)
+	_, err = u.client.ExecuteContext(ctx, UpdateFireIncidentMutation, variables)
 	return err
 }
 
 // uploadPhase uploads a single phase, returns phaseID, created flag, error.
-func (u *Uploader) uploadPhase(phase models.DetailedTimelinePhase, incidentID int, locale string) (int, bool, error) {
-	existingID, err := u.findEntityID(FindDetailedTimelinePhaseQuery, "phaseId", phase.ID, "DetailedTimelinePhases")
+func (u *Uploader) uploadPhase(ctx context.Context, phase models.DetailedTimelinePhase, incidentID int, locale string) (int, bool, error) {
+	existingID, err := u.findEntityID(ctx, FindDetailedTimelinePhaseQuery, "phaseId", phase.ID, "DetailedTimelinePhases")
 	if err != nil {
 		return 0, false, fmt.Errorf("failed to find existing phase: %w", err)
 	}
@@ -413,11 +505,11 @@ func (u *Uploader) uploadPhase(phase models.DetailedTimelinePhase, incidentID in
 
 	if existingID > 0 {
 		variables["id"] = existingID
-		_, err = u.client.Execute(UpdateDetailedTimelinePhaseMutation, variables)
+		_, err = u.client.ExecuteContext(ctx, UpdateDetailedTimelinePhaseMutation, variables)
 		return existingID, false, err
 	}
 
-	resp, err := u.client.Execute(CreateDetailedTimelinePhaseMutation, variables)
+	resp, err := u.client.ExecuteContext(ctx, CreateDetailedTimelinePhaseMutation, variables)
 	if err != nil {
 		return 0, false, err
 	}
@@ -435,8 +527,9 @@ func (u *Uploader) uploadPhase(phase models.DetailedTimelinePhase, incidentID in
 	return createResult.CreateDetailedTimelinePhase.ID, true, nil
 }
 
-func (u *Uploader) uploadDetailedTimelineEvent(event models.DetailedTimelineEvent, phaseID int, locale string) (bool, error) {
+func (u *Uploader) uploadDetailedTimelineEvent(ctx context.Context, event models.DetailedTimelineEvent, phaseID int, locale string) (bool, error) {
 	return u.uploadEntity(
+		ctx,
 		FindDetailedTimelineEventQuery,
 		CreateDetailedTimelineEventMutation,
 		UpdateDetailedTimelineEventMutation,
@@ -448,8 +541,9 @@ func (u *Uploader) uploadDetailedTimelineEvent(event models.DetailedTimelineEven
 	)
 }
 
-func (u *Uploader) uploadLongTermTracking(tracking models.LongTermTrackingEvent, incidentID int, locale string) (bool, error) {
+func (u *Uploader) uploadLongTermTracking(ctx context.Context, tracking models.LongTermTrackingEvent, incidentID int, locale string) (bool, error) {
 	return u.uploadEntity(
+		ctx,
 		FindLongTermTrackingQuery,
 		CreateLongTermTrackingMutation,
 		UpdateLongTermTrackingMutation,
@@ -462,12 +556,13 @@ func (u *Uploader) uploadLongTermTracking(tracking models.LongTermTrackingEvent,
 }
 
 func (u *Uploader) uploadEntity(
+	ctx context.Context,
 	findQuery, createMutation, updateMutation, idKey, responseKey string,
 	entityID string,
 	data interface{},
 	locale string,
 ) (bool, error) {
-	existingID, err := u.findEntityID(findQuery, idKey, entityID, responseKey)
+	existingID, err := u.findEntityID(ctx, findQuery, idKey, entityID, responseKey)
 	if err != nil {
 		return false, fmt.Errorf("failed to find existing document: %w", err)
 	}
@@ -479,19 +574,19 @@ func (u *Uploader) uploadEntity(
 
 	if existingID > 0 {
 		variables["id"] = existingID
-		_, err = u.client.Execute(updateMutation, variables)
+		_, err = u.client.ExecuteContext(ctx, updateMutation, variables)
 		return false, err
 	}
 
-	_, err = u.client.Execute(createMutation, variables)
+	_, err = u.client.ExecuteContext(ctx, createMutation, variables)
 	return true, err
 }
 
 // --- Helpers ---
 
 // findEntityID performs a find query and returns the ID of the first document if found.
-func (u *Uploader) findEntityID(query, varName, varValue, responseKey string) (int, error) {
-	resp, err := u.client.Execute(query, map[string]interface{}{
+func (u *Uploader) findEntityID(ctx context.Context, query, varName, varValue, responseKey string) (int, error) {
+	resp, err := u.client.ExecuteContext(ctx, query, map[string]interface{}{
 		varName: varValue,
 	})
 	if err != nil {
@@ -1,10 +1,13 @@
 package payload
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"tpwfc/internal/logger"
 	"tpwfc/internal/models"
@@ -29,6 +32,10 @@ func (m *MockClient) Execute(query string, variables map[string]interface{}) (*G
 	return nil, nil
 }
 
+func (m *MockClient) ExecuteContext(_ context.Context, query string, variables map[string]interface{}) (*GraphQLResponse, error) {
+	return m.Execute(query, variables)
+}
+
 func (m *MockClient) Login(email, password string) error {
 	if m.LoginFunc != nil {
 		return m.LoginFunc(email, password)
@@ -154,3 +161,101 @@ func TestUploader_Authenticate(t *testing.T) {
 		t.Error("Login func was not called")
 	}
 }
+
+func TestUploader_UploadDetailedTimelineContext_Cancelled(t *testing.T) {
+	calls := 0
+	mockClient := &MockClient{
+		ExecuteFunc: func(query string, variables map[string]interface{}) (*GraphQLResponse, error) {
+			calls++
+
+			return nil, fmt.Errorf("%w: %s", ErrUnexpectedQuery, query)
+		},
+	}
+
+	uploader := NewUploaderWithClient(mockClient, logger.NewLogger("error"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	data := &DetailedTimelineData{
+		Phases: []models.DetailedTimelinePhase{{ID: "phase-1"}},
+	}
+
+	_, err := uploader.UploadDetailedTimelineContext(ctx, data, 100, "en")
+	if !errors.Is(err, ErrUploadInterrupted) || !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected ErrUploadInterrupted wrapping context.Canceled, got %v", err)
+	}
+
+	if calls != 0 {
+		t.Errorf("Expected no requests after cancellation, got %d", calls)
+	}
+}
+
+func TestUploader_UploadEvents_BoundsReads(t *testing.T) {
+	release := make(chan struct{})
+	mockClient := &MockClient{
+		ExecuteFunc: func(query string, variables map[string]interface{}) (*GraphQLResponse, error) {
+			if query == FindFireIncidentQuery {
+				return &GraphQLResponse{Data: json.RawMessage(`{"FireIncidents": {"docs": [{"id": 100}]}}`)}, nil
+			}
+
+			if query == UpdateFireIncidentMutation {
+				return &GraphQLResponse{Data: json.RawMessage(`{"updateFireIncident": {"id": 100}}`)}, nil
+			}
+
+			// Hold every event upload until the test has checked how far the stream was read
+			<-release
+
+			return nil, fmt.Errorf("%w: %s", ErrUnexpectedQuery, query)
+		},
+	}
+
+	uploader := NewUploaderWithClient(mockClient, logger.NewLogger("error"))
+
+	var pulls atomic.Int64
+
+	events := func(yield func(models.TimelineEvent, error) bool) {
+		for i := range 1000 {
+			pulls.Add(1)
+
+			if !yield(models.TimelineEvent{ID: fmt.Sprintf("ev%d", i)}, nil) {
+				return
+			}
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan error, 1)
+
+	go func() {
+		_, err := uploader.uploadEvents(ctx, &models.Timeline{BasicInfo: models.BasicInfo{IncidentID: "test-fire-id"}}, events, 1000, "en")
+		done <- err
+	}()
+
+	// One event per upload slot, plus the one waiting for a slot
+	want := int64(maxConcurrentUploads + 1)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for pulls.Load() < want && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+
+	if got := pulls.Load(); got != want {
+		t.Errorf("read %d events while uploads were busy, want %d", got, want)
+	}
+
+	cancel()
+	close(release)
+
+	if err := <-done; !errors.Is(err, ErrUploadInterrupted) {
+		t.Errorf("uploadEvents() error = %v, want ErrUploadInterrupted", err)
+	}
+
+	if got := pulls.Load(); got != want {
+		t.Errorf("read %d events in total, want reading to stop at %d after cancellation", got, want)
+	}
+}
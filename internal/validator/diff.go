@@ -0,0 +1,122 @@
+package validator
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+)
+
+// ValidationDiff lists what changed between two validations of the same document.
+type ValidationDiff struct {
+	Fixed              []ValidationError
+	Introduced         []ValidationError
+	WarningsFixed      []string
+	WarningsIntroduced []string
+}
+
+// Empty reports whether the two validations found the same problems.
+func (d *ValidationDiff) Empty() bool {
+	return len(d.Fixed) == 0 && len(d.Introduced) == 0 &&
+		len(d.WarningsFixed) == 0 && len(d.WarningsIntroduced) == 0
+}
+
+// Diff compares r with an earlier result for the same document; a nil prev counts as clean.
+// Errors are matched by field, value and message rather than position, so inserting a row
+// above an existing error does not report it as both fixed and introduced.
+func (r *ValidationResult) Diff(prev *ValidationResult) *ValidationDiff {
+	if prev == nil {
+		prev = &ValidationResult{}
+	}
+
+	return &ValidationDiff{
+		Fixed:              missingFrom(prev.Errors, r.Errors, errorKey),
+		Introduced:         missingFrom(r.Errors, prev.Errors, errorKey),
+		WarningsFixed:      missingFrom(prev.Warnings, r.Warnings, warningKey),
+		WarningsIntroduced: missingFrom(r.Warnings, prev.Warnings, warningKey),
+	}
+}
+
+// FprintDiff writes the diff to w, "-" for fixed problems and "+" for new ones.
+func (d *ValidationDiff) FprintDiff(w io.Writer) {
+	printf := func(format string, args ...any) {
+		_, _ = fmt.Fprintf(w, format, args...)
+	}
+
+	if d.Empty() {
+		printf("🟰 No validation changes\n")
+
+		return
+	}
+
+	printf("🔀 Validation changes: %d fixed, %d new\n",
+		len(d.Fixed)+len(d.WarningsFixed), len(d.Introduced)+len(d.WarningsIntroduced))
+
+	for _, e := range d.Fixed {
+		printf("  - ✅ %s\n", describeError(e))
+	}
+
+	for _, warn := range d.WarningsFixed {
+		printf("  - ✅ %s\n", warn)
+	}
+
+	for _, e := range d.Introduced {
+		printf("  + ❌ %s\n", describeError(e))
+	}
+
+	for _, warn := range d.WarningsIntroduced {
+		printf("  + ⚠️  %s\n", warn)
+	}
+}
+
+// describeError renders an error on one line.
+func describeError(e ValidationError) string {
+	msg := e.Message
+	if e.Field != "" {
+		msg = "[" + e.Field + "] " + msg
+	}
+
+	if e.Value != "" {
+		msg += fmt.Sprintf(" (found %q)", e.Value)
+	}
+
+	if e.Line > 0 {
+		msg = fmt.Sprintf("Line %d: %s", e.Line, msg)
+	}
+
+	return msg
+}
+
+func errorKey(e ValidationError) string {
+	return e.Field + "\x00" + e.Value + "\x00" + e.Message
+}
+
+// linePrefix matches the "line N: " position prefix of a warning.
+var linePrefix = regexp.MustCompile(`(?i)^line \d+:\s*`)
+
+// warningKey matches warnings without their line, like errorKey, so a warning that only moved is not reported.
+func warningKey(warn string) string {
+	return linePrefix.ReplaceAllString(warn, "")
+}
+
+// missingFrom returns the items of a not matched by an item of b, counting duplicates.
+func missingFrom[T any](a, b []T, key func(T) string) []T {
+	counts := make(map[string]int, len(b))
+	for _, item := range b {
+		counts[key(item)]++
+	}
+
+	var missing []T
+
+	for _, item := range a {
+		k := key(item)
+		if counts[k] > 0 {
+			counts[k]--
+
+			continue
+		}
+
+		missing = append(missing, item)
+	}
+
+	return missing
+}
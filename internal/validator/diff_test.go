@@ -0,0 +1,68 @@
+package validator
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestValidationResult_Diff(t *testing.T) {
+	prev := &ValidationResult{
+		Errors: []ValidationError{
+			{Field: "time", Value: "25:00", Message: "invalid time format", Line: 10},
+			{Field: "description", Message: "description is required", Line: 12},
+		},
+		Warnings: []string{"Line 3: missing date header"},
+	}
+
+	// The time error moved down a line, the description was filled in, a new date error appeared
+	next := &ValidationResult{
+		Errors: []ValidationError{
+			{Field: "time", Value: "25:00", Message: "invalid time format", Line: 11},
+			{Field: "date", Value: "2025-13-01", Message: "invalid date format", Line: 14},
+		},
+		Warnings: []string{"line 4: missing date header"},
+	}
+
+	diff := next.Diff(prev)
+
+	if len(diff.Fixed) != 1 || diff.Fixed[0].Field != "description" {
+		t.Errorf("Fixed = %+v, want the description error", diff.Fixed)
+	}
+
+	if len(diff.Introduced) != 1 || diff.Introduced[0].Field != "date" {
+		t.Errorf("Introduced = %+v, want the date error", diff.Introduced)
+	}
+
+	if len(diff.WarningsFixed) != 0 || len(diff.WarningsIntroduced) != 0 {
+		t.Errorf("unexpected warning changes: %+v", diff)
+	}
+
+	var buf bytes.Buffer
+
+	diff.FprintDiff(&buf)
+
+	out := buf.String()
+	if !strings.Contains(out, "1 fixed, 1 new") || !strings.Contains(out, "+ ❌ Line 14: [date]") {
+		t.Errorf("unexpected diff output:\n%s", out)
+	}
+}
+
+func TestValidationResult_Diff_Duplicates(t *testing.T) {
+	dup := ValidationError{Field: "time", Message: "time is required"}
+	prev := &ValidationResult{Errors: []ValidationError{dup}}
+	next := &ValidationResult{Errors: []ValidationError{dup, dup}}
+
+	diff := next.Diff(prev)
+	if len(diff.Introduced) != 1 || len(diff.Fixed) != 0 {
+		t.Errorf("Diff = %+v, want one introduced duplicate", diff)
+	}
+
+	if d := next.Diff(next); !d.Empty() {
+		t.Errorf("Diff with itself = %+v, want empty", d)
+	}
+
+	if d := prev.Diff(nil); len(d.Introduced) != 1 {
+		t.Errorf("Diff against nil = %+v, want every error introduced", d)
+	}
+}
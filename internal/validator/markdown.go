@@ -5,11 +5,14 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"io"
+	"os"
 	"os/exec"
 	"regexp"
 	"strings"
 
 	"tpwfc/internal/config"
+	"tpwfc/internal/mdtable"
 )
 
 // Validation errors.
@@ -116,6 +119,7 @@ func (v *MarkdownValidator) ValidateMarkdown(markdown string) *ValidationResult
 	tableStarted := false
 	rowNumber := 0
 	var colMap map[string]int
+	var headerWidth int
 
 	for lineNum, line := range lines {
 		line = strings.TrimSpace(line)
@@ -126,48 +130,26 @@ func (v *MarkdownValidator) ValidateMarkdown(markdown string) *ValidationResult
 			continue
 		}
 
-		// Skip markdown table separators
-		if strings.HasPrefix(line, "|") && (strings.Contains(line, "---") || strings.Contains(line, ":---")) {
-			continue
-		}
-
 		// Check for Table Rows
 		if strings.HasPrefix(line, "|") {
-			// Split and clean cells
-			cells := strings.Split(line, "|")
-			var cleanCells []string
-			cleanCells = append(cleanCells, cells...)
-			if len(cleanCells) > 0 && strings.TrimSpace(cleanCells[0]) == "" {
-				cleanCells = cleanCells[1:]
-			}
-			if len(cleanCells) > 0 && strings.TrimSpace(cleanCells[len(cleanCells)-1]) == "" {
-				cleanCells = cleanCells[:len(cleanCells)-1]
+			row := mdtable.ParseRow(lines[lineNum])
+
+			// Skip markdown table separators
+			if row.IsSeparator() {
+				continue
 			}
 
 			// Check if this is a header row (Timeline table specific)
-			upperLine := strings.ToUpper(line)
-			isTimelineHeader := strings.Contains(upperLine, "DATE") &&
-				strings.Contains(upperLine, "TIME") &&
-				strings.Contains(upperLine, "EVENT")
+			header := row.Header()
+			_, hasDate := header[mdtable.ColDate]
+			_, hasTime := header[mdtable.ColTime]
+			_, hasEvent := header[mdtable.ColEvent]
 
-			if isTimelineHeader {
+			if hasDate && hasTime && hasEvent {
 				tableStarted = true
-				colMap = make(map[string]int)
-				for idx, cell := range cleanCells {
-					// Import NormalizeHeader from parsers if possible, but cyclic dependency might prevent it.
-					// We'll reimplement simple normalization or just string matching here since this is a validator.
-					// Ideally we should move NormalizeHeader to a shared package, but for now we'll duplicate simple logic.
-					h := strings.ToUpper(strings.TrimSpace(cell))
-					if strings.Contains(h, "DATE") {
-						colMap["DATE"] = idx
-					} else if strings.Contains(h, "TIME") {
-						colMap["TIME"] = idx
-					} else if strings.Contains(h, "EVENT") || strings.Contains(h, "DESCRIPTION") {
-						colMap["EVENT"] = idx
-					} else if strings.Contains(h, "END") {
-						colMap["END"] = idx
-					}
-				}
+				colMap = header
+				headerWidth = row.Len()
+
 				continue
 			}
 
@@ -180,7 +162,11 @@ func (v *MarkdownValidator) ValidateMarkdown(markdown string) *ValidationResult
 			rowNumber++
 			result.Stats.TotalRows++
 
-			rowError := v.validateRow(cleanCells, lineNum+1, colMap)
+			if err := row.CheckWidth(headerWidth); err != nil {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("line %d: %v", lineNum+1, err))
+			}
+
+			rowError := v.validateRow(row.Texts(), lineNum+1, colMap)
 			if len(rowError) > 0 {
 				result.IsValid = false
 				result.Stats.InvalidRows++
@@ -428,44 +414,58 @@ func (r *ValidationResult) String() string {
 
 // PrintErrors prints validation errors in readable format.
 func (r *ValidationResult) PrintErrors() {
+	r.FprintErrors(os.Stdout)
+}
+
+// FprintErrors writes validation errors in readable format to w.
+func (r *ValidationResult) FprintErrors(w io.Writer) {
 	if len(r.Errors) == 0 {
 		return
 	}
 
-	fmt.Println("❌ Validation Errors:")
+	printf := func(format string, args ...any) {
+		_, _ = fmt.Fprintf(w, format, args...)
+	}
+
+	printf("❌ Validation Errors:\n")
 
 	for _, err := range r.Errors {
 		if err.Line > 0 {
-			fmt.Printf("  Line %d, Col %d", err.Line, err.Column)
+			printf("  Line %d, Col %d", err.Line, err.Column)
 
 			if err.Field != "" {
-				fmt.Printf(" [%s]", err.Field)
+				printf(" [%s]", err.Field)
 			}
 
-			fmt.Printf(": %s\n", err.Message)
+			printf(": %s\n", err.Message)
 
 			if err.Value != "" {
-				fmt.Printf("    Found: %q\n", err.Value)
+				printf("    Found: %q\n", err.Value)
 			}
 
 			if err.Pattern != "" {
-				fmt.Printf("    Expected pattern: %s\n", err.Pattern)
+				printf("    Expected pattern: %s\n", err.Pattern)
 			}
 		} else {
-			fmt.Printf("  %s\n", err.Message)
+			printf("  %s\n", err.Message)
 		}
 	}
 }
 
 // PrintWarnings prints validation warnings.
 func (r *ValidationResult) PrintWarnings() {
+	r.FprintWarnings(os.Stdout)
+}
+
+// FprintWarnings writes validation warnings to w.
+func (r *ValidationResult) FprintWarnings(w io.Writer) {
 	if len(r.Warnings) == 0 {
 		return
 	}
 
-	fmt.Println("⚠️  Validation Warnings:")
+	_, _ = fmt.Fprintln(w, "⚠️  Validation Warnings:")
 
 	for _, warn := range r.Warnings {
-		fmt.Printf("  %s\n", warn)
+		_, _ = fmt.Fprintf(w, "  %s\n", warn)
 	}
 }
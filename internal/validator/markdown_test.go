@@ -258,6 +258,31 @@ func TestValidateMarkdown_TooFewColumns(t *testing.T) {
 	}
 }
 
+func TestValidateMarkdown_EscapedPipe(t *testing.T) {
+	cfg := createTestConfig(t)
+
+	v, err := NewMarkdownValidator(cfg)
+	if err != nil {
+		t.Fatalf("NewMarkdownValidator failed: %v", err)
+	}
+
+	markdown := `
+| 日期 | 時間 | 事件 |
+|------|------|------|
+| 2024-11-26 | 10:30 | 火警 \| 四級 |
+| 2024-11-26 | 10:45 | Extra | cell |
+`
+
+	result := v.ValidateMarkdown(markdown)
+	if !result.IsValid || result.Stats.ValidRows != 2 {
+		t.Errorf("Expected 2 valid rows, got %s: %+v", result, result.Errors)
+	}
+
+	if len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0], "line 5: column count mismatch") {
+		t.Errorf("Expected one column count warning for line 5, got %v", result.Warnings)
+	}
+}
+
 func TestValidateMarkdown_MinEventsNotMet(t *testing.T) {
 	cfg := createTestConfig(t)
 	cfg.Crawler.Validation.MinEvents = 5
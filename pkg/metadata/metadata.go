@@ -23,6 +23,8 @@ var (
 	ErrNoMetadataBlock = errors.New("no metadata block found")
 	ErrNoHashFound     = errors.New("no hash found in metadata")
 	ErrHashMismatch    = errors.New("hash mismatch")
+	ErrUnterminated    = errors.New("metadata block is not terminated")
+	ErrTruncated       = errors.New("document is cut off")
 )
 
 // Metadata contains the document status information.
@@ -119,6 +121,70 @@ func Sign(content string, validated bool, oldMeta *Metadata) string {
 	return clean + newBlock
 }
 
+// CheckComplete reports whether content looks cut off. The metadata block is written last, so
+// content that ends inside an HTML comment (including a partly written METADATA_START tag),
+// leaves a <!-- X_START --> section without its <!-- X_END -->, or opens the metadata block
+// without closing it was truncated. Only the structure is checked: a HASH that does not match
+// is left to Verify, since an edited document is complete and must reach the verification mode.
+func CheckComplete(content string) error {
+	trimmed := strings.TrimRight(content, " \t\r\n")
+
+	for n := len(TagStart) - 1; n > 0; n-- {
+		if strings.HasSuffix(trimmed, TagStart[:n]) {
+			return fmt.Errorf("%w: ends with %q", ErrTruncated, TagStart[:n])
+		}
+	}
+
+	start := strings.LastIndex(content, TagStart)
+	if start >= 0 && !strings.Contains(content[start:], TagEnd) {
+		return ErrUnterminated
+	}
+
+	if open := strings.LastIndex(content, "<!--"); open > strings.LastIndex(content, "-->") {
+		return fmt.Errorf("%w: comment at byte %d is not closed", ErrTruncated, open)
+	}
+
+	if section := openSection(content); section != "" {
+		return fmt.Errorf("%w: %s_START without %s_END", ErrTruncated, section, section)
+	}
+
+	return nil
+}
+
+// sectionRegex matches <!-- X_START --> and <!-- X_END --> section markers.
+var sectionRegex = regexp.MustCompile(`<!--\s*([A-Z][A-Z0-9_]*)_(START|END)\s*-->`)
+
+// openSection returns the name of a section whose last START marker is not followed by its END marker.
+func openSection(content string) string {
+	lastStart := make(map[string]int)
+	lastEnd := make(map[string]int)
+
+	var order []string
+
+	for _, m := range sectionRegex.FindAllStringSubmatchIndex(content, -1) {
+		name := content[m[2]:m[3]]
+		if content[m[4]:m[5]] == "END" {
+			lastEnd[name] = m[0]
+
+			continue
+		}
+
+		if _, seen := lastStart[name]; !seen {
+			order = append(order, name)
+		}
+
+		lastStart[name] = m[0]
+	}
+
+	for _, name := range order {
+		if end, ok := lastEnd[name]; !ok || end < lastStart[name] {
+			return name
+		}
+	}
+
+	return ""
+}
+
 // Verify checks if the content matches the hash in its metadata.
 func Verify(content string) (bool, error) {
 	meta, clean := Extract(content)
@@ -0,0 +1,76 @@
+package metadata
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+const document = `<!-- BASIC_INFO_START -->
+| KEY | VALUE |
+| --- | --- |
+| INCIDENT_ID | TEST_FIRE |
+<!-- BASIC_INFO_END -->
+
+<!-- TIMELINE_TABLE_START -->
+| DATE | TIME | DESCRIPTION |
+| --- | --- | --- |
+| 2025-01-01 | 12:00 | Alarm raised |
+<!-- TIMELINE_TABLE_END -->`
+
+func TestCheckComplete(t *testing.T) {
+	signed := Sign(document, true, nil)
+	block := strings.Index(signed, TagStart)
+
+	tests := []struct {
+		name    string
+		content string
+		want    error
+	}{
+		{"signed", signed, nil},
+		{"unsigned", document, nil},
+		// Cut between sections, a signed document looks like an unsigned one
+		{"cut before the metadata block", signed[:block], nil},
+		{"cut inside a section", document[:strings.Index(document, "<!-- TIMELINE_TABLE_END")], ErrTruncated},
+		{"cut inside a section marker", document[:len(document)-5], ErrTruncated},
+		{"cut inside the METADATA_START tag", signed[:block+len("<!-- METADA")], ErrTruncated},
+		{"cut inside the metadata block", signed[:len(signed)-len(TagEnd)-2], ErrUnterminated},
+		// A hash mismatch is for Verify and the verification mode to judge
+		{"edited after signing", strings.Replace(signed, "Alarm raised", "Alarm", 1), nil},
+		{"unsigned block", document + "\n" + TagStart + "\nVALIDATION: FALSE\n" + TagEnd, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckComplete(tt.content)
+			if tt.want == nil && err != nil {
+				t.Errorf("CheckComplete() error = %v, want nil", err)
+			}
+
+			if tt.want != nil && !errors.Is(err, tt.want) {
+				t.Errorf("CheckComplete() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerify(t *testing.T) {
+	signed := Sign(document, false, nil)
+
+	if ok, err := Verify(signed); !ok || err != nil {
+		t.Errorf("Verify(signed) = %v, %v", ok, err)
+	}
+
+	if _, err := Verify(document); !errors.Is(err, ErrNoMetadataBlock) {
+		t.Errorf("Verify(unsigned) error = %v, want ErrNoMetadataBlock", err)
+	}
+
+	if _, err := Verify(strings.Replace(signed, "Alarm", "Fire", 1)); !errors.Is(err, ErrHashMismatch) {
+		t.Errorf("Verify(edited) error = %v, want ErrHashMismatch", err)
+	}
+
+	meta, _ := Extract(signed)
+	if meta == nil || meta.Validation || meta.Hash != CalculateHash(document) {
+		t.Errorf("Extract() = %+v, want VALIDATION: FALSE and the document hash", meta)
+	}
+}
@@ -1,7 +1,12 @@
 // Package utils provides common utility functions.
 package utils
 
-import "net/http"
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
 
 // HTTPHelper provides HTTP utility functions.
 type HTTPHelper struct{}
@@ -18,17 +23,53 @@ func (h *HTTPHelper) IsValidURL(url string) bool {
 }
 
 // BuildHeaders creates HTTP headers with defaults.
+// Custom headers replace a default of the same name.
 func (h *HTTPHelper) BuildHeaders(customHeaders map[string]string) http.Header {
 	headers := http.Header{}
 
 	// Add default headers
-	headers.Add("User-Agent", "TPWFC-Worker/1.0")
-	headers.Add("Accept", "application/json, text/html")
+	headers.Set("User-Agent", "TPWFC-Worker/1.0")
+	headers.Set("Accept", "text/markdown, text/plain, application/json, text/html;q=0.9, */*;q=0.8")
 
 	// Add custom headers
 	for key, value := range customHeaders {
-		headers.Add(key, value)
+		headers.Set(key, value)
 	}
 
 	return headers
 }
+
+// IsRetryableStatus reports whether a response status indicates a temporary failure worth retrying.
+func IsRetryableStatus(statusCode int) bool {
+	switch statusCode {
+	case http.StatusServiceUnavailable, http.StatusGatewayTimeout, http.StatusTooManyRequests, http.StatusRequestTimeout:
+		return true
+	}
+
+	return false
+}
+
+// ParseRetryAfter parses a Retry-After header value relative to now.
+// Both the delay-seconds and HTTP-date forms are accepted; dates in the past yield zero.
+// The second result is false when the value is empty or malformed.
+func ParseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	date, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+
+	return max(date.Sub(now), 0), true
+}
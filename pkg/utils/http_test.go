@@ -0,0 +1,33 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 11, 26, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		value  string
+		want   time.Duration
+		wantOK bool
+	}{
+		{"seconds", "120", 2 * time.Minute, true},
+		{"http date", "Wed, 26 Nov 2025 12:00:30 GMT", 30 * time.Second, true},
+		{"date in the past", "Wed, 26 Nov 2025 11:00:00 GMT", 0, true},
+		{"empty", "", 0, false},
+		{"negative", "-5", 0, false},
+		{"malformed", "soon", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ParseRetryAfter(tt.value, now)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("ParseRetryAfter(%q) = (%v, %v), want (%v, %v)", tt.value, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
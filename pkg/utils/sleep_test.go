@@ -0,0 +1,30 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSleepContext(t *testing.T) {
+	if err := SleepContext(context.Background(), time.Millisecond); err != nil {
+		t.Errorf("SleepContext() = %v, want nil", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	start := time.Now()
+	if err := SleepContext(ctx, time.Minute); !errors.Is(err, context.Canceled) {
+		t.Errorf("SleepContext(cancelled) = %v, want context.Canceled", err)
+	}
+
+	if time.Since(start) > time.Second {
+		t.Error("SleepContext(cancelled) waited out the delay")
+	}
+
+	if err := SleepContext(ctx, 0); !errors.Is(err, context.Canceled) {
+		t.Errorf("SleepContext(cancelled, 0) = %v, want context.Canceled", err)
+	}
+}
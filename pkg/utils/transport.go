@@ -0,0 +1,182 @@
+package utils
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/andybalholm/brotli"
+)
+
+// ErrDecodedTooLarge indicates a compressed response that expands past the decoding limit.
+var ErrDecodedTooLarge = errors.New("decoded response exceeds size limit")
+
+// AcceptEncoding is the compression negotiated by DecodingTransport.
+const AcceptEncoding = "gzip, br"
+
+// TransportOptions tunes the connection pool of an HTTP transport.
+type TransportOptions struct {
+	// DialContext replaces the default dialer, e.g. to restrict the addresses reached.
+	DialContext func(ctx context.Context, network, addr string) (net.Conn, error)
+	// Proxy selects the proxy for a request; nil connects directly.
+	Proxy func(*http.Request) (*url.URL, error)
+	// MaxIdleConns caps idle keep-alive connections across all hosts.
+	MaxIdleConns int
+	// MaxIdleConnsPerHost caps idle keep-alive connections to one host.
+	MaxIdleConnsPerHost int
+	// IdleConnTimeout closes keep-alive connections unused for this long.
+	IdleConnTimeout time.Duration
+}
+
+// DefaultTransportOptions returns pool settings sized for many small requests to a few hosts,
+// such as an upload issuing hundreds of GraphQL mutations to one CMS.
+func DefaultTransportOptions() TransportOptions {
+	return TransportOptions{
+		Proxy:               http.ProxyFromEnvironment,
+		MaxIdleConns:        100,
+		MaxIdleConnsPerHost: 16,
+		IdleConnTimeout:     90 * time.Second,
+	}
+}
+
+// NewTransport builds a keep-alive, HTTP/2 capable transport. Its own transparent gzip is
+// disabled so that DecodingTransport can negotiate and bound every encoding the same way.
+func NewTransport(opts TransportOptions) *http.Transport {
+	dial := opts.DialContext
+	if dial == nil {
+		dial = (&net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}).DialContext
+	}
+
+	return &http.Transport{
+		Proxy:                 opts.Proxy,
+		DialContext:           dial,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          opts.MaxIdleConns,
+		MaxIdleConnsPerHost:   opts.MaxIdleConnsPerHost,
+		IdleConnTimeout:       opts.IdleConnTimeout,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+		DisableCompression:    true,
+	}
+}
+
+// SharedTransport returns the process-wide transport built from DefaultTransportOptions,
+// so every client without special dialing needs shares one connection pool.
+var SharedTransport = sync.OnceValue(func() *http.Transport {
+	return NewTransport(DefaultTransportOptions())
+})
+
+// DecodingTransport asks for gzip or brotli and decodes the response body transparently.
+// A decoded response has no Content-Encoding or Content-Length and is marked Uncompressed.
+type DecodingTransport struct {
+	// Base performs the request; nil uses SharedTransport.
+	Base http.RoundTripper
+	// MaxDecodedBytes fails body reads with ErrDecodedTooLarge past this size; 0 is unlimited.
+	MaxDecodedBytes int64
+}
+
+// NewDecodingTransport wraps base with gzip and brotli decoding bounded by maxDecodedBytes.
+func NewDecodingTransport(base http.RoundTripper, maxDecodedBytes int64) *DecodingTransport {
+	return &DecodingTransport{Base: base, MaxDecodedBytes: maxDecodedBytes}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *DecodingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = SharedTransport()
+	}
+
+	// Leave callers that negotiate encodings themselves alone
+	if req.Header.Get("Accept-Encoding") != "" {
+		return base.RoundTrip(req)
+	}
+
+	req = req.Clone(req.Context())
+	req.Header.Set("Accept-Encoding", AcceptEncoding)
+
+	resp, err := base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Method == http.MethodHead || resp.StatusCode == http.StatusNoContent || resp.StatusCode == http.StatusNotModified {
+		return resp, nil
+	}
+
+	var decoded io.Reader
+
+	switch strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding"))) {
+	case "gzip", "x-gzip":
+		decoded = &lazyGzipReader{body: resp.Body}
+	case "br":
+		decoded = brotli.NewReader(resp.Body)
+	default:
+		return resp, nil
+	}
+
+	resp.Body = &decodedBody{reader: decoded, body: resp.Body, limit: t.MaxDecodedBytes}
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+
+	return resp, nil
+}
+
+// lazyGzipReader defers reading the gzip header to the first Read, so RoundTrip never blocks on the body.
+type lazyGzipReader struct {
+	body io.Reader
+	zr   *gzip.Reader
+	err  error
+}
+
+// Read implements io.Reader.
+func (r *lazyGzipReader) Read(p []byte) (int, error) {
+	if r.zr == nil && r.err == nil {
+		r.zr, r.err = gzip.NewReader(r.body)
+	}
+
+	if r.err != nil {
+		return 0, fmt.Errorf("invalid gzip body: %w", r.err)
+	}
+
+	return r.zr.Read(p)
+}
+
+// decodedBody reads the decoded stream, closes the raw body and enforces the size limit.
+type decodedBody struct {
+	reader io.Reader
+	body   io.ReadCloser
+	limit  int64
+	read   int64
+}
+
+// Read implements io.Reader.
+func (b *decodedBody) Read(p []byte) (int, error) {
+	if b.limit > 0 && int64(len(p)) > b.limit-b.read+1 {
+		p = p[:b.limit-b.read+1]
+	}
+
+	n, err := b.reader.Read(p)
+	b.read += int64(n)
+
+	if b.limit > 0 && b.read > b.limit {
+		return n, fmt.Errorf("%w: more than %d bytes", ErrDecodedTooLarge, b.limit)
+	}
+
+	return n, err
+}
+
+// Close implements io.Closer.
+func (b *decodedBody) Close() error {
+	return b.body.Close()
+}
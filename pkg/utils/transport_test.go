@@ -0,0 +1,133 @@
+package utils
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+)
+
+// compressedServer serves body encoded as the client asked for, recording the negotiated encoding.
+func compressedServer(t *testing.T, body string, accepted *string) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*accepted = r.Header.Get("Accept-Encoding")
+
+		var buf bytes.Buffer
+
+		switch r.URL.Query().Get("encoding") {
+		case "gzip":
+			zw := gzip.NewWriter(&buf)
+			_, _ = zw.Write([]byte(body))
+			_ = zw.Close()
+		case "br":
+			bw := brotli.NewWriter(&buf)
+			_, _ = bw.Write([]byte(body))
+			_ = bw.Close()
+		default:
+			buf.WriteString(body)
+		}
+
+		if encoding := r.URL.Query().Get("encoding"); encoding != "" {
+			w.Header().Set("Content-Encoding", encoding)
+		}
+
+		_, _ = w.Write(buf.Bytes())
+	}))
+}
+
+func TestDecodingTransport(t *testing.T) {
+	body := strings.Repeat("| 2025-11-26 | 14:51 | Fire reported |\n", 200)
+
+	var accepted string
+
+	server := compressedServer(t, body, &accepted)
+	defer server.Close()
+
+	client := &http.Client{Transport: NewDecodingTransport(NewTransport(DefaultTransportOptions()), 0)}
+
+	for _, encoding := range []string{"", "gzip", "br"} {
+		t.Run("encoding="+encoding, func(t *testing.T) {
+			resp, err := client.Get(server.URL + "?encoding=" + encoding)
+			if err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+			defer resp.Body.Close()
+
+			got, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatalf("ReadAll() error = %v", err)
+			}
+
+			if string(got) != body {
+				t.Errorf("body mismatch: got %d bytes, want %d", len(got), len(body))
+			}
+
+			if accepted != AcceptEncoding {
+				t.Errorf("Accept-Encoding = %q, want %q", accepted, AcceptEncoding)
+			}
+
+			if encoding != "" && (resp.Header.Get("Content-Encoding") != "" || !resp.Uncompressed) {
+				t.Errorf("decoded response still marked as %q", resp.Header.Get("Content-Encoding"))
+			}
+		})
+	}
+}
+
+func TestDecodingTransport_MaxDecodedBytes(t *testing.T) {
+	// Highly compressible, so the encoded response is far below the limit
+	body := strings.Repeat("x", 64*1024)
+
+	var accepted string
+
+	server := compressedServer(t, body, &accepted)
+	defer server.Close()
+
+	client := &http.Client{Transport: NewDecodingTransport(nil, 1024)}
+
+	for _, encoding := range []string{"gzip", "br"} {
+		resp, err := client.Get(server.URL + "?encoding=" + encoding)
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+
+		_, err = io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+
+		if !errors.Is(err, ErrDecodedTooLarge) {
+			t.Errorf("%s: ReadAll() error = %v, want %v", encoding, err, ErrDecodedTooLarge)
+		}
+	}
+}
+
+func TestDecodingTransport_CallerEncoding(t *testing.T) {
+	var accepted string
+
+	server := compressedServer(t, "raw", &accepted)
+	defer server.Close()
+
+	req, err := http.NewRequest(http.MethodGet, server.URL+"?encoding=gzip", http.NoBody)
+	if err != nil {
+		t.Fatalf("NewRequest() error = %v", err)
+	}
+
+	req.Header.Set("Accept-Encoding", "gzip")
+
+	resp, err := (&http.Client{Transport: NewDecodingTransport(nil, 0)}).Do(req)
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	defer resp.Body.Close()
+
+	// The caller negotiated the encoding itself, so the body is left compressed
+	if resp.Header.Get("Content-Encoding") != "gzip" {
+		t.Errorf("Content-Encoding = %q, want gzip", resp.Header.Get("Content-Encoding"))
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"tpwfc/internal/crawler/parsers"
+	"tpwfc/internal/normalizer"
 )
 
 func TestNormalizer_DetailedTimeline(t *testing.T) {
@@ -54,3 +55,44 @@ func TestNormalizer_DetailedTimeline(t *testing.T) {
 		t.Errorf("Expected tracking event 'Tracking Event', got '%s'", doc.LongTermTracking[0].Event)
 	}
 }
+
+func TestNormalizer_InvestigationAndResponses(t *testing.T) {
+	parser := parsers.NewParser()
+	validator := normalizer.NewValidator()
+
+	content, err := os.ReadFile(filepath.Join("..", "fixtures", "investigation.md"))
+	if err != nil {
+		t.Fatalf("Failed to read fixture: %v", err)
+	}
+
+	investigation, diags, err := parser.ParseInvestigationWithDiagnostics(string(content))
+	if err != nil || len(diags) != 0 {
+		t.Fatalf("ParseInvestigationWithDiagnostics: err=%v, diagnostics=%v", err, diags)
+	}
+
+	if len(investigation.Findings) != 3 || investigation.Findings[1].Finding != "Foam boards sealed windows | lobbies" {
+		t.Errorf("Unexpected findings: %+v", investigation.Findings)
+	}
+
+	if err := validator.Validate(investigation); err != nil {
+		t.Errorf("Validate(investigation) = %v", err)
+	}
+
+	content, err = os.ReadFile(filepath.Join("..", "fixtures", "responses.md"))
+	if err != nil {
+		t.Fatalf("Failed to read fixture: %v", err)
+	}
+
+	responses, diags, err := parser.ParseResponsesWithDiagnostics(string(content))
+	if err != nil || len(diags) != 0 {
+		t.Fatalf("ParseResponsesWithDiagnostics: err=%v, diagnostics=%v", err, diags)
+	}
+
+	if len(responses.Responses) != 3 || responses.Responses[2].ResponseType != "INQUIRY" {
+		t.Errorf("Unexpected responses: %+v", responses.Responses)
+	}
+
+	if err := validator.Validate(responses); err != nil {
+		t.Errorf("Validate(responses) = %v", err)
+	}
+}